@@ -0,0 +1,36 @@
+package v1
+
+import (
+	"context"
+	schedulermodels "github.com/SneaksAndData/nexus/api/v1/models"
+	nexusv1 "github.com/SneaksAndData/nexus/proto/nexus/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// CancelRun interrupts the provided run and cancels the execution tree if it exists
+func (server *SchedulerServer) CancelRun(_ context.Context, in *nexusv1.CancelRunRequest) (*nexusv1.CancelRunResponse, error) {
+	cancellation := schedulermodels.CancellationRequest{
+		Reason:             in.GetReason(),
+		Initiator:          in.GetInitiator(),
+		CancellationPolicy: in.GetCancellationPolicy(),
+	}
+
+	policy, err := cancellation.GetPolicy()
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, `Invalid cancellation request: %s`, err.Error())
+	}
+
+	exists, err := server.scheduler.CancelRun(in.GetRequestId(), in.GetAlgorithmName(), cancellation.Initiator, cancellation.Reason, *policy)
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, `Provided request with identifier '%s' not found`, in.GetRequestId())
+	}
+
+	if err != nil && !errors.IsNotFound(err) {
+		server.logger.V(0).Error(err, "error when cancelling a run", "algorithm", in.GetAlgorithmName(), "request", in.GetRequestId())
+		return nil, status.Error(codes.Internal, `Unhandled error when executing a run cancellation. Please try again later`)
+	}
+
+	return &nexusv1.CancelRunResponse{}, nil
+}
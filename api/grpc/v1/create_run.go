@@ -0,0 +1,51 @@
+package v1
+
+import (
+	"context"
+	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
+	nexusv1 "github.com/SneaksAndData/nexus/proto/nexus/v1"
+	"github.com/SneaksAndData/nexus/services"
+	"github.com/gin-gonic/gin/binding"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CreateRun accepts an algorithm payload and places it into a scheduling queue
+func (server *SchedulerServer) CreateRun(ctx context.Context, in *nexusv1.CreateRunRequest) (*nexusv1.CreateRunResponse, error) {
+	payload := models.AlgorithmRequest{}
+	if err := services.FromProtoAlgorithmRequest(in.GetRequest(), &payload); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, `Algorithm payload is invalid: %s`, err.Error())
+	}
+
+	// requests are validated with the same rules as REST API payloads
+	if err := binding.Validator.ValidateStruct(&payload); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, `Algorithm payload is invalid: %s`, err.Error())
+	}
+
+	if in.GetTemplateRevision() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, `Template revision must be a positive integer, but got: %d`, in.GetTemplateRevision())
+	}
+
+	submission := &services.RunSubmission{
+		AlgorithmName:    in.GetAlgorithmName(),
+		Request:          &payload,
+		DryRun:           in.GetDryRun(),
+		PayloadUploadId:  in.GetPayloadUploadId(),
+		TemplateRevision: in.GetTemplateRevision(),
+		RoutingKey:       in.GetRoutingKey(),
+	}
+
+	if in.GetMaxQueueTime() != nil {
+		if err := in.GetMaxQueueTime().CheckValid(); err != nil || in.GetMaxQueueTime().AsDuration() <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, `Maximum queue time must be a positive duration, but got: %s`, in.GetMaxQueueTime().String())
+		}
+		submission.MaxQueueTime = in.GetMaxQueueTime().AsDuration()
+	}
+
+	requestId, err := server.submitter.Submit(ctx, submission)
+	if err != nil {
+		return nil, submissionStatus(err)
+	}
+
+	return &nexusv1.CreateRunResponse{RequestId: requestId}, nil
+}
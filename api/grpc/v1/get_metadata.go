@@ -0,0 +1,42 @@
+package v1
+
+import (
+	"context"
+	coremodels "github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
+	"github.com/SneaksAndData/nexus/api/v1/models"
+	nexusv1 "github.com/SneaksAndData/nexus/proto/nexus/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetRunMetadata retrieves checkpointed metadata for a run
+func (server *SchedulerServer) GetRunMetadata(_ context.Context, in *nexusv1.GetRunMetadataRequest) (*nexusv1.RunMetadata, error) {
+	result, err := server.buffer.Get(in.GetRequestId(), in.GetAlgorithmName())
+	if err != nil {
+		server.logger.V(0).Error(err, "failed to read run metadata", "algorithm", in.GetAlgorithmName(), "request", in.GetRequestId())
+		return nil, status.Errorf(codes.Internal, `Failed to read metadata for %s`, in.GetRequestId())
+	}
+
+	if result == nil {
+		return nil, status.Errorf(codes.NotFound, `Provided request with identifier '%s' not found`, in.GetRequestId())
+	}
+
+	return server.runMetadata(result)
+}
+
+// runMetadata combines a checkpoint with scheduler attributes, the same way as the REST API
+func (server *SchedulerServer) runMetadata(checkpoint *coremodels.CheckpointedRequest) (*nexusv1.RunMetadata, error) {
+	attributes, err := server.attributeStore.ReadAttributes(checkpoint.Algorithm, checkpoint.Id)
+	if err != nil {
+		server.logger.V(0).Error(err, "failed to read run attributes", "algorithm", checkpoint.Algorithm, "request", checkpoint.Id)
+		return nil, status.Errorf(codes.Internal, `Failed to read metadata for %s`, checkpoint.Id)
+	}
+
+	metadata, err := newRunMetadata(models.NewRunMetadata(checkpoint, attributes))
+	if err != nil { // coverage-ignore
+		server.logger.V(0).Error(err, "failed to convert run metadata", "algorithm", checkpoint.Algorithm, "request", checkpoint.Id)
+		return nil, status.Errorf(codes.Internal, `Failed to read metadata for %s`, checkpoint.Id)
+	}
+
+	return metadata, nil
+}
@@ -0,0 +1,45 @@
+package v1
+
+import (
+	"context"
+	"github.com/SneaksAndData/nexus/api/v1/models"
+	nexusv1 "github.com/SneaksAndData/nexus/proto/nexus/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetRunResult retrieves a result for the provided run
+func (server *SchedulerServer) GetRunResult(_ context.Context, in *nexusv1.GetRunResultRequest) (*nexusv1.RunResult, error) {
+	result, err := server.buffer.Get(in.GetRequestId(), in.GetAlgorithmName())
+	if err != nil {
+		server.logger.V(0).Error(err, "failed to read a run result", "algorithm", in.GetAlgorithmName(), "request", in.GetRequestId())
+		return nil, status.Errorf(codes.Internal, `Failed to read results for %s`, in.GetRequestId())
+	}
+
+	if result == nil {
+		return nil, status.Errorf(codes.NotFound, `Provided request with identifier '%s' not found`, in.GetRequestId())
+	}
+
+	return newRunResult(in.GetAlgorithmName(), models.FromCheckpointedRequest(result)), nil
+}
+
+// GetRunsByTag reads results of all runs with a matching tag
+func (server *SchedulerServer) GetRunsByTag(_ context.Context, in *nexusv1.GetRunsByTagRequest) (*nexusv1.GetRunsByTagResponse, error) {
+	tag, err := server.scheduler.ScopedTag(in.GetTenant(), in.GetTag())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, `Request tag is invalid: %s`, err.Error())
+	}
+
+	results, err := server.buffer.GetTagged(tag)
+	if err != nil {
+		server.logger.V(0).Error(err, "failed to read tagged results", "tag", tag)
+		return nil, status.Errorf(codes.Internal, `Failed to read tagged results for %s`, in.GetTag())
+	}
+
+	response := &nexusv1.GetRunsByTagResponse{Results: []*nexusv1.RunResult{}}
+	for result := range results {
+		response.Results = append(response.Results, newTaggedRunResult(models.NewTaggedRequestResult(result)))
+	}
+
+	return response, nil
+}
@@ -0,0 +1,46 @@
+package v1
+
+import (
+	"encoding/json"
+	"github.com/SneaksAndData/nexus/api/v1/models"
+	nexusv1 "github.com/SneaksAndData/nexus/proto/nexus/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// newRunResult converts a REST API run result into a RunResult message
+func newRunResult(algorithmName string, result *models.RequestResult) *nexusv1.RunResult {
+	return &nexusv1.RunResult{
+		RequestId:       result.RequestId,
+		AlgorithmName:   algorithmName,
+		Status:          result.Status,
+		ResultUri:       result.ResultUri,
+		RunErrorMessage: result.RunErrorMessage,
+	}
+}
+
+// newTaggedRunResult converts a REST API tagged run result into a RunResult message
+func newTaggedRunResult(result *models.TaggedRequestResult) *nexusv1.RunResult {
+	return &nexusv1.RunResult{
+		RequestId:       result.RequestId,
+		AlgorithmName:   result.AlgorithmName,
+		Status:          result.Status,
+		ResultUri:       result.ResultUri,
+		RunErrorMessage: result.RunErrorMessage,
+	}
+}
+
+// newRunMetadata converts REST API run metadata into a RunMetadata message
+func newRunMetadata(metadata *models.RunMetadata) (*nexusv1.RunMetadata, error) {
+	// message fields are named after JSON fields of the REST model, so the JSON mapping of the message reads it as is
+	serialized, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	message := &nexusv1.RunMetadata{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(serialized, message); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
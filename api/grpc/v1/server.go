@@ -0,0 +1,48 @@
+package v1
+
+import (
+	"errors"
+	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/request"
+	nexusv1 "github.com/SneaksAndData/nexus/proto/nexus/v1"
+	"github.com/SneaksAndData/nexus/services"
+	"github.com/SneaksAndData/nexus/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+	"time"
+)
+
+// SchedulerServer implements the nexus.v1.Scheduler gRPC service on top of the same services as the REST API
+type SchedulerServer struct {
+	nexusv1.UnimplementedSchedulerServer
+	buffer         request.Buffer
+	attributeStore storage.AttributeStore
+	scheduler      *services.RequestScheduler
+	submitter      *services.RunSubmitter
+	watchInterval  time.Duration
+	logger         klog.Logger
+}
+
+// NewSchedulerServer creates a SchedulerServer
+func NewSchedulerServer(buffer request.Buffer, attributeStore storage.AttributeStore, scheduler *services.RequestScheduler, submitter *services.RunSubmitter, watchInterval time.Duration, logger klog.Logger) *SchedulerServer {
+	return &SchedulerServer{
+		buffer:         buffer,
+		attributeStore: attributeStore,
+		scheduler:      scheduler,
+		submitter:      submitter,
+		watchInterval:  watchInterval,
+		logger:         logger,
+	}
+}
+
+// submissionStatus maps a SubmissionError to a gRPC status
+func submissionStatus(err error) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidSubmission), errors.Is(err, services.ErrUnsupportedContentType):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, services.ErrPayloadTooLarge), errors.Is(err, services.ErrQuotaExceeded):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,38 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	coremodels "github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
+	nexusv1 "github.com/SneaksAndData/nexus/proto/nexus/v1"
+	"github.com/SneaksAndData/nexus/services"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WatchRun streams run metadata every time the run changes, and completes once the run has finished
+func (server *SchedulerServer) WatchRun(in *nexusv1.WatchRunRequest, stream grpc.ServerStreamingServer[nexusv1.RunMetadata]) error {
+	err := server.scheduler.WatchRun(stream.Context(), in.GetRequestId(), in.GetAlgorithmName(), server.watchInterval, func(checkpoint *coremodels.CheckpointedRequest) error {
+		metadata, err := server.runMetadata(checkpoint)
+		if err != nil {
+			return err
+		}
+
+		return stream.Send(metadata)
+	})
+
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, services.ErrRunNotFound):
+		return status.Errorf(codes.NotFound, `Provided request with identifier '%s' not found`, in.GetRequestId())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	case status.Code(err) != codes.Unknown:
+		return err
+	default:
+		server.logger.V(0).Error(err, "failed to watch a run", "algorithm", in.GetAlgorithmName(), "request", in.GetRequestId())
+		return status.Errorf(codes.Internal, `Failed to read metadata for %s`, in.GetRequestId())
+	}
+}
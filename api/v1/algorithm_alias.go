@@ -0,0 +1,37 @@
+package v1
+
+import (
+	"fmt"
+	"github.com/SneaksAndData/nexus/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// AlgorithmAliasHeaders adds Deprecation (RFC 9745) and Sunset (RFC 8594) headers to responses of requests that address an algorithm via a deprecated alias, and records calls made via aliases
+func AlgorithmAliasHeaders(configCache *services.NexusResourceCache) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		algorithmName := ctx.Param("algorithmName")
+		if algorithmName == "" {
+			ctx.Next()
+			return
+		}
+
+		template, alias, err := configCache.ResolveAlgorithmAlias(algorithmName)
+		if err != nil || alias == nil {
+			ctx.Next()
+			return
+		}
+
+		configCache.RecordAliasCall(alias, configCache.RunName(template))
+
+		if alias.Deprecation != nil {
+			ctx.Header("Deprecation", fmt.Sprintf("@%d", alias.Deprecation.Unix()))
+		}
+
+		if alias.Sunset != nil {
+			ctx.Header("Sunset", alias.Sunset.UTC().Format(http.TimeFormat))
+		}
+
+		ctx.Next()
+	}
+}
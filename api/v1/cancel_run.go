@@ -12,7 +12,7 @@ import (
 // CancelRun godoc
 //
 //	@Summary		Cancels an algorithm run
-//	@Description	Interrupts the provided run id and cancels the execution tree if it exists
+//	@Description	Interrupts the provided run id and cancels the execution tree if it exists. Descendant runs are cancelled as well, unless Orphan cancellation policy is used. Runs that are still waiting for submission (NEW or BUFFERED) are cancelled before a Job is created.
 //	@Tags			cancellation
 //	@Accept			json
 //	@Produce		json
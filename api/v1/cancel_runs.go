@@ -0,0 +1,132 @@
+package v1
+
+import (
+	schedulermodels "github.com/SneaksAndData/nexus/api/v1/models"
+	"github.com/SneaksAndData/nexus/services"
+	servicemodels "github.com/SneaksAndData/nexus/services/models"
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"net/http"
+)
+
+type bulkCancellation func(target string, lifecycleStages []string, initiator string, reason string, policy metav1.DeletionPropagation) (*servicemodels.CancellationOperation, error)
+
+func startBulkCancellation(target string, cancel bulkCancellation, logger klog.Logger, ctx *gin.Context) {
+	payload := schedulermodels.BulkCancellationRequest{}
+
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		ctx.String(http.StatusBadRequest, `Cancellation payload is invalid: %s`, err.Error())
+		return
+	}
+
+	policy, err := payload.GetPolicy()
+
+	if err != nil {
+		ctx.String(http.StatusBadRequest, `Invalid cancellation request: %s`, err.Error())
+		return
+	}
+
+	stages, err := payload.GetLifecycleStages()
+
+	if err != nil {
+		ctx.String(http.StatusBadRequest, `Invalid cancellation request: %s`, err.Error())
+		return
+	}
+
+	operation, err := cancel(target, stages, payload.Initiator, payload.Reason, *policy)
+
+	if err != nil {
+		ctx.String(http.StatusInternalServerError, `Unhandled error when starting a bulk cancellation. Please try again later`)
+		logger.V(0).Error(err, "error when starting a bulk cancellation", "target", target)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, map[string]string{
+		"operationId": operation.Id,
+	})
+}
+
+// CancelTaggedRuns godoc
+//
+//	@Summary		Cancels all runs with the provided tag
+//	@Description	Starts an asynchronous cancellation of all runs sharing the provided tag, within the provided tenant. By default, BUFFERED and RUNNING runs are cancelled. Progress can be tracked using the returned operation identifier.
+//	@Tags			cancellation
+//	@Accept			json
+//	@Produce		json
+//	@Produce		plain
+//	@Produce		html
+//	@Param			requestTag	path		string	true	"Request tag"
+//	@Param			tenant		query		string	false	"Tenant of algorithms the runs were submitted to. Not authenticated: tenants namespace tags, they do not isolate runs from callers of other tenants"
+//	@Param			payload	body		schedulermodels.BulkCancellationRequest	true	"Cancellation configuration"
+//	@Success		202	{object}	map[string]string
+//	@Failure		400	{string}	string
+//	@Failure		500	{string}	string
+//	@Failure		401	{string}	string
+//	@Router			/algorithm/v1/cancel/tags/{requestTag} [post]
+func CancelTaggedRuns(scheduler *services.RequestScheduler, logger klog.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tag, err := scheduler.ScopedTag(ctx.Query("tenant"), ctx.Param("requestTag"))
+		if err != nil {
+			ctx.String(http.StatusBadRequest, `Request tag is invalid: %s`, err.Error())
+			return
+		}
+
+		startBulkCancellation(tag, scheduler.CancelTaggedRuns, logger, ctx)
+	}
+}
+
+// CancelAlgorithmRuns godoc
+//
+//	@Summary		Cancels all runs of an algorithm
+//	@Description	Starts an asynchronous cancellation of all algorithm runs in the provided lifecycle stages. By default, BUFFERED and RUNNING runs are cancelled. Progress can be tracked using the returned operation identifier.
+//	@Tags			cancellation
+//	@Accept			json
+//	@Produce		json
+//	@Produce		plain
+//	@Produce		html
+//	@Param			algorithmName	path		string	true	"Algorithm name"
+//	@Param			payload	body		schedulermodels.BulkCancellationRequest	true	"Cancellation configuration"
+//	@Success		202	{object}	map[string]string
+//	@Failure		400	{string}	string
+//	@Failure		500	{string}	string
+//	@Failure		401	{string}	string
+//	@Router			/algorithm/v1/cancel/{algorithmName}/requests [post]
+func CancelAlgorithmRuns(scheduler *services.RequestScheduler, logger klog.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		startBulkCancellation(ctx.Param("algorithmName"), scheduler.CancelAlgorithmRuns, logger, ctx)
+	}
+}
+
+// GetCancellationOperation godoc
+//
+//	@Summary		Read a bulk cancellation progress
+//	@Description	Retrieves the status and progress of a bulk cancellation
+//	@Tags			cancellation
+//	@Produce		json
+//	@Produce		plain
+//	@Produce		html
+//	@Param			operationId	path		string	true	"Cancellation operation identifier"
+//	@Success		200	{object}	servicemodels.CancellationOperation
+//	@Failure		400	{string}	string
+//	@Failure		404	{string}	string
+//	@Failure		401	{string}	string
+//	@Router			/algorithm/v1/cancel/operations/{operationId} [get]
+func GetCancellationOperation(scheduler *services.RequestScheduler) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		operationId := ctx.Param("operationId")
+		operation, err := scheduler.GetCancellationOperation(operationId)
+
+		if err != nil {
+			ctx.String(http.StatusBadRequest, `Failed to read cancellation operation %s`, operationId)
+			return
+		}
+
+		if operation == nil {
+			ctx.String(http.StatusNotFound, "")
+			return
+		}
+
+		ctx.JSON(http.StatusOK, operation)
+	}
+}
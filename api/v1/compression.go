@@ -0,0 +1,110 @@
+package v1
+
+import (
+	"errors"
+	"github.com/SneaksAndData/nexus/services"
+	"github.com/gin-gonic/gin"
+	"io"
+	"k8s.io/klog/v2"
+	"net/http"
+	"strings"
+)
+
+// DecompressRequest decodes gzip and zstd request bodies and limits the decoded body to maxSize bytes, so a small compressed body cannot expand beyond the payload limit
+func DecompressRequest(maxSize int64) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		encoding := ctx.GetHeader("Content-Encoding")
+		decoder, err := services.NewDecoder(encoding, ctx.Request.Body)
+		switch {
+		case errors.Is(err, services.ErrUnsupportedEncoding):
+			ctx.String(http.StatusUnsupportedMediaType, `Content encoding %s is not supported. Use gzip or zstd`, encoding)
+			ctx.Abort()
+			return
+		case err != nil:
+			ctx.String(http.StatusBadRequest, `Request body is not valid %s: %s`, encoding, err.Error())
+			ctx.Abort()
+			return
+		}
+
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, decoder, maxSize)
+		ctx.Request.Header.Del("Content-Encoding")
+		ctx.Request.Header.Del("Content-Length")
+		ctx.Request.ContentLength = -1
+
+		ctx.Next()
+	}
+}
+
+// isBodyTooLarge returns true if reading a request body failed because it exceeds the maximum payload size
+func isBodyTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
+
+// compressingWriter compresses JSON responses, deciding on the first write when status and content type are known
+type compressingWriter struct {
+	gin.ResponseWriter
+	encoding string
+	minSize  int
+	encoder  io.WriteCloser
+	decided  bool
+	logger   klog.Logger
+}
+
+func (w *compressingWriter) decide(size int) {
+	w.decided = true
+	header := w.Header()
+
+	if !strings.HasPrefix(header.Get("Content-Type"), "application/json") || header.Get("Content-Encoding") != "" || header.Get("Content-Range") != "" || size < w.minSize {
+		return
+	}
+
+	encoder, err := services.NewEncoder(w.encoding, w.ResponseWriter)
+	if err != nil { // coverage-ignore
+		w.logger.V(0).Error(err, "unable to compress a response", "encoding", w.encoding)
+		return
+	}
+
+	header.Set("Content-Encoding", w.encoding)
+	header.Del("Content-Length")
+	w.encoder = encoder
+}
+
+func (w *compressingWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.decide(len(data))
+	}
+
+	if w.encoder == nil {
+		return w.ResponseWriter.Write(data)
+	}
+
+	return w.encoder.Write(data)
+}
+
+func (w *compressingWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// CompressResponse compresses JSON responses of at least minSize bytes with gzip or zstd, if accepted by the client
+func CompressResponse(minSize int64, logger klog.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Writer.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := services.NegotiateEncoding(ctx.GetHeader("Accept-Encoding"))
+		if encoding == "" || ctx.Request.Method == http.MethodHead {
+			ctx.Next()
+			return
+		}
+
+		writer := &compressingWriter{ResponseWriter: ctx.Writer, encoding: encoding, minSize: int(minSize), logger: logger}
+		ctx.Writer = writer
+		ctx.Next()
+
+		if writer.encoder != nil {
+			if err := writer.encoder.Close(); err != nil { // coverage-ignore
+				logger.V(0).Error(err, "unable to complete a compressed response", "encoding", encoding)
+			}
+		}
+	}
+}
@@ -1,103 +1,115 @@
 package v1
 
 import (
+	"errors"
 	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
-	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/request"
 	"github.com/SneaksAndData/nexus/services"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/klog/v2"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 // CreateRun godoc
 //
 //	@Summary		Create a new algorithm run
-//	@Description	Accepts an algorithm payload and places it into a scheduling queue
+//	@Description	Accepts an algorithm payload and places it into a scheduling queue. Templates with the result cache enabled complete identical submissions immediately with a result of a previous run. MessagePack, Protobuf (proto/nexus/v1/algorithm_request.proto) and binary payloads are stored in their original format and passed to the algorithm in the payloadReference parameter
 //	@Tags			run
 //	@Accept			json
+//	@Accept			application/msgpack
+//	@Accept			application/x-protobuf
+//	@Accept			octet-stream
 //	@Produce		json
 //	@Produce		plain
 //	@Produce		html
-//	@Param			algorithmName	path		string	true	"Algorithm name"
+//	@Param			algorithmName	path		string	true	"Algorithm name. Algorithms of a tenant are addressed as tenant/algorithm, with the slash URL-encoded"
 //	@Param			payload	body	models.AlgorithmRequest	true	"Run configuration"
 //	@Param			dryRun	query	string	false	"If false, will buffer but not submit to the target cluster"
+//	@Param			maxQueueTime	query	string	false	"Maximum time the run may wait in the queue before it is moved to DEADLINE_EXCEEDED, for example 10m. Cannot extend the limit set by the algorithm template"
+//	@Param			payloadUploadId	query	string	false	"Upload id of a payload uploaded in advance. Algorithm receives a pre-signed URL to it in the payloadReference parameter"
+//	@Param			templateRevision	query	int	false	"Recorded revision of the algorithm template to create the run from, instead of the current one"
+//	@Param			routingKey	query	string	false	"Runs with the same routing key are routed to the same variant of an algorithm with a traffic split"
+//	@Param			Content-Encoding	header	string	false	"Encoding of a compressed payload, gzip or zstd"
+//	@Param			Nexus-Algorithm-Parameters	header	string	false	"Algorithm parameters as a JSON object, for application/octet-stream payloads"
+//	@Param			Nexus-Tag	header	string	false	"Run tag, for application/octet-stream payloads"
+//	@Param			Nexus-Parent-Request	header	string	false	"Parent run as algorithmName/requestId, for application/octet-stream payloads"
+//	@Param			Nexus-Payload-Valid-For	header	string	false	"Payload validity period, for application/octet-stream payloads"
 //	@Success		202	{object}	map[string]string
 //	@Failure		400	{string}	string
+//	@Failure		413	{string}	string
+//	@Failure		415	{string}	string
+//	@Failure		429	{string}	string
 //	@Failure		500	{string}	string
 //	@Failure		401	{string}	string
 //	@Router			/algorithm/v1/run/{algorithmName} [post]
-func CreateRun(buffer request.Buffer, configCache *services.NexusResourceCache, scheduler *services.RequestScheduler, logger klog.Logger) gin.HandlerFunc {
+func CreateRun(submitter *services.RunSubmitter, maxPayloadSize int64) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		var parentRef *metav1.OwnerReference
-
-		algorithmName := ctx.Param("algorithmName")
 		payload := models.AlgorithmRequest{}
-		dryRun := ctx.DefaultQuery("dryRun", "false") == "true"
-		requestId := uuid.New()
 
-		if err := ctx.ShouldBindJSON(&payload); err != nil {
+		original, err := bindAlgorithmRequest(ctx, &payload, maxPayloadSize)
+		if err != nil {
+			if isBodyTooLarge(err) {
+				ctx.String(http.StatusRequestEntityTooLarge, `Algorithm payload exceeds the maximum size. Use payload upload for large payloads`)
+				return
+			}
 			ctx.String(http.StatusBadRequest, `Algorithm payload is invalid: %s`, err.Error())
 			return
 		}
 
-		config, cacheErr := configCache.GetAlgorithmConfiguration(algorithmName)
-
-		if cacheErr != nil {
-			ctx.String(http.StatusInternalServerError, `Internal error occurred when processing your request.`, algorithmName)
-			logger.V(0).Error(cacheErr, "error when retrieving algorithm template for %s/%s", algorithmName, requestId)
-			return
+		var requestMaxQueueTime time.Duration
+		if value := ctx.Query("maxQueueTime"); value != "" {
+			parsed, err := time.ParseDuration(value)
+			if err != nil || parsed <= 0 {
+				ctx.String(http.StatusBadRequest, `Maximum queue time must be a positive duration, for example 10m, but got: %s`, value)
+				return
+			}
+			requestMaxQueueTime = parsed
 		}
 
-		if config == nil {
-			ctx.String(http.StatusBadRequest, `No valid configuration found for: %s. Please check that algorithm name is spelled correctly and try again. Contact an algorithm author if this problem persists.`, algorithmName)
-			return
+		var templateRevision int64
+		if value := ctx.Query("templateRevision"); value != "" {
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || parsed <= 0 {
+				ctx.String(http.StatusBadRequest, `Template revision must be a positive integer, but got: %s`, value)
+				return
+			}
+			templateRevision = parsed
 		}
 
-		workgroup, err := configCache.GetWorkgroupConfiguration(config.Spec.WorkgroupRef.Name)
+		requestId, err := submitter.Submit(ctx, &services.RunSubmission{
+			AlgorithmName:    ctx.Param("algorithmName"),
+			Request:          &payload,
+			DryRun:           ctx.DefaultQuery("dryRun", "false") == "true",
+			MaxQueueTime:     requestMaxQueueTime,
+			PayloadUploadId:  ctx.Query("payloadUploadId"),
+			Original:         original,
+			TemplateRevision: templateRevision,
+			RoutingKey:       ctx.Query("routingKey"),
+		})
 
 		if err != nil {
-			ctx.String(http.StatusInternalServerError, `Internal error occurred when processing your request.`, algorithmName)
-			logger.V(0).Error(err, "error when retrieving algorithm workgroup configuration for %s/%s", algorithmName, requestId)
-			return
-		}
-
-		if workgroup == nil {
-			ctx.String(http.StatusBadRequest, `Cannot assign requested workgroup %s to the algorithm %s. Please check the deployed configuration.`, config.Spec.WorkgroupRef.Name, algorithmName)
-			return
-		}
-
-		if payload.ParentRequest != nil {
-			if !dryRun {
-				parentRef, err = scheduler.ResolveParent(payload.ParentRequest.RequestId, workgroup.Spec.Cluster)
-
-				if err != nil {
-					ctx.String(http.StatusInternalServerError, `Internal error occurred when processing your request.`, algorithmName)
-					logger.V(0).Error(err, "error when retrieving a parent request for %s/%s", algorithmName, requestId)
-					return
-				}
-			} else {
-				// for dry runs parent job might not exist at all, thus we create a fake reference
-				parentRef = &metav1.OwnerReference{
-					APIVersion: "batch/v1",
-					Kind:       "Job",
-					Name:       payload.ParentRequest.RequestId,
-					UID:        types.UID(payload.ParentRequest.RequestId),
-				}
-			}
-
-		}
-
-		if err := buffer.Add(requestId.String(), algorithmName, &payload, &config.Spec, &workgroup.Spec, parentRef, dryRun); err != nil {
-			ctx.String(http.StatusBadRequest, `Request buffering failed for: %s/%s`, algorithmName, requestId)
-			logger.V(0).Error(err, "error when retrieving a parent request for %s/%s", algorithmName, requestId)
+			ctx.String(submissionStatus(err), "%s", err.Error())
 			return
 		}
 
 		ctx.JSON(http.StatusAccepted, map[string]string{
-			"requestId": requestId.String(),
+			"requestId": requestId,
 		})
 	}
 }
+
+// submissionStatus maps a SubmissionError to an HTTP status code
+func submissionStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrInvalidSubmission):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrPayloadTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, services.ErrUnsupportedContentType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, services.ErrQuotaExceeded):
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,107 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
+	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/request"
+	"github.com/SneaksAndData/nexus/services"
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+	"net/http"
+)
+
+// proxyDownload streams an object referenced by a run checkpoint through the scheduler
+func proxyDownload(buffer request.Buffer, objectKind string, objectUri func(checkpoint *models.CheckpointedRequest) string, serve func(ctx context.Context, checkpoint *models.CheckpointedRequest, rangeHeader string, writer http.ResponseWriter) error, logger klog.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		algorithmName := ctx.Param("algorithmName")
+		requestId := ctx.Param("requestId")
+
+		result, err := buffer.Get(requestId, algorithmName)
+
+		if err != nil {
+			ctx.String(http.StatusBadRequest, `Failed to find a run for %s`, requestId)
+			return
+		}
+
+		if result == nil {
+			ctx.String(http.StatusNotFound, "")
+			return
+		}
+
+		if objectUri(result) == "" {
+			ctx.String(http.StatusExpectationFailed, `Specified request %s does not have a %s`, requestId, objectKind)
+			return
+		}
+
+		err = serve(ctx, result, ctx.GetHeader("Range"), ctx.Writer)
+
+		switch {
+		case err == nil:
+		case errors.Is(err, services.ErrObjectOutsideStorage):
+			ctx.String(http.StatusForbidden, `Requested %s of %s is not stored in a location that can be downloaded through the scheduler`, objectKind, requestId)
+		case errors.Is(err, services.ErrObjectNotFound):
+			ctx.String(http.StatusNotFound, `Requested %s of %s no longer exists`, objectKind, requestId)
+		case errors.Is(err, services.ErrInvalidRange):
+			ctx.String(http.StatusRequestedRangeNotSatisfiable, `Requested range of %s of %s cannot be satisfied`, objectKind, requestId)
+		case errors.Is(err, services.ErrObjectTooLarge):
+			ctx.String(http.StatusRequestEntityTooLarge, `Requested %s of %s exceeds the maximum download size. Use a Range request or the redirect endpoint instead`, objectKind, requestId)
+		case !ctx.Writer.Written():
+			ctx.String(http.StatusBadGateway, `Failed to download a %s for %s`, objectKind, requestId)
+			logger.V(0).Error(err, "error when proxying a download", "request", requestId, "algorithm", algorithmName, "object", objectKind)
+		default:
+			logger.V(0).Error(err, "proxied download interrupted", "request", requestId, "algorithm", algorithmName, "object", objectKind)
+		}
+	}
+}
+
+// DownloadRunResult godoc
+//
+//	@Summary		Download a run result
+//	@Description	Streams the result of the provided run through the scheduler, for clients that cannot reach the object store. Only results stored under the configured result storage path are served. Supports HTTP Range requests
+//	@Tags			results
+//	@Produce		octet-stream
+//	@Param			algorithmName	path		string	true	"Algorithm name"
+//	@Param			requestId	path		string	true	"Request identifier"
+//	@Param			Range	header		string	false	"Byte range to download, for example bytes=0-1023"
+//	@Success		200	{string}    string
+//	@Success		206	{string}    string
+//	@Failure		400	{string}	string
+//	@Failure		404	{string}	string
+//	@Failure		413	{string}	string
+//	@Failure		416	{string}	string
+//	@Failure		417	{string}	string
+//	@Failure		502	{string}	string
+//	@Failure		401	{string}	string
+//	@Failure		403	{string}	string
+//	@Router			/algorithm/v1/download/results/{algorithmName}/requests/{requestId} [get]
+func DownloadRunResult(buffer request.Buffer, proxy *services.ObjectProxy, logger klog.Logger) gin.HandlerFunc {
+	return proxyDownload(buffer, "result", func(checkpoint *models.CheckpointedRequest) string {
+		return checkpoint.ResultUri
+	}, proxy.ServeResult, logger)
+}
+
+// DownloadRunPayload godoc
+//
+//	@Summary		Download a run payload
+//	@Description	Streams payload sent by the client for the provided run through the scheduler, for clients that cannot reach the object store. Supports HTTP Range requests
+//	@Tags			payload
+//	@Produce		octet-stream
+//	@Param			algorithmName	path		string	true	"Algorithm name"
+//	@Param			requestId	path		string	true	"Request identifier"
+//	@Param			Range	header		string	false	"Byte range to download, for example bytes=0-1023"
+//	@Success		200	{string}    string
+//	@Success		206	{string}    string
+//	@Failure		400	{string}	string
+//	@Failure		404	{string}	string
+//	@Failure		413	{string}	string
+//	@Failure		416	{string}	string
+//	@Failure		417	{string}	string
+//	@Failure		502	{string}	string
+//	@Failure		401	{string}	string
+//	@Router			/algorithm/v1/download/payload/{algorithmName}/requests/{requestId} [get]
+func DownloadRunPayload(buffer request.Buffer, proxy *services.ObjectProxy, logger klog.Logger) gin.HandlerFunc {
+	return proxyDownload(buffer, "payload", func(checkpoint *models.CheckpointedRequest) string {
+		return checkpoint.PayloadUri
+	}, proxy.ServePayload, logger)
+}
@@ -0,0 +1,98 @@
+package v1
+
+import (
+	"github.com/SneaksAndData/nexus/services"
+	servicemodels "github.com/SneaksAndData/nexus/services/models"
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+	"net/http"
+	"strings"
+)
+
+// callerIdentity reads a caller identity set by an authenticating proxy. Returns false if callers must be authorized, but the identity is missing
+func callerIdentity(ctx *gin.Context, config *servicemodels.AlgorithmCatalogConfig) (*services.Caller, bool) {
+	caller := &services.Caller{
+		User:   ctx.GetHeader(config.UserHeader),
+		Groups: []string{},
+	}
+
+	for _, value := range ctx.Request.Header.Values(config.GroupsHeader) {
+		for _, group := range strings.Split(value, ",") {
+			if group = strings.TrimSpace(group); group != "" {
+				caller.Groups = append(caller.Groups, group)
+			}
+		}
+	}
+
+	return caller, !config.AuthorizeCallers || caller.User != ""
+}
+
+// ListAlgorithms godoc
+//
+//	@Summary		List algorithms
+//	@Description	Lists algorithms the caller is authorized to run, with their version, resources, retry behaviour and workgroup. Algorithms that reference a missing workgroup are listed with missingWorkgroup set, as their runs are rejected
+//	@Tags			algorithms
+//	@Produce		json
+//	@Produce		plain
+//	@Param			tenant	query		string	false	"List only algorithms of a tenant. Algorithms of all tenants are listed if omitted"
+//	@Success		200	{array}		servicemodels.AlgorithmCatalogEntry
+//	@Failure		401	{string}	string
+//	@Failure		500	{string}	string
+//	@Router			/algorithm/v1/algorithms [get]
+func ListAlgorithms(catalog *services.AlgorithmCatalog, config *servicemodels.AlgorithmCatalogConfig, logger klog.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		caller, ok := callerIdentity(ctx, config)
+		if !ok {
+			ctx.String(http.StatusUnauthorized, `Caller identity is required to list algorithms`)
+			return
+		}
+
+		entries, err := catalog.List(ctx, caller, ctx.Query("tenant"))
+		if err != nil {
+			logger.V(0).Error(err, "failed to list algorithms", "user", caller.User)
+			ctx.String(http.StatusInternalServerError, `Internal error occurred when processing your request.`)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, entries)
+	}
+}
+
+// GetAlgorithm godoc
+//
+//	@Summary		Read an algorithm
+//	@Description	Retrieves version, resources, retry behaviour and workgroup of an algorithm the caller is authorized to run
+//	@Tags			algorithms
+//	@Produce		json
+//	@Produce		plain
+//	@Param			algorithmName	path		string	true	"Algorithm name, or tenant/algorithm URL-encoded for algorithms of a tenant"
+//	@Success		200	{object}	servicemodels.AlgorithmCatalogEntry
+//	@Failure		401	{string}	string
+//	@Failure		404	{string}	string
+//	@Failure		500	{string}	string
+//	@Router			/algorithm/v1/algorithms/{algorithmName} [get]
+func GetAlgorithm(catalog *services.AlgorithmCatalog, config *servicemodels.AlgorithmCatalogConfig, logger klog.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		algorithmName := ctx.Param("algorithmName")
+		caller, ok := callerIdentity(ctx, config)
+		if !ok {
+			ctx.String(http.StatusUnauthorized, `Caller identity is required to read algorithms`)
+			return
+		}
+
+		entry, err := catalog.Get(ctx, caller, algorithmName)
+		if err != nil {
+			logger.V(0).Error(err, "failed to read an algorithm", "algorithm", algorithmName, "user", caller.User)
+			ctx.String(http.StatusInternalServerError, `Internal error occurred when processing your request.`)
+			return
+		}
+
+		// unauthorized callers cannot tell an algorithm they cannot see from a missing one
+		if entry == nil {
+			ctx.String(http.StatusNotFound, `Algorithm %s not found`, algorithmName)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, entry)
+	}
+}
@@ -2,6 +2,8 @@ package v1
 
 import (
 	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/request"
+	"github.com/SneaksAndData/nexus/api/v1/models"
+	"github.com/SneaksAndData/nexus/storage"
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
@@ -16,12 +18,12 @@ import (
 //	@Produce		html
 //	@Param			algorithmName	path		string	true	"Algorithm name"
 //	@Param			requestId	path		string	true	"Request identifier"
-//	@Success		200	{object}	models.CheckpointedRequest
+//	@Success		200	{object}	models.RunMetadata
 //	@Failure		400	{string}	string
 //	@Failure		404	{string}	string
 //	@Failure		401	{string}	string
 //	@Router			/algorithm/v1/metadata/{algorithmName}/requests/{requestId} [get]
-func GetRunMetadata(buffer request.Buffer) gin.HandlerFunc {
+func GetRunMetadata(buffer request.Buffer, attributeStore storage.AttributeStore) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		// TODO: log errors
 		algorithmName := ctx.Param("algorithmName")
@@ -39,6 +41,13 @@ func GetRunMetadata(buffer request.Buffer) gin.HandlerFunc {
 			return
 		}
 
-		ctx.JSON(http.StatusOK, result)
+		attributes, err := attributeStore.ReadAttributes(result.Algorithm, requestId)
+
+		if err != nil {
+			ctx.String(http.StatusBadRequest, `Failed to read metadata for %s`, requestId)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, models.NewRunMetadata(result, attributes))
 	}
 }
@@ -0,0 +1,147 @@
+package v1
+
+import (
+	"encoding/json"
+	"github.com/SneaksAndData/nexus/api/v1/models"
+	"github.com/SneaksAndData/nexus/docs"
+	"github.com/SneaksAndData/nexus/services"
+	servicemodels "github.com/SneaksAndData/nexus/services/models"
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+	"net/http"
+	"strings"
+)
+
+// apiLocation returns the scheme, host and path prefix clients reach the scheduler at, taking an ingress in front of it into account
+func apiLocation(ctx *gin.Context, config *servicemodels.OpenApiConfig) (string, string, string) {
+	scheme := "http"
+	if ctx.Request.TLS != nil {
+		scheme = "https"
+	}
+	if forwarded := firstHeaderValue(ctx, "X-Forwarded-Proto"); forwarded != "" {
+		scheme = forwarded
+	}
+
+	host := ctx.Request.Host
+	if forwarded := firstHeaderValue(ctx, "X-Forwarded-Host"); forwarded != "" {
+		host = forwarded
+	}
+	if config.Host != "" {
+		host = config.Host
+	}
+
+	basePath := firstHeaderValue(ctx, "X-Forwarded-Prefix")
+	if config.BasePath != "" {
+		basePath = config.BasePath
+	}
+
+	return scheme, host, "/" + strings.Trim(basePath, "/")
+}
+
+// firstHeaderValue returns the first value of a header that can be appended to by each proxy in a chain
+func firstHeaderValue(ctx *gin.Context, name string) string {
+	value, _, _ := strings.Cut(ctx.GetHeader(name), ",")
+	return strings.TrimSpace(value)
+}
+
+// loadSpec parses a generated spec, so it can be served with the deployment location filled in
+func loadSpec(spec []byte, logger klog.Logger) map[string]interface{} {
+	parsed := map[string]interface{}{}
+	if err := json.Unmarshal(spec, &parsed); err != nil { // coverage-ignore
+		logger.V(0).Error(err, "generated API spec is not valid JSON")
+	}
+
+	return parsed
+}
+
+// GetOpenApiV2 godoc
+//
+//	@Summary		Read the Swagger 2.0 spec
+//	@Description	Returns the Swagger 2.0 specification of this API, with host and base path of the deployment
+//	@Tags			docs
+//	@Produce		json
+//	@Success		200	{object}	map[string]interface{}
+//	@Failure		401	{string}	string
+//	@Router			/algorithm/v1/openapi/v2.json [get]
+func GetOpenApiV2(config *servicemodels.OpenApiConfig, logger klog.Logger) gin.HandlerFunc {
+	spec := loadSpec([]byte(docs.SwaggerInfo.ReadDoc()), logger)
+
+	return func(ctx *gin.Context) {
+		scheme, host, basePath := apiLocation(ctx, config)
+
+		served := map[string]interface{}{}
+		for key, value := range spec {
+			served[key] = value
+		}
+		// paths in the spec include the API version, so the base path is only the prefix of the deployment
+		served["schemes"] = []string{scheme}
+		served["host"] = host
+		served["basePath"] = basePath
+
+		ctx.JSON(http.StatusOK, served)
+	}
+}
+
+// GetOpenApiV3 godoc
+//
+//	@Summary		Read the OpenAPI v3 spec
+//	@Description	Returns the OpenAPI v3 specification of this API, with a server URL of the deployment
+//	@Tags			docs
+//	@Produce		json
+//	@Success		200	{object}	map[string]interface{}
+//	@Failure		401	{string}	string
+//	@Router			/algorithm/v1/openapi/v3.json [get]
+func GetOpenApiV3(config *servicemodels.OpenApiConfig, logger klog.Logger) gin.HandlerFunc {
+	spec := loadSpec(docs.OpenApiV3, logger)
+
+	return func(ctx *gin.Context) {
+		scheme, host, basePath := apiLocation(ctx, config)
+
+		served := map[string]interface{}{}
+		for key, value := range spec {
+			served[key] = value
+		}
+		served["servers"] = []map[string]string{
+			{"url": scheme + "://" + host + strings.TrimSuffix(basePath, "/")},
+		}
+
+		ctx.JSON(http.StatusOK, served)
+	}
+}
+
+// GetAlgorithmSchemas godoc
+//
+//	@Summary		List deployed algorithms
+//	@Description	Lists algorithms the caller is authorized to run, with a JSON Schema of their parameters if published by the algorithm template in the science.sneaksanddata.com/parameter-schema annotation
+//	@Tags			docs
+//	@Produce		json
+//	@Success		200	{array}		models.AlgorithmSchema
+//	@Failure		401	{string}	string
+//	@Failure		500	{string}	string
+//	@Router			/algorithm/v1/openapi/algorithms [get]
+func GetAlgorithmSchemas(catalog *services.AlgorithmCatalog, config *servicemodels.AlgorithmCatalogConfig, logger klog.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		caller, ok := callerIdentity(ctx, config)
+		if !ok {
+			ctx.String(http.StatusUnauthorized, `Caller identity is required to list algorithms`)
+			return
+		}
+
+		entries, err := catalog.List(ctx, caller, "")
+		if err != nil {
+			logger.V(0).Error(err, "failed to list algorithms", "user", caller.User)
+			ctx.String(http.StatusInternalServerError, `Internal error occurred when processing your request.`)
+			return
+		}
+
+		schemas := []*models.AlgorithmSchema{}
+		for _, entry := range entries {
+			schemas = append(schemas, &models.AlgorithmSchema{
+				AlgorithmName:   entry.Name,
+				ParameterSchema: entry.ParameterSchema,
+			})
+		}
+
+		ctx.JSON(http.StatusOK, schemas)
+	}
+}
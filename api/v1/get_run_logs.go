@@ -0,0 +1,59 @@
+package v1
+
+import (
+	"errors"
+	"github.com/SneaksAndData/nexus/services"
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"net/http"
+	"strconv"
+)
+
+// GetRunLogs godoc
+//
+//	@Summary		Read run logs
+//	@Description	Streams container logs of all pods created for the provided run from the shard it was submitted to. Each container section starts with a ==> pod/container <== header
+//	@Tags			results
+//	@Produce		plain
+//	@Param			algorithmName	path		string	true	"Algorithm name"
+//	@Param			requestId	path		string	true	"Request identifier"
+//	@Param			tailLines	query	int	false	"Number of lines from the end of each container log to return. All lines are returned if omitted"
+//	@Param			previous	query	bool	false	"Return logs of the previous container instance, for runs with restarted containers"
+//	@Success		200	{string}	string
+//	@Failure		400	{string}	string
+//	@Failure		404	{string}	string
+//	@Failure		500	{string}	string
+//	@Failure		401	{string}	string
+//	@Router			/algorithm/v1/logs/{algorithmName}/requests/{requestId} [get]
+func GetRunLogs(scheduler *services.RequestScheduler, logger klog.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		algorithmName := ctx.Param("algorithmName")
+		requestId := ctx.Param("requestId")
+		options := &corev1.PodLogOptions{
+			Previous: ctx.DefaultQuery("previous", "false") == "true",
+		}
+
+		if value := ctx.Query("tailLines"); value != "" {
+			tailLines, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || tailLines <= 0 {
+				ctx.String(http.StatusBadRequest, `tailLines must be a positive integer, but got: %s`, value)
+				return
+			}
+			options.TailLines = &tailLines
+		}
+
+		ctx.Header("Content-Type", "text/plain; charset=utf-8")
+		err := scheduler.StreamRunLogs(ctx, requestId, algorithmName, options, ctx.Writer)
+
+		switch {
+		case errors.Is(err, services.ErrRunPodsNotFound):
+			ctx.String(http.StatusNotFound, `No pods found for the request with identifier '%s'`, requestId)
+		case err != nil && !ctx.Writer.Written():
+			ctx.String(http.StatusInternalServerError, `Unhandled error when reading run logs. Please try again later`)
+			logger.V(0).Error(err, "error when reading run logs", "request", requestId, "algorithm", algorithmName)
+		case err != nil:
+			logger.V(0).Error(err, "run log stream interrupted", "request", requestId, "algorithm", algorithmName)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package v1
+
+import (
+	"github.com/SneaksAndData/nexus/services"
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+	"net/http"
+)
+
+// GetRunTimeline godoc
+//
+//	@Summary		Read a run timeline
+//	@Description	Retrieves checkpoint lifecycle transitions of the provided run merged with Kubernetes events for its Job and pods from the shard it was submitted to, ordered by time
+//	@Tags			metadata
+//	@Produce		json
+//	@Produce		plain
+//	@Param			algorithmName	path		string	true	"Algorithm name"
+//	@Param			requestId	path		string	true	"Request identifier"
+//	@Success		200	{array}	models.RunTimelineEvent
+//	@Failure		404	{string}	string
+//	@Failure		500	{string}	string
+//	@Failure		401	{string}	string
+//	@Router			/algorithm/v1/timeline/{algorithmName}/requests/{requestId} [get]
+func GetRunTimeline(scheduler *services.RequestScheduler, logger klog.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		algorithmName := ctx.Param("algorithmName")
+		requestId := ctx.Param("requestId")
+
+		timeline, err := scheduler.GetRunTimeline(ctx, requestId, algorithmName)
+
+		if err != nil {
+			ctx.String(http.StatusInternalServerError, `Unhandled error when reading a run timeline. Please try again later`)
+			logger.V(0).Error(err, "error when reading a run timeline", "request", requestId, "algorithm", algorithmName)
+			return
+		}
+
+		if timeline == nil {
+			ctx.String(http.StatusNotFound, `Provided request with identifier '%s' not found`, requestId)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, timeline)
+	}
+}
@@ -3,6 +3,7 @@ package v1
 import (
 	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/request"
 	"github.com/SneaksAndData/nexus/api/v1/models"
+	"github.com/SneaksAndData/nexus/services"
 	"github.com/gin-gonic/gin"
 	"k8s.io/klog/v2"
 	"net/http"
@@ -11,19 +12,24 @@ import (
 // GetRunResultsByTag godoc
 //
 //	@Summary		Read run results by tag
-//	@Description	Read results of all runs with a matching tag
+//	@Description	Read results of all runs with a matching tag. Tags are scoped to a tenant, so runs of tenant algorithms are only matched if the tenant is provided
 //	@Tags			results
 //	@Produce		json
 //	@Produce		plain
 //	@Produce		html
 //	@Param			requestTag	path		string	true	"Request tag assigned by a client"
+//	@Param			tenant		query		string	false	"Tenant of algorithms the runs were submitted to. Not authenticated: tenants namespace tags, they do not isolate runs from callers of other tenants"
 //	@Success		200	{array}    models.TaggedRequestResult
 //	@Failure		400	{string}	string
 //	@Failure		401	{string}	string
 //	@Router			/algorithm/v1/results/tags/{requestTag} [get]
-func GetRunResultsByTag(buffer request.Buffer, logger klog.Logger) gin.HandlerFunc {
+func GetRunResultsByTag(buffer request.Buffer, scheduler *services.RequestScheduler, logger klog.Logger) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		tag := ctx.Param("requestTag")
+		tag, err := scheduler.ScopedTag(ctx.Query("tenant"), ctx.Param("requestTag"))
+		if err != nil {
+			ctx.String(http.StatusBadRequest, `Request tag is invalid: %s`, err.Error())
+			return
+		}
 
 		results, err := buffer.GetTagged(tag)
 
@@ -0,0 +1,130 @@
+package v1
+
+import (
+	"errors"
+	"github.com/SneaksAndData/nexus/api/v1/models"
+	"github.com/SneaksAndData/nexus/services"
+	servicemodels "github.com/SneaksAndData/nexus/services/models"
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+	"net/http"
+	"strconv"
+)
+
+// authorizeAlgorithm returns the catalog entry of an algorithm, or responds with an error and returns false if the algorithm does not exist or the caller is not authorized to see it
+func authorizeAlgorithm(ctx *gin.Context, catalog *services.AlgorithmCatalog, config *servicemodels.AlgorithmCatalogConfig, algorithmName string, logger klog.Logger) (*servicemodels.AlgorithmCatalogEntry, bool) {
+	caller, ok := callerIdentity(ctx, config)
+	if !ok {
+		ctx.String(http.StatusUnauthorized, `Caller identity is required to read algorithms`)
+		return nil, false
+	}
+
+	entry, err := catalog.Get(ctx, caller, algorithmName)
+	if err != nil {
+		logger.V(0).Error(err, "failed to read an algorithm", "algorithm", algorithmName, "user", caller.User)
+		ctx.String(http.StatusInternalServerError, `Internal error occurred when processing your request.`)
+		return nil, false
+	}
+
+	if entry == nil {
+		ctx.String(http.StatusNotFound, `Algorithm %s not found`, algorithmName)
+		return nil, false
+	}
+
+	return entry, true
+}
+
+// ListTemplateRevisions godoc
+//
+//	@Summary		List algorithm template revisions
+//	@Description	Lists recorded spec revisions of an algorithm template, latest first. Runs can be pinned to a revision with the templateRevision parameter
+//	@Tags			algorithms
+//	@Produce		json
+//	@Produce		plain
+//	@Param			algorithmName	path		string	true	"Algorithm name"
+//	@Success		200				{array}		models.TemplateRevision
+//	@Failure		401				{string}	string
+//	@Failure		404				{string}	string
+//	@Failure		500				{string}	string
+//	@Router			/algorithm/v1/algorithms/{algorithmName}/revisions [get]
+func ListTemplateRevisions(catalog *services.AlgorithmCatalog, history *services.TemplateHistory, config *servicemodels.AlgorithmCatalogConfig, logger klog.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		algorithmName := ctx.Param("algorithmName")
+		entry, ok := authorizeAlgorithm(ctx, catalog, config, algorithmName, logger)
+		if !ok {
+			return
+		}
+
+		// revisions are recorded under the template the name resolves to
+		recorded, err := history.List(services.RunAlgorithmName(entry.Name))
+		if err != nil {
+			logger.V(0).Error(err, "failed to list template revisions", "algorithm", algorithmName)
+			ctx.String(http.StatusInternalServerError, `Internal error occurred when processing your request.`)
+			return
+		}
+
+		revisions := []*models.TemplateRevision{}
+		for _, revision := range recorded {
+			spec, err := revision.TemplateSpec()
+			if err != nil {
+				logger.V(0).Error(err, "failed to read a template revision", "algorithm", algorithmName, "revision", revision.Revision)
+				ctx.String(http.StatusInternalServerError, `Internal error occurred when processing your request.`)
+				return
+			}
+
+			revisions = append(revisions, &models.TemplateRevision{
+				AlgorithmName:   entry.Name,
+				Revision:        revision.Revision,
+				ResourceVersion: revision.ResourceVersion,
+				CreatedAt:       revision.CreatedAt,
+				Spec:            spec,
+			})
+		}
+
+		ctx.JSON(http.StatusOK, revisions)
+	}
+}
+
+// DiffTemplateRevisions godoc
+//
+//	@Summary		Compare algorithm template revisions
+//	@Description	Lists values that differ between two recorded spec revisions of an algorithm template. Paths refer to the JSON representation of the spec
+//	@Tags			algorithms
+//	@Produce		json
+//	@Produce		plain
+//	@Param			algorithmName	path		string	true	"Algorithm name"
+//	@Param			from			query		int		true	"Revision to compare from"
+//	@Param			to				query		int		true	"Revision to compare to"
+//	@Success		200				{array}		servicemodels.TemplateSpecChange
+//	@Failure		400				{string}	string
+//	@Failure		401				{string}	string
+//	@Failure		404				{string}	string
+//	@Failure		500				{string}	string
+//	@Router			/algorithm/v1/algorithms/{algorithmName}/revisions/diff [get]
+func DiffTemplateRevisions(catalog *services.AlgorithmCatalog, history *services.TemplateHistory, config *servicemodels.AlgorithmCatalogConfig, logger klog.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		algorithmName := ctx.Param("algorithmName")
+		from, fromErr := strconv.ParseInt(ctx.Query("from"), 10, 64)
+		to, toErr := strconv.ParseInt(ctx.Query("to"), 10, 64)
+		if fromErr != nil || toErr != nil {
+			ctx.String(http.StatusBadRequest, `Revisions to compare must be provided as integers in from and to parameters`)
+			return
+		}
+
+		entry, ok := authorizeAlgorithm(ctx, catalog, config, algorithmName, logger)
+		if !ok {
+			return
+		}
+
+		changes, err := history.Diff(services.RunAlgorithmName(entry.Name), from, to)
+		switch {
+		case errors.Is(err, services.ErrRevisionNotFound):
+			ctx.String(http.StatusNotFound, `Revision %d or %d of algorithm %s not found`, from, to, algorithmName)
+		case err != nil:
+			logger.V(0).Error(err, "failed to compare template revisions", "algorithm", algorithmName, "from", from, "to", to)
+			ctx.String(http.StatusInternalServerError, `Internal error occurred when processing your request.`)
+		default:
+			ctx.JSON(http.StatusOK, changes)
+		}
+	}
+}
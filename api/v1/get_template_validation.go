@@ -0,0 +1,59 @@
+package v1
+
+import (
+	"github.com/SneaksAndData/nexus/services"
+	servicemodels "github.com/SneaksAndData/nexus/services/models"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// ListTemplateValidation godoc
+//
+//	@Summary		List template validation results
+//	@Description	Lists results of the latest consistency check of each algorithm template: workgroup and shard references, and service accounts, secrets and config maps on the shard if enabled
+//	@Tags			validation
+//	@Produce		json
+//	@Param			invalid	query		bool	false	"List only templates that failed validation"
+//	@Success		200		{array}		servicemodels.TemplateValidationStatus
+//	@Failure		401		{string}	string
+//	@Router			/algorithm/v1/validation/algorithms [get]
+func ListTemplateValidation(configCache *services.NexusResourceCache) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		onlyInvalid := ctx.Query("invalid") == "true"
+
+		statuses := []*servicemodels.TemplateValidationStatus{}
+		for _, status := range configCache.ListTemplateValidationStatus() {
+			if onlyInvalid && status.Valid {
+				continue
+			}
+			statuses = append(statuses, status)
+		}
+
+		ctx.JSON(http.StatusOK, statuses)
+	}
+}
+
+// GetTemplateValidation godoc
+//
+//	@Summary		Read a template validation result
+//	@Description	Retrieves the result of the latest consistency check of an algorithm template
+//	@Tags			validation
+//	@Produce		json
+//	@Produce		plain
+//	@Param			algorithmName	path		string	true	"Algorithm name"
+//	@Success		200	{object}	servicemodels.TemplateValidationStatus
+//	@Failure		401	{string}	string
+//	@Failure		404	{string}	string
+//	@Router			/algorithm/v1/validation/algorithms/{algorithmName} [get]
+func GetTemplateValidation(configCache *services.NexusResourceCache) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		algorithmName := ctx.Param("algorithmName")
+		status := configCache.GetTemplateValidationStatus(algorithmName)
+		if status == nil {
+			ctx.String(http.StatusNotFound, `Validation result for algorithm %s not found`, algorithmName)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, status)
+	}
+}
@@ -0,0 +1,8 @@
+package models
+
+// AlgorithmSchema describes parameters accepted by a deployed algorithm
+type AlgorithmSchema struct {
+	AlgorithmName string `json:"algorithmName"`
+	// ParameterSchema is a JSON Schema of algorithmParameters, if published by the algorithm template
+	ParameterSchema map[string]interface{} `json:"parameterSchema,omitempty"`
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	coremodels "github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -24,3 +25,22 @@ func (r *CancellationRequest) GetPolicy() (*v1.DeletionPropagation, error) {
 		return nil, fmt.Errorf("invalid CancellationPolicy: '%s', accepted values: Orphan, Foreground or Background", r.CancellationPolicy)
 	}
 }
+
+type BulkCancellationRequest struct {
+	CancellationRequest
+	LifecycleStages []string `json:"lifecycleStages,omitempty"`
+}
+
+// GetLifecycleStages returns lifecycle stages targeted by the bulk cancellation. Only runs that have not finished yet can be cancelled.
+func (r *BulkCancellationRequest) GetLifecycleStages() ([]string, error) {
+	for _, stage := range r.LifecycleStages {
+		switch stage {
+		case coremodels.LifecycleStageNew, coremodels.LifecycleStageBuffered, coremodels.LifecycleStageRunning:
+			continue
+		default:
+			return nil, fmt.Errorf("invalid lifecycle stage: '%s', accepted values: NEW, BUFFERED or RUNNING", stage)
+		}
+	}
+
+	return r.LifecycleStages, nil
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
+	schedulermodels "github.com/SneaksAndData/nexus/services/models"
+	"time"
+)
+
+type RunMetadata struct {
+	*models.CheckpointedRequest
+	Shard               string     `json:"shard,omitempty"`
+	SchedulingAttempts  int        `json:"schedulingAttempts,omitempty"`
+	LastSchedulingError string     `json:"lastSchedulingError,omitempty"`
+	QueueDeadline       *time.Time `json:"queueDeadline,omitempty"`
+	QueueTime           string     `json:"queueTime,omitempty"`
+	CompletedAt         *time.Time `json:"completedAt,omitempty"`
+	EndToEndLatency     string     `json:"endToEndLatency,omitempty"`
+	PayloadReference    string     `json:"payloadReference,omitempty"`
+	PayloadContentHash  string     `json:"payloadContentHash,omitempty"`
+	PayloadSize         int64      `json:"payloadSize,omitempty"`
+	PayloadContentType  string     `json:"payloadContentType,omitempty"`
+	PayloadEncoding     string     `json:"payloadEncoding,omitempty"`
+	ResultCache         string     `json:"resultCache,omitempty"`
+	ResultCacheSource   string     `json:"resultCacheSource,omitempty"`
+	TemplateVariant     string     `json:"templateVariant,omitempty"`
+}
+
+// NewRunMetadata combines a CheckpointedRequest with attributes recorded by the scheduler
+func NewRunMetadata(request *models.CheckpointedRequest, attributes *schedulermodels.CheckpointAttributes) *RunMetadata {
+	if request == nil {
+		return nil
+	}
+
+	result := &RunMetadata{
+		CheckpointedRequest: request,
+	}
+
+	if attributes != nil {
+		result.Shard = attributes.Shard
+		result.SchedulingAttempts = attributes.SchedulingAttempts
+		result.LastSchedulingError = attributes.LastSchedulingError
+		if !attributes.QueueDeadline.IsZero() {
+			result.QueueDeadline = &attributes.QueueDeadline
+		}
+		result.PayloadReference = attributes.PayloadReference
+		result.PayloadContentHash = attributes.PayloadContentHash
+		result.PayloadSize = attributes.PayloadSize
+		result.PayloadContentType = attributes.PayloadContentType
+		result.PayloadEncoding = attributes.PayloadContentEncoding
+		result.ResultCache = attributes.ResultCache
+		result.ResultCacheSource = attributes.ResultCacheSource
+		result.TemplateVariant = attributes.TemplateVariant
+		// content hash is only recorded on checkpoints of cache hits, runs submitted to a cluster keep it in attributes
+		if request.ContentHash == "" {
+			request.ContentHash = attributes.ContentHash
+		}
+	}
+
+	// received -> sent
+	if !request.ReceivedAt.IsZero() && !request.SentAt.IsZero() {
+		result.QueueTime = request.SentAt.Sub(request.ReceivedAt).String()
+	}
+
+	// received -> completed, finished checkpoints are no longer modified after reaching the final stage
+	if !request.ReceivedAt.IsZero() && request.IsFinished() {
+		result.CompletedAt = &request.LastModified
+		result.EndToEndLatency = request.LastModified.Sub(request.ReceivedAt).String()
+	}
+
+	return result
+}
@@ -0,0 +1,15 @@
+package models
+
+import (
+	v1 "github.com/SneaksAndData/nexus-core/pkg/apis/science/v1"
+	"time"
+)
+
+// TemplateRevision is a recorded spec of an algorithm template
+type TemplateRevision struct {
+	AlgorithmName   string                 `json:"algorithmName"`
+	Revision        int64                  `json:"revision"`
+	ResourceVersion string                 `json:"resourceVersion"`
+	CreatedAt       time.Time              `json:"createdAt"`
+	Spec            *v1.NexusAlgorithmSpec `json:"spec"`
+}
@@ -0,0 +1,77 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
+	"github.com/SneaksAndData/nexus/services"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	headerAlgorithmParameters = "Nexus-Algorithm-Parameters"
+	headerRequestApiVersion   = "Nexus-Request-Api-Version"
+	headerTag                 = "Nexus-Tag"
+	headerParentRequest       = "Nexus-Parent-Request"
+	headerPayloadValidFor     = "Nexus-Payload-Valid-For"
+)
+
+// bindAlgorithmRequest reads a CreateRun request according to its content type. Requests with a body that must be stored as is return it as services.OriginalPayload. Bodies larger than maxSize fail with http.MaxBytesError, regardless of the content type
+func bindAlgorithmRequest(ctx *gin.Context, payload *models.AlgorithmRequest, maxSize int64) (*services.OriginalPayload, error) {
+	contentType := services.NormalizeContentType(ctx.ContentType())
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxSize)
+
+	switch contentType {
+	case services.ContentTypeMsgpack, services.ContentTypeProtobuf:
+		body, err := io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := services.DecodeAlgorithmRequest(contentType, body, payload); err != nil {
+			return nil, err
+		}
+
+		return &services.OriginalPayload{ContentType: contentType, Body: bytes.NewReader(body)}, binding.Validator.ValidateStruct(payload)
+	case services.ContentTypeBinary:
+		if err := bindRequestHeaders(ctx, payload); err != nil {
+			return nil, err
+		}
+
+		return &services.OriginalPayload{ContentType: contentType, Body: ctx.Request.Body}, binding.Validator.ValidateStruct(payload)
+	default:
+		// any other content type is read as JSON, as before content negotiation was supported
+		return nil, ctx.ShouldBindJSON(payload)
+	}
+}
+
+// bindRequestHeaders reads run configuration of a binary payload from request headers
+func bindRequestHeaders(ctx *gin.Context, payload *models.AlgorithmRequest) error {
+	payload.AlgorithmParameters = map[string]interface{}{}
+	if value := ctx.GetHeader(headerAlgorithmParameters); value != "" {
+		if err := json.Unmarshal([]byte(value), &payload.AlgorithmParameters); err != nil {
+			return fmt.Errorf("%s header must be a JSON object: %w", headerAlgorithmParameters, err)
+		}
+	}
+
+	payload.RequestApiVersion = ctx.GetHeader(headerRequestApiVersion)
+	payload.Tag = ctx.GetHeader(headerTag)
+	payload.PayloadValidFor = ctx.GetHeader(headerPayloadValidFor)
+
+	if value := ctx.GetHeader(headerParentRequest); value != "" {
+		// algorithm names of tenants contain a separator as well, while request identifiers do not
+		separator := strings.LastIndex(value, "/")
+		if separator <= 0 || separator == len(value)-1 {
+			return fmt.Errorf("%s header must be in the algorithmName/requestId format", headerParentRequest)
+		}
+
+		payload.ParentRequest = &models.AlgorithmRequestRef{RequestId: value[separator+1:], AlgorithmName: value[:separator]}
+	}
+
+	return nil
+}
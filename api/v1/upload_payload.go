@@ -0,0 +1,141 @@
+package v1
+
+import (
+	"errors"
+	"github.com/SneaksAndData/nexus/services"
+	servicemodels "github.com/SneaksAndData/nexus/services/models"
+	"github.com/gin-gonic/gin"
+	"io"
+	"k8s.io/klog/v2"
+	"mime"
+	"net/http"
+)
+
+// CreatePayloadUpload godoc
+//
+//	@Summary		Create a payload upload slot
+//	@Description	Allocates a location in the payload storage and returns a pre-signed POST form to upload a large payload with. Send uploadFields and then the payload as the file field in a multipart/form-data POST to uploadUrl. Uploads larger than the maximum payload size are rejected by the object store. Pass the returned uploadId to CreateRun as payloadUploadId
+//	@Tags			upload
+//	@Produce		json
+//	@Param			algorithmName	path		string	true	"Algorithm name"
+//	@Success		201	{object}	servicemodels.PayloadUploadSlot
+//	@Failure		400	{string}	string
+//	@Failure		500	{string}	string
+//	@Failure		401	{string}	string
+//	@Router			/algorithm/v1/upload/{algorithmName} [post]
+func CreatePayloadUpload(configCache *services.NexusResourceCache, uploads *services.PayloadUploadStore, logger klog.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		algorithmName := ctx.Param("algorithmName")
+
+		algorithmName, ok := resolveAlgorithmName(ctx, configCache, algorithmName, logger)
+		if !ok {
+			return
+		}
+
+		slot, err := uploads.CreateSlot(ctx, algorithmName)
+		if err != nil { // coverage-ignore
+			ctx.String(http.StatusInternalServerError, `Internal error occurred when processing your request.`)
+			logger.V(0).Error(err, "error when creating a payload upload slot", "algorithm", algorithmName)
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, slot)
+	}
+}
+
+// UploadPayload godoc
+//
+//	@Summary		Upload a payload
+//	@Description	Streams a request body, or the first file of a multipart/form-data body, into the upload slot. Compressed payloads are stored as is, with the encoding recorded. Pass the uploadId to CreateRun as payloadUploadId
+//	@Tags			upload
+//	@Accept			octet-stream
+//	@Accept			mpfd
+//	@Produce		json
+//	@Param			algorithmName	path		string	true	"Algorithm name"
+//	@Param			uploadId	path		string	true	"Upload identifier returned when creating an upload slot"
+//	@Param			Content-Encoding	header	string	false	"Encoding of a compressed payload, gzip or zstd"
+//	@Success		201	{object}	servicemodels.PayloadObject
+//	@Failure		400	{string}	string
+//	@Failure		413	{string}	string
+//	@Failure		415	{string}	string
+//	@Failure		500	{string}	string
+//	@Failure		401	{string}	string
+//	@Router			/algorithm/v1/upload/{algorithmName}/{uploadId} [put]
+func UploadPayload(configCache *services.NexusResourceCache, uploads *services.PayloadUploadStore, logger klog.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		algorithmName := ctx.Param("algorithmName")
+		uploadId := ctx.Param("uploadId")
+
+		algorithmName, ok := resolveAlgorithmName(ctx, configCache, algorithmName, logger)
+		if !ok {
+			return
+		}
+
+		contentEncoding := ctx.GetHeader("Content-Encoding")
+		if contentEncoding != "" && contentEncoding != services.EncodingGzip && contentEncoding != services.EncodingZstd {
+			ctx.String(http.StatusUnsupportedMediaType, `Content encoding %s is not supported. Use gzip or zstd`, contentEncoding)
+			return
+		}
+
+		body, err := uploadBody(ctx.Request)
+		if err != nil {
+			ctx.String(http.StatusBadRequest, `Payload upload is invalid: %s`, err.Error())
+			return
+		}
+
+		var object *servicemodels.PayloadObject
+		object, err = uploads.Upload(ctx, algorithmName, uploadId, servicemodels.PayloadFormat{ContentEncoding: contentEncoding}, body)
+		switch {
+		case err == nil:
+			ctx.JSON(http.StatusCreated, object)
+		case errors.Is(err, services.ErrInvalidUploadId):
+			ctx.String(http.StatusBadRequest, `Upload id %s is not a valid upload identifier`, uploadId)
+		case errors.Is(err, services.ErrPayloadTooLarge):
+			ctx.String(http.StatusRequestEntityTooLarge, `Payload exceeds the maximum upload size`)
+		default:
+			ctx.String(http.StatusInternalServerError, `Internal error occurred when processing your request.`)
+			logger.V(0).Error(err, "error when uploading a payload", "algorithm", algorithmName, "upload", uploadId)
+		}
+	}
+}
+
+// resolveAlgorithmName returns the name payloads of the algorithm are stored under, or writes a response and returns false if uploads cannot be accepted for the algorithm
+func resolveAlgorithmName(ctx *gin.Context, configCache *services.NexusResourceCache, algorithmName string, logger klog.Logger) (string, bool) {
+	config, err := configCache.GetAlgorithmConfiguration(algorithmName)
+	if err != nil { // coverage-ignore
+		ctx.String(http.StatusInternalServerError, `Internal error occurred when processing your request.`)
+		logger.V(0).Error(err, "error when retrieving algorithm template", "algorithm", algorithmName)
+		return "", false
+	}
+
+	if config == nil {
+		ctx.String(http.StatusBadRequest, `No valid configuration found for: %s. Please check that algorithm name is spelled correctly and try again. Contact an algorithm author if this problem persists.`, algorithmName)
+		return "", false
+	}
+
+	return configCache.RunName(config), true
+}
+
+// uploadBody returns the payload stream: the first file part of a multipart body, or the raw request body
+func uploadBody(request *http.Request) (io.Reader, error) {
+	mediaType, _, err := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" {
+		return request.Body, nil
+	}
+
+	reader, err := request.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return nil, errors.New("multipart body does not contain a file")
+		}
+
+		if part.FileName() != "" {
+			return part, nil
+		}
+	}
+}
@@ -2,20 +2,33 @@ package app
 
 import (
 	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/request"
+	"github.com/SneaksAndData/nexus/services/models"
 	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 type SchedulerConfig struct {
-	S3Buffer            request.S3BufferConfig       `mapstructure:"s3-buffer,omitempty"`
-	AstraCqlStore       request.AstraBundleConfig    `mapstructure:"astra-cql-store,omitempty"`
-	ScyllaCqlStore      request.ScyllaCqlStoreConfig `mapstructure:"scylla-cql-store,omitempty"`
-	CqlStoreType        string                       `mapstructure:"cql-store-type,omitempty"`
-	DeployNamespace     string                       `mapstructure:"deploy-namespace,omitempty"`
-	RuntimeNamespace    string                       `mapstructure:"runtime-namespace,omitempty"`
-	KubeConfigPath      string                       `mapstructure:"kube-config-path,omitempty"`
-	ShardKubeConfigPath string                       `mapstructure:"shard-kube-config-path,omitempty"`
-	LogLevel            string                       `mapstructure:"log-level,omitempty"`
-	MaxPayloadSize      string                       `mapstructure:"max-payload-size,omitempty"`
+	S3Buffer            request.S3BufferConfig          `mapstructure:"s3-buffer,omitempty"`
+	AstraCqlStore       request.AstraBundleConfig       `mapstructure:"astra-cql-store,omitempty"`
+	ScyllaCqlStore      request.ScyllaCqlStoreConfig    `mapstructure:"scylla-cql-store,omitempty"`
+	CqlStoreType        string                          `mapstructure:"cql-store-type,omitempty"`
+	DeployNamespace     string                          `mapstructure:"deploy-namespace,omitempty"`
+	RuntimeNamespace    string                          `mapstructure:"runtime-namespace,omitempty"`
+	KubeConfigPath      string                          `mapstructure:"kube-config-path,omitempty"`
+	ShardKubeConfigPath string                          `mapstructure:"shard-kube-config-path,omitempty"`
+	LogLevel            string                          `mapstructure:"log-level,omitempty"`
+	MaxPayloadSize      string                          `mapstructure:"max-payload-size,omitempty"`
+	SchedulingRetry     models.SchedulingRetryConfig    `mapstructure:"scheduling-retry,omitempty"`
+	Supervisor          models.SupervisorConfig         `mapstructure:"supervisor,omitempty"`
+	JobReconciliation   models.JobReconciliationConfig  `mapstructure:"job-reconciliation,omitempty"`
+	ProxyDownload       models.ProxyDownloadConfig      `mapstructure:"proxy-download,omitempty"`
+	PayloadUpload       models.PayloadUploadConfig      `mapstructure:"payload-upload,omitempty"`
+	Compression         models.CompressionConfig        `mapstructure:"compression,omitempty"`
+	Grpc                models.GrpcConfig               `mapstructure:"grpc,omitempty"`
+	OpenApi             models.OpenApiConfig            `mapstructure:"openapi,omitempty"`
+	AlgorithmCatalog    models.AlgorithmCatalogConfig   `mapstructure:"algorithm-catalog,omitempty"`
+	TemplateValidation  models.TemplateValidationConfig `mapstructure:"template-validation,omitempty"`
+	TemplateHistory     models.TemplateHistoryConfig    `mapstructure:"template-history,omitempty"`
+	Tenancy             models.TenancyConfig            `mapstructure:"tenancy,omitempty"`
 }
 
 const (
@@ -23,6 +36,19 @@ const (
 	CqlStoreScylla = "scylla"
 )
 
+// Validate rejects configuration values that would make the scheduler fail after startup
+func (c *SchedulerConfig) Validate() error {
+	if err := c.Supervisor.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Grpc.Validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (c *SchedulerConfig) MaxPayloadSizeBytes() int64 { // coverage-ignore
 	var quantity = resource.MustParse(c.MaxPayloadSize)
 	return quantity.Value()
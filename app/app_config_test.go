@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/request"
 	nexusconf "github.com/SneaksAndData/nexus-core/pkg/configurations"
+	"github.com/SneaksAndData/nexus/services/models"
 	"os"
 	"reflect"
 	"testing"
@@ -47,6 +48,80 @@ func getExpectedConfig(storagePath string) *SchedulerConfig {
 		ShardKubeConfigPath: "/tmp/shards",
 		MaxPayloadSize:      "500Mi",
 		LogLevel:            "debug",
+		SchedulingRetry: models.SchedulingRetryConfig{
+			Default: models.SchedulingRetryPolicy{
+				MaxAttempts: 5,
+				BaseDelay:   time.Second,
+				MaxDelay:    time.Minute,
+			},
+			Algorithms: map[string]models.SchedulingRetryPolicy{
+				"test-algorithm": {
+					MaxAttempts: 2,
+					BaseDelay:   time.Millisecond * 100,
+					MaxDelay:    time.Second,
+				},
+			},
+		},
+		Supervisor: models.SupervisorConfig{
+			Enabled:               true,
+			Interval:              time.Minute,
+			GracePeriod:           time.Minute * 5,
+			LeaseName:             "nexus-scheduler-supervisor",
+			LeaseDuration:         time.Second * 15,
+			RenewDeadline:         time.Second * 10,
+			RetryPeriod:           time.Second * 2,
+			RecoveryClaimDuration: time.Minute * 5,
+		},
+		JobReconciliation: models.JobReconciliationConfig{
+			Enabled:      true,
+			ResyncPeriod: time.Minute * 5,
+		},
+		ProxyDownload: models.ProxyDownloadConfig{
+			Enabled:           true,
+			MaxSize:           "500Mi",
+			Timeout:           time.Minute * 5,
+			ResultStoragePath: "s3a://nexus/results",
+		},
+		PayloadUpload: models.PayloadUploadConfig{
+			Enabled:           true,
+			MaxSize:           "2Gi",
+			UploadUrlValidFor: time.Hour,
+		},
+		Compression: models.CompressionConfig{
+			Enabled:         true,
+			MinResponseSize: "1Ki",
+		},
+		Grpc: models.GrpcConfig{
+			Enabled:       true,
+			Port:          9090,
+			WatchInterval: time.Second * 5,
+		},
+		OpenApi: models.OpenApiConfig{
+			Enabled:   true,
+			UiEnabled: true,
+		},
+		AlgorithmCatalog: models.AlgorithmCatalogConfig{
+			AuthorizeCallers: false,
+			UserHeader:       "X-Remote-User",
+			GroupsHeader:     "X-Remote-Group",
+			DecisionCacheTtl: time.Second * 30,
+		},
+		TemplateValidation: models.TemplateValidationConfig{
+			Enabled:             true,
+			CheckShardResources: true,
+			RecordEvents:        true,
+		},
+		TemplateHistory: models.TemplateHistoryConfig{
+			Enabled: true,
+		},
+		Tenancy: models.TenancyConfig{
+			Enabled:             true,
+			Namespaces:          []string{"team-a", "team-b"},
+			LabelSelector:       "science.sneaksanddata.com/tenant=true",
+			TenantJobNamespaces: true,
+			MaxActiveRuns:       100,
+			ActiveRunsCacheTtl:  time.Second * 10,
+		},
 	}
 }
 
@@ -77,3 +152,30 @@ func Test_LoadConfigFromEnv(t *testing.T) {
 		t.Errorf("LoadConfig failed, expected %v, got %v", *expected, result)
 	}
 }
+
+func Test_ValidateConfig(t *testing.T) {
+	config := getExpectedConfig("s3://bucket/nexus/payloads")
+	if err := config.Validate(); err != nil {
+		t.Errorf("expected a valid configuration, but got %v", err)
+	}
+
+	config.Supervisor.RetryPeriod = 0
+	if err := config.Validate(); err == nil {
+		t.Errorf("expected a zero supervisor retry period to be rejected")
+	}
+
+	config.Supervisor.RetryPeriod = config.Supervisor.RenewDeadline
+	if err := config.Validate(); err == nil {
+		t.Errorf("expected a retry period exceeding the renew deadline to be rejected")
+	}
+
+	config.Supervisor.Enabled = false
+	if err := config.Validate(); err != nil {
+		t.Errorf("expected a disabled supervisor not to be validated, but got %v", err)
+	}
+
+	config.Grpc.WatchInterval = 0
+	if err := config.Validate(); err == nil {
+		t.Errorf("expected a zero gRPC watch interval to be rejected")
+	}
+}
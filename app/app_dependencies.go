@@ -8,6 +8,7 @@ import (
 	"github.com/SneaksAndData/nexus-core/pkg/shards"
 	"github.com/SneaksAndData/nexus/services"
 	"github.com/SneaksAndData/nexus/services/models"
+	"github.com/SneaksAndData/nexus/storage"
 	corev1 "k8s.io/api/core/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes"
@@ -16,24 +17,40 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
+	"os"
+	"path"
+	"strings"
 )
 
 type ApplicationServices struct {
-	checkpointBuffer request.Buffer
-	runtimeNamespace string
-	deployNamespace  string
-	kubeClient       kubernetes.Interface
-	shardClients     []*shards.ShardClient
-	nexusClient      nexuscore.Interface
-	recorder         record.EventRecorder
-	configCache      *services.NexusResourceCache
-	scheduler        *services.RequestScheduler
-	workerConfig     *models.PipelineWorkerConfig
+	checkpointBuffer     request.Buffer
+	aliasedBuffer        request.Buffer
+	store                storage.Store
+	runtimeNamespace     string
+	deployNamespace      string
+	kubeClient           kubernetes.Interface
+	shardClients         []*shards.ShardClient
+	shardKubeClients     map[string]kubernetes.Interface
+	nexusClient          nexuscore.Interface
+	recorder             record.EventRecorder
+	configCache          *services.NexusResourceCache
+	scheduler            *services.RequestScheduler
+	workerConfig         *models.PipelineWorkerConfig
+	retryConfig          *models.SchedulingRetryConfig
+	supervisorConfig     *models.SupervisorConfig
+	reconciliationConfig *models.JobReconciliationConfig
+	objectProxy          *services.ObjectProxy
+	payloadUploads       *services.PayloadUploadStore
+	submitter            *services.RunSubmitter
+	algorithmCatalog     *services.AlgorithmCatalog
+	templateHistory      *services.TemplateHistory
+	tenancyConfig        *models.TenancyConfig
 }
 
 func (appServices *ApplicationServices) WithAstraS3Buffer(ctx context.Context, config *request.S3BufferConfig, bundleConfig *request.AstraBundleConfig) *ApplicationServices {
 	if appServices.checkpointBuffer == nil {
 		appServices.checkpointBuffer = request.NewAstraS3Buffer(ctx, config, bundleConfig, map[string]string{})
+		appServices.store = storage.NewAstraCqlStore(klog.FromContext(ctx), bundleConfig)
 		appServices.workerConfig = models.FromBufferConfig(config.BufferConfig)
 	}
 
@@ -43,6 +60,7 @@ func (appServices *ApplicationServices) WithAstraS3Buffer(ctx context.Context, c
 func (appServices *ApplicationServices) WithScyllaS3Buffer(ctx context.Context, config *request.S3BufferConfig, scyllaConfig *request.ScyllaCqlStoreConfig) *ApplicationServices {
 	if appServices.checkpointBuffer == nil {
 		appServices.checkpointBuffer = request.NewScyllaS3Buffer(ctx, config, scyllaConfig, map[string]string{})
+		appServices.store = storage.NewScyllaCqlStore(klog.FromContext(ctx), scyllaConfig)
 		appServices.workerConfig = models.FromBufferConfig(config.BufferConfig)
 	}
 
@@ -83,11 +101,46 @@ func (appServices *ApplicationServices) WithShards(ctx context.Context, shardCon
 			logger.Error(shardLoaderError, "unable to initialize shard clients")
 			klog.FlushAndExit(klog.ExitFlushTimeout, 1)
 		}
+
+		appServices.shardKubeClients, shardLoaderError = loadShardKubeClients(shardConfigPath)
+		if shardLoaderError != nil {
+			logger.Error(shardLoaderError, "unable to initialize shard Kubernetes clients")
+			klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+		}
 	}
 
 	return appServices
 }
 
+// loadShardKubeClients creates Kubernetes clientsets from shard kubeconfig files, keyed by shard name the same way as shard clients
+func loadShardKubeClients(shardConfigPath string) (map[string]kubernetes.Interface, error) {
+	files, err := os.ReadDir(shardConfigPath)
+	if err != nil { // coverage-ignore
+		return nil, err
+	}
+
+	clients := map[string]kubernetes.Interface{}
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".kubeconfig") {
+			continue
+		}
+
+		cfg, err := clientcmd.BuildConfigFromFlags("", path.Join(shardConfigPath, file.Name()))
+		if err != nil { // coverage-ignore
+			return nil, err
+		}
+
+		client, err := kubernetes.NewForConfig(cfg)
+		if err != nil { // coverage-ignore
+			return nil, err
+		}
+
+		clients[strings.Split(file.Name(), ".")[0]] = client
+	}
+
+	return clients, nil
+}
+
 func (appServices *ApplicationServices) WithRuntimeNamespace(namespace string) *ApplicationServices {
 	appServices.runtimeNamespace = namespace
 	return appServices
@@ -98,6 +151,47 @@ func (appServices *ApplicationServices) WithDeployNamespace(namespace string) *A
 	return appServices
 }
 
+func (appServices *ApplicationServices) WithSchedulingRetry(config *models.SchedulingRetryConfig) *ApplicationServices {
+	appServices.retryConfig = config
+	return appServices
+}
+
+func (appServices *ApplicationServices) WithSupervisor(config *models.SupervisorConfig) *ApplicationServices {
+	appServices.supervisorConfig = config
+	return appServices
+}
+
+func (appServices *ApplicationServices) WithJobReconciliation(config *models.JobReconciliationConfig) *ApplicationServices {
+	appServices.reconciliationConfig = config
+	return appServices
+}
+
+func (appServices *ApplicationServices) WithObjectProxy(ctx context.Context, bufferConfig *request.S3BufferConfig, config *models.ProxyDownloadConfig) *ApplicationServices {
+	if appServices.objectProxy == nil && config.Enabled {
+		var err error
+		appServices.objectProxy, err = services.NewObjectProxy(bufferConfig, config)
+		if err != nil {
+			klog.FromContext(ctx).Error(err, "unable to initialize proxied downloads")
+			klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+		}
+	}
+
+	return appServices
+}
+
+func (appServices *ApplicationServices) WithPayloadUploads(ctx context.Context, bufferConfig *request.S3BufferConfig, config *models.PayloadUploadConfig) *ApplicationServices {
+	if appServices.payloadUploads == nil && config.Enabled {
+		var err error
+		appServices.payloadUploads, err = services.NewPayloadUploadStore(bufferConfig, config)
+		if err != nil {
+			klog.FromContext(ctx).Error(err, "unable to initialize payload uploads")
+			klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+		}
+	}
+
+	return appServices
+}
+
 func (appServices *ApplicationServices) WithRecorder(ctx context.Context) *ApplicationServices {
 	if appServices.recorder == nil {
 		logger := klog.FromContext(ctx)
@@ -126,12 +220,48 @@ func (appServices *ApplicationServices) WithCache(ctx context.Context) *Applicat
 	return appServices
 }
 
+// WithTenancy discovers templates and workgroups in tenant namespaces, if enabled. Requires the cache to be configured first
+func (appServices *ApplicationServices) WithTenancy(config *models.TenancyConfig) *ApplicationServices {
+	appServices.tenancyConfig = config
+	appServices.configCache = appServices.configCache.WithTenancy(config)
+	return appServices
+}
+
+// WithTemplateValidation enables consistency checks of templates in the resource cache. Requires the cache, the recorder and shards to be configured first
+func (appServices *ApplicationServices) WithTemplateValidation(config *models.TemplateValidationConfig) *ApplicationServices {
+	appServices.configCache = appServices.configCache.WithValidation(config, appServices.shardClients, appServices.shardKubeClients, appServices.recorder)
+	return appServices
+}
+
+// WithTemplateHistory records template revisions in the store, if enabled. Requires the cache and the store to be configured first
+func (appServices *ApplicationServices) WithTemplateHistory(ctx context.Context, config *models.TemplateHistoryConfig) *ApplicationServices {
+	if appServices.templateHistory == nil && config.Enabled {
+		appServices.templateHistory = services.NewTemplateHistory(appServices.store, klog.FromContext(ctx))
+		appServices.configCache = appServices.configCache.WithTemplateHistory(appServices.templateHistory)
+	}
+
+	return appServices
+}
+
+func (appServices *ApplicationServices) WithAlgorithmCatalog(ctx context.Context, config *models.AlgorithmCatalogConfig) *ApplicationServices {
+	if appServices.algorithmCatalog == nil {
+		appServices.algorithmCatalog = services.NewAlgorithmCatalog(appServices.configCache, appServices.kubeClient, config, klog.FromContext(ctx))
+	}
+
+	return appServices
+}
+
 func (appServices *ApplicationServices) BuildScheduler(ctx context.Context) *ApplicationServices {
 	logger := klog.FromContext(ctx)
 	var err error
 
 	appServices.scheduler, err = services.
-		NewRequestScheduler(appServices.workerConfig, appServices.kubeClient, appServices.shardClients, appServices.checkpointBuffer, appServices.runtimeNamespace, appServices.deployNamespace, logger, nil).
+		NewRequestScheduler(appServices.workerConfig, appServices.retryConfig, appServices.kubeClient, appServices.shardClients, appServices.checkpointBuffer, appServices.store, appServices.runtimeNamespace, appServices.deployNamespace, logger, nil).
+		WithSupervisor(appServices.supervisorConfig).
+		WithShardKubeClients(appServices.shardKubeClients).
+		WithJobReconciliation(appServices.reconciliationConfig).
+		WithEventRecorder(appServices.recorder, appServices.configCache).
+		WithTenancy(appServices.tenancyConfig).
 		Init(ctx)
 
 	if err != nil {
@@ -139,6 +269,12 @@ func (appServices *ApplicationServices) BuildScheduler(ctx context.Context) *App
 		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
 	}
 
+	appServices.submitter = services.
+		NewRunSubmitter(appServices.checkpointBuffer, appServices.configCache, appServices.scheduler, appServices.payloadUploads, appServices.recorder, logger).
+		WithTemplateHistory(appServices.templateHistory)
+
+	appServices.aliasedBuffer = services.NewAliasedBuffer(appServices.checkpointBuffer, appServices.configCache)
+
 	return appServices
 }
 
@@ -146,6 +282,15 @@ func (appServices *ApplicationServices) CheckpointBuffer() request.Buffer {
 	return appServices.checkpointBuffer
 }
 
+// AliasedBuffer returns the checkpoint buffer that looks runs up under both template names and their aliases, for API handlers
+func (appServices *ApplicationServices) AliasedBuffer() request.Buffer {
+	return appServices.aliasedBuffer
+}
+
+func (appServices *ApplicationServices) Store() storage.Store {
+	return appServices.store
+}
+
 func (appServices *ApplicationServices) Logger(ctx context.Context) klog.Logger {
 	return klog.FromContext(ctx)
 }
@@ -158,6 +303,20 @@ func (appServices *ApplicationServices) NexusClient() nexuscore.Interface {
 	return appServices.nexusClient
 }
 
+func (appServices *ApplicationServices) Recorder() record.EventRecorder {
+	return appServices.recorder
+}
+
+// ObjectProxy returns a proxy for run payload and result downloads, or nil if proxied downloads are disabled
+func (appServices *ApplicationServices) ObjectProxy() *services.ObjectProxy {
+	return appServices.objectProxy
+}
+
+// PayloadUploads returns a store for payloads uploaded ahead of run creation, or nil if payload uploads are disabled
+func (appServices *ApplicationServices) PayloadUploads() *services.PayloadUploadStore {
+	return appServices.payloadUploads
+}
+
 func (appServices *ApplicationServices) Cache() *services.NexusResourceCache {
 	return appServices.configCache
 }
@@ -170,6 +329,21 @@ func (appServices *ApplicationServices) Scheduler() *services.RequestScheduler {
 	return appServices.scheduler
 }
 
+// Submitter returns the service that creates runs for both the REST and the gRPC API
+func (appServices *ApplicationServices) Submitter() *services.RunSubmitter {
+	return appServices.submitter
+}
+
+// AlgorithmCatalog returns the service that lists algorithms to callers
+func (appServices *ApplicationServices) AlgorithmCatalog() *services.AlgorithmCatalog {
+	return appServices.algorithmCatalog
+}
+
+// TemplateHistory returns the service that records template revisions, or nil if template history is disabled
+func (appServices *ApplicationServices) TemplateHistory() *services.TemplateHistory {
+	return appServices.templateHistory
+}
+
 func (appServices *ApplicationServices) Start(ctx context.Context) {
 	logger := klog.FromContext(ctx)
 	err := appServices.configCache.Init(ctx)
@@ -22,46 +22,76 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/algorithm/v1/buffer/{algorithmName}/requests/{requestId}": {
+        "/algorithm/v1/algorithms": {
             "get": {
-                "description": "Retrieves a buffered metadata for a run",
+                "description": "Lists algorithms the caller is authorized to run, with their version, resources, retry behaviour and workgroup. Algorithms that reference a missing workgroup are listed with missingWorkgroup set, as their runs are rejected",
                 "produces": [
                     "application/json",
-                    "text/plain",
-                    "text/html"
+                    "text/plain"
                 ],
                 "tags": [
-                    "metadata"
+                    "algorithms"
                 ],
-                "summary": "Read a buffered run metadata (Kubernetes Job JSON)",
+                "summary": "List algorithms",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Algorithm name",
-                        "name": "algorithmName",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Request identifier",
-                        "name": "requestId",
-                        "in": "path",
-                        "required": true
+                        "description": "List only algorithms of a tenant. Algorithms of all tenants are listed if omitted",
+                        "name": "tenant",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AlgorithmCatalogEntry"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "string"
                         }
+                    }
+                }
+            }
+        },
+        "/algorithm/v1/algorithms/{algorithmName}": {
+            "get": {
+                "description": "Retrieves version, resources, retry behaviour and workgroup of an algorithm the caller is authorized to run",
+                "produces": [
+                    "application/json",
+                    "text/plain"
+                ],
+                "tags": [
+                    "algorithms"
+                ],
+                "summary": "Read an algorithm",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Algorithm name, or tenant/algorithm URL-encoded for algorithms of a tenant",
+                        "name": "algorithmName",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AlgorithmCatalogEntry"
+                        }
                     },
                     "401": {
                         "description": "Unauthorized",
@@ -74,25 +104,27 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/algorithm/v1/cancel/{algorithmName}/requests/{requestId}": {
-            "post": {
-                "description": "Interrupts the provided run id and cancels the execution tree if it exists",
-                "consumes": [
-                    "application/json"
-                ],
+        "/algorithm/v1/algorithms/{algorithmName}/revisions": {
+            "get": {
+                "description": "Lists recorded spec revisions of an algorithm template, latest first. Runs can be pinned to a revision with the templateRevision parameter",
                 "produces": [
                     "application/json",
-                    "text/plain",
-                    "text/html"
+                    "text/plain"
                 ],
                 "tags": [
-                    "cancellation"
+                    "algorithms"
                 ],
-                "summary": "Cancels an algorithm run",
+                "summary": "List algorithm template revisions",
                 "parameters": [
                     {
                         "type": "string",
@@ -100,35 +132,16 @@ const docTemplate = `{
                         "name": "algorithmName",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Request identifier",
-                        "name": "requestId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Cancellation configuration",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CancellationRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_SneaksAndData_nexus_api_v1_models.TemplateRevision"
+                            }
                         }
                     },
                     "401": {
@@ -152,18 +165,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/algorithm/v1/metadata/{algorithmName}/requests/{requestId}": {
+        "/algorithm/v1/algorithms/{algorithmName}/revisions/diff": {
             "get": {
-                "description": "Retrieves checkpointed metadata for a run",
+                "description": "Lists values that differ between two recorded spec revisions of an algorithm template. Paths refer to the JSON representation of the spec",
                 "produces": [
                     "application/json",
-                    "text/plain",
-                    "text/html"
+                    "text/plain"
                 ],
                 "tags": [
-                    "metadata"
+                    "algorithms"
                 ],
-                "summary": "Read a run metadata",
+                "summary": "Compare algorithm template revisions",
                 "parameters": [
                     {
                         "type": "string",
@@ -173,10 +185,17 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Request identifier",
-                        "name": "requestId",
-                        "in": "path",
+                        "type": "integer",
+                        "description": "Revision to compare from",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision to compare to",
+                        "name": "to",
+                        "in": "query",
                         "required": true
                     }
                 ],
@@ -184,7 +203,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.CheckpointedRequest"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TemplateSpecChange"
+                            }
                         }
                     },
                     "400": {
@@ -204,22 +226,28 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/algorithm/v1/payload/{algorithmName}/requests/{requestId}": {
+        "/algorithm/v1/buffer/{algorithmName}/requests/{requestId}": {
             "get": {
-                "description": "Retrieves payload sent by the client for the provided run",
+                "description": "Retrieves a buffered metadata for a run",
                 "produces": [
+                    "application/json",
                     "text/plain",
-                    "text/html",
-                    "application/octet-stream"
+                    "text/html"
                 ],
                 "tags": [
-                    "payload"
+                    "metadata"
                 ],
-                "summary": "Read a run payload",
+                "summary": "Read a buffered run metadata (Kubernetes Job JSON)",
                 "parameters": [
                     {
                         "type": "string",
@@ -243,12 +271,6 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "302": {
-                        "description": "Found",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
@@ -270,23 +292,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/algorithm/v1/results/tags/{requestTag}": {
+        "/algorithm/v1/cancel/operations/{operationId}": {
             "get": {
-                "description": "Read results of all runs with a matching tag",
+                "description": "Retrieves the status and progress of a bulk cancellation",
                 "produces": [
                     "application/json",
                     "text/plain",
                     "text/html"
                 ],
                 "tags": [
-                    "results"
+                    "cancellation"
                 ],
-                "summary": "Read run results by tag",
+                "summary": "Read a bulk cancellation progress",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Request tag assigned by a client",
-                        "name": "requestTag",
+                        "description": "Cancellation operation identifier",
+                        "name": "operationId",
                         "in": "path",
                         "required": true
                     }
@@ -295,10 +317,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.TaggedRequestResult"
-                            }
+                            "$ref": "#/definitions/models.CancellationOperation"
                         }
                     },
                     "400": {
@@ -312,43 +331,63 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/algorithm/v1/results/{algorithmName}/requests/{requestId}": {
-            "get": {
-                "description": "Retrieves a result for the provided run",
+        "/algorithm/v1/cancel/tags/{requestTag}": {
+            "post": {
+                "description": "Starts an asynchronous cancellation of all runs sharing the provided tag, within the provided tenant. By default, BUFFERED and RUNNING runs are cancelled. Progress can be tracked using the returned operation identifier.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json",
                     "text/plain",
                     "text/html"
                 ],
                 "tags": [
-                    "results"
+                    "cancellation"
                 ],
-                "summary": "Read a run result",
+                "summary": "Cancels all runs with the provided tag",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Algorithm name",
-                        "name": "algorithmName",
+                        "description": "Request tag",
+                        "name": "requestTag",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Request identifier",
-                        "name": "requestId",
-                        "in": "path",
-                        "required": true
+                        "description": "Tenant of algorithms the runs were submitted to. Not authenticated: tenants namespace tags, they do not isolate runs from callers of other tenants",
+                        "name": "tenant",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Cancellation configuration",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BulkCancellationRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/models.RequestResult"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -363,8 +402,8 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "string"
                         }
@@ -372,9 +411,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/algorithm/v1/run/{algorithmName}": {
+        "/algorithm/v1/cancel/{algorithmName}/requests": {
             "post": {
-                "description": "Accepts an algorithm payload and places it into a scheduling queue",
+                "description": "Starts an asynchronous cancellation of all algorithm runs in the provided lifecycle stages. By default, BUFFERED and RUNNING runs are cancelled. Progress can be tracked using the returned operation identifier.",
                 "consumes": [
                     "application/json"
                 ],
@@ -384,9 +423,9 @@ const docTemplate = `{
                     "text/html"
                 ],
                 "tags": [
-                    "run"
+                    "cancellation"
                 ],
-                "summary": "Create a new algorithm run",
+                "summary": "Cancels all runs of an algorithm",
                 "parameters": [
                     {
                         "type": "string",
@@ -396,19 +435,13 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Run configuration",
+                        "description": "Cancellation configuration",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.AlgorithmRequest"
+                            "$ref": "#/definitions/models.BulkCancellationRequest"
                         }
-                    },
-                    {
-                        "type": "string",
-                        "description": "If false, will buffer but not submit to the target cluster",
-                        "name": "dryRun",
-                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -441,66 +474,1335 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.AlgorithmRequest": {
-            "type": "object",
-            "required": [
-                "algorithmParameters"
-            ],
-            "properties": {
-                "algorithmParameters": {
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "customConfiguration": {
-                    "$ref": "#/definitions/v1.NexusAlgorithmSpec"
-                },
-                "parentRequest": {
-                    "$ref": "#/definitions/models.AlgorithmRequestRef"
-                },
-                "payloadValidFor": {
-                    "type": "string"
-                },
-                "requestApiVersion": {
-                    "type": "string"
-                },
-                "tag": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.AlgorithmRequestRef": {
-            "type": "object",
-            "required": [
-                "algorithmName",
-                "requestId"
-            ],
-            "properties": {
-                "algorithmName": {
-                    "type": "string"
-                },
-                "requestId": {
-                    "type": "string"
-                }
-            }
         },
-        "models.CancellationRequest": {
-            "type": "object",
-            "properties": {
-                "cancellationPolicy": {
-                    "type": "string"
-                },
-                "initiator": {
-                    "type": "string"
-                },
+        "/algorithm/v1/cancel/{algorithmName}/requests/{requestId}": {
+            "post": {
+                "description": "Interrupts the provided run id and cancels the execution tree if it exists. Descendant runs are cancelled as well, unless Orphan cancellation policy is used. Runs that are still waiting for submission (NEW or BUFFERED) are cancelled before a Job is created.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/plain",
+                    "text/html"
+                ],
+                "tags": [
+                    "cancellation"
+                ],
+                "summary": "Cancels an algorithm run",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Algorithm name",
+                        "name": "algorithmName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Request identifier",
+                        "name": "requestId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Cancellation configuration",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CancellationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/algorithm/v1/download/payload/{algorithmName}/requests/{requestId}": {
+            "get": {
+                "description": "Streams payload sent by the client for the provided run through the scheduler, for clients that cannot reach the object store. Supports HTTP Range requests",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "payload"
+                ],
+                "summary": "Download a run payload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Algorithm name",
+                        "name": "algorithmName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Request identifier",
+                        "name": "requestId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Byte range to download, for example bytes=0-1023",
+                        "name": "Range",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "416": {
+                        "description": "Requested Range Not Satisfiable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "417": {
+                        "description": "Expectation Failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/algorithm/v1/download/results/{algorithmName}/requests/{requestId}": {
+            "get": {
+                "description": "Streams the result of the provided run through the scheduler, for clients that cannot reach the object store. Only results stored under the configured result storage path are served. Supports HTTP Range requests",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "results"
+                ],
+                "summary": "Download a run result",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Algorithm name",
+                        "name": "algorithmName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Request identifier",
+                        "name": "requestId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Byte range to download, for example bytes=0-1023",
+                        "name": "Range",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "416": {
+                        "description": "Requested Range Not Satisfiable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "417": {
+                        "description": "Expectation Failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/algorithm/v1/logs/{algorithmName}/requests/{requestId}": {
+            "get": {
+                "description": "Streams container logs of all pods created for the provided run from the shard it was submitted to. Each container section starts with a ==\u003e pod/container \u003c== header",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "results"
+                ],
+                "summary": "Read run logs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Algorithm name",
+                        "name": "algorithmName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Request identifier",
+                        "name": "requestId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of lines from the end of each container log to return. All lines are returned if omitted",
+                        "name": "tailLines",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return logs of the previous container instance, for runs with restarted containers",
+                        "name": "previous",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/algorithm/v1/metadata/{algorithmName}/requests/{requestId}": {
+            "get": {
+                "description": "Retrieves checkpointed metadata for a run",
+                "produces": [
+                    "application/json",
+                    "text/plain",
+                    "text/html"
+                ],
+                "tags": [
+                    "metadata"
+                ],
+                "summary": "Read a run metadata",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Algorithm name",
+                        "name": "algorithmName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Request identifier",
+                        "name": "requestId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RunMetadata"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/algorithm/v1/openapi/algorithms": {
+            "get": {
+                "description": "Lists algorithms the caller is authorized to run, with a JSON Schema of their parameters if published by the algorithm template in the science.sneaksanddata.com/parameter-schema annotation",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "docs"
+                ],
+                "summary": "List deployed algorithms",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AlgorithmSchema"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/algorithm/v1/openapi/v2.json": {
+            "get": {
+                "description": "Returns the Swagger 2.0 specification of this API, with host and base path of the deployment",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "docs"
+                ],
+                "summary": "Read the Swagger 2.0 spec",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/algorithm/v1/openapi/v3.json": {
+            "get": {
+                "description": "Returns the OpenAPI v3 specification of this API, with a server URL of the deployment",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "docs"
+                ],
+                "summary": "Read the OpenAPI v3 spec",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/algorithm/v1/payload/{algorithmName}/requests/{requestId}": {
+            "get": {
+                "description": "Retrieves payload sent by the client for the provided run",
+                "produces": [
+                    "text/plain",
+                    "text/html",
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "payload"
+                ],
+                "summary": "Read a run payload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Algorithm name",
+                        "name": "algorithmName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Request identifier",
+                        "name": "requestId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "302": {
+                        "description": "Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/algorithm/v1/results/tags/{requestTag}": {
+            "get": {
+                "description": "Read results of all runs with a matching tag. Tags are scoped to a tenant, so runs of tenant algorithms are only matched if the tenant is provided",
+                "produces": [
+                    "application/json",
+                    "text/plain",
+                    "text/html"
+                ],
+                "tags": [
+                    "results"
+                ],
+                "summary": "Read run results by tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Request tag assigned by a client",
+                        "name": "requestTag",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant of algorithms the runs were submitted to. Not authenticated: tenants namespace tags, they do not isolate runs from callers of other tenants",
+                        "name": "tenant",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TaggedRequestResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/algorithm/v1/results/{algorithmName}/requests/{requestId}": {
+            "get": {
+                "description": "Retrieves a result for the provided run",
+                "produces": [
+                    "application/json",
+                    "text/plain",
+                    "text/html"
+                ],
+                "tags": [
+                    "results"
+                ],
+                "summary": "Read a run result",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Algorithm name",
+                        "name": "algorithmName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Request identifier",
+                        "name": "requestId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RequestResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/algorithm/v1/run/{algorithmName}": {
+            "post": {
+                "description": "Accepts an algorithm payload and places it into a scheduling queue. Templates with the result cache enabled complete identical submissions immediately with a result of a previous run. MessagePack, Protobuf (proto/nexus/v1/algorithm_request.proto) and binary payloads are stored in their original format and passed to the algorithm in the payloadReference parameter",
+                "consumes": [
+                    "application/json",
+                    "application/msgpack",
+                    "application/x-protobuf",
+                    "application/octet-stream"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/plain",
+                    "text/html"
+                ],
+                "tags": [
+                    "run"
+                ],
+                "summary": "Create a new algorithm run",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Algorithm name. Algorithms of a tenant are addressed as tenant/algorithm, with the slash URL-encoded",
+                        "name": "algorithmName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Run configuration",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AlgorithmRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "If false, will buffer but not submit to the target cluster",
+                        "name": "dryRun",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Maximum time the run may wait in the queue before it is moved to DEADLINE_EXCEEDED, for example 10m. Cannot extend the limit set by the algorithm template",
+                        "name": "maxQueueTime",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upload id of a payload uploaded in advance. Algorithm receives a pre-signed URL to it in the payloadReference parameter",
+                        "name": "payloadUploadId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Recorded revision of the algorithm template to create the run from, instead of the current one",
+                        "name": "templateRevision",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Runs with the same routing key are routed to the same variant of an algorithm with a traffic split",
+                        "name": "routingKey",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Encoding of a compressed payload, gzip or zstd",
+                        "name": "Content-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Algorithm parameters as a JSON object, for application/octet-stream payloads",
+                        "name": "Nexus-Algorithm-Parameters",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Run tag, for application/octet-stream payloads",
+                        "name": "Nexus-Tag",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Parent run as algorithmName/requestId, for application/octet-stream payloads",
+                        "name": "Nexus-Parent-Request",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Payload validity period, for application/octet-stream payloads",
+                        "name": "Nexus-Payload-Valid-For",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/algorithm/v1/timeline/{algorithmName}/requests/{requestId}": {
+            "get": {
+                "description": "Retrieves checkpoint lifecycle transitions of the provided run merged with Kubernetes events for its Job and pods from the shard it was submitted to, ordered by time",
+                "produces": [
+                    "application/json",
+                    "text/plain"
+                ],
+                "tags": [
+                    "metadata"
+                ],
+                "summary": "Read a run timeline",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Algorithm name",
+                        "name": "algorithmName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Request identifier",
+                        "name": "requestId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RunTimelineEvent"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/algorithm/v1/upload/{algorithmName}": {
+            "post": {
+                "description": "Allocates a location in the payload storage and returns a pre-signed POST form to upload a large payload with. Send uploadFields and then the payload as the file field in a multipart/form-data POST to uploadUrl. Uploads larger than the maximum payload size are rejected by the object store. Pass the returned uploadId to CreateRun as payloadUploadId",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "upload"
+                ],
+                "summary": "Create a payload upload slot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Algorithm name",
+                        "name": "algorithmName",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PayloadUploadSlot"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/algorithm/v1/upload/{algorithmName}/{uploadId}": {
+            "put": {
+                "description": "Streams a request body, or the first file of a multipart/form-data body, into the upload slot. Compressed payloads are stored as is, with the encoding recorded. Pass the uploadId to CreateRun as payloadUploadId",
+                "consumes": [
+                    "application/octet-stream",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "upload"
+                ],
+                "summary": "Upload a payload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Algorithm name",
+                        "name": "algorithmName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upload identifier returned when creating an upload slot",
+                        "name": "uploadId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Encoding of a compressed payload, gzip or zstd",
+                        "name": "Content-Encoding",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PayloadObject"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/algorithm/v1/validation/algorithms": {
+            "get": {
+                "description": "Lists results of the latest consistency check of each algorithm template: workgroup and shard references, and service accounts, secrets and config maps on the shard if enabled",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "validation"
+                ],
+                "summary": "List template validation results",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "List only templates that failed validation",
+                        "name": "invalid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TemplateValidationStatus"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/algorithm/v1/validation/algorithms/{algorithmName}": {
+            "get": {
+                "description": "Retrieves the result of the latest consistency check of an algorithm template",
+                "produces": [
+                    "application/json",
+                    "text/plain"
+                ],
+                "tags": [
+                    "validation"
+                ],
+                "summary": "Read a template validation result",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Algorithm name",
+                        "name": "algorithmName",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TemplateValidationStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "github_com_SneaksAndData_nexus_api_v1_models.TemplateRevision": {
+            "type": "object",
+            "properties": {
+                "algorithmName": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "resourceVersion": {
+                    "type": "string"
+                },
+                "revision": {
+                    "type": "integer"
+                },
+                "spec": {
+                    "$ref": "#/definitions/v1.NexusAlgorithmSpec"
+                }
+            }
+        },
+        "models.AlgorithmCatalogEntry": {
+            "type": "object",
+            "properties": {
+                "cluster": {
+                    "type": "string"
+                },
+                "computeResources": {
+                    "$ref": "#/definitions/v1.NexusAlgorithmResources"
+                },
+                "deadlineSeconds": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "errorHandlingBehaviour": {
+                    "$ref": "#/definitions/v1.NexusErrorHandlingBehaviour"
+                },
+                "maximumRetries": {
+                    "type": "integer"
+                },
+                "missingWorkgroup": {
+                    "description": "MissingWorkgroup is true if the template references a workgroup that does not exist, so runs of the algorithm are rejected",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parameterSchema": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "tenant": {
+                    "type": "string"
+                },
+                "versionTag": {
+                    "type": "string"
+                },
+                "workgroup": {
+                    "type": "string"
+                },
+                "workgroupDescription": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AlgorithmRequest": {
+            "type": "object",
+            "required": [
+                "algorithmParameters"
+            ],
+            "properties": {
+                "algorithmParameters": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "customConfiguration": {
+                    "$ref": "#/definitions/v1.NexusAlgorithmSpec"
+                },
+                "parentRequest": {
+                    "$ref": "#/definitions/models.AlgorithmRequestRef"
+                },
+                "payloadValidFor": {
+                    "type": "string"
+                },
+                "requestApiVersion": {
+                    "type": "string"
+                },
+                "tag": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AlgorithmRequestRef": {
+            "type": "object",
+            "required": [
+                "algorithmName",
+                "requestId"
+            ],
+            "properties": {
+                "algorithmName": {
+                    "type": "string"
+                },
+                "requestId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AlgorithmSchema": {
+            "type": "object",
+            "properties": {
+                "algorithmName": {
+                    "type": "string"
+                },
+                "parameterSchema": {
+                    "description": "ParameterSchema is a JSON Schema of algorithmParameters, if published by the algorithm template",
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "models.BulkCancellationRequest": {
+            "type": "object",
+            "properties": {
+                "cancellationPolicy": {
+                    "type": "string"
+                },
+                "initiator": {
+                    "type": "string"
+                },
+                "lifecycleStages": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CancellationOperation": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "type": "string"
+                },
+                "cancelled": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "host": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "initiator": {
+                    "type": "string"
+                },
+                "lastError": {
+                    "type": "string"
+                },
+                "lastModified": {
+                    "type": "string"
+                },
+                "lifecycleStages": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "matched": {
+                    "type": "integer"
+                },
+                "policy": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tag": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CancellationRequest": {
+            "type": "object",
+            "properties": {
+                "cancellationPolicy": {
+                    "type": "string"
+                },
+                "initiator": {
+                    "type": "string"
+                },
                 "reason": {
                     "type": "string"
                 }
             }
         },
-        "models.CheckpointedRequest": {
+        "models.PayloadObject": {
+            "type": "object",
+            "properties": {
+                "contentEncoding": {
+                    "description": "ContentEncoding is set for payloads stored compressed",
+                    "type": "string"
+                },
+                "contentHash": {
+                    "type": "string"
+                },
+                "contentType": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "uploadId": {
+                    "type": "string"
+                },
+                "uri": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PayloadUploadSlot": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "uploadFields": {
+                    "description": "UploadFields are form fields to send in a multipart/form-data POST to the upload URL, followed by the payload as the file field",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "uploadId": {
+                    "type": "string"
+                },
+                "uploadUrl": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RequestResult": {
+            "type": "object",
+            "properties": {
+                "requestId": {
+                    "type": "string"
+                },
+                "resultUri": {
+                    "type": "string"
+                },
+                "runErrorMessage": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RunMetadata": {
             "type": "object",
             "properties": {
                 "algorithm": {
@@ -518,18 +1820,27 @@ const docTemplate = `{
                 "applied_configuration": {
                     "$ref": "#/definitions/v1.NexusAlgorithmSpec"
                 },
+                "completedAt": {
+                    "type": "string"
+                },
                 "configuration_overrides": {
                     "$ref": "#/definitions/v1.NexusAlgorithmSpec"
                 },
                 "content_hash": {
                     "type": "string"
                 },
+                "endToEndLatency": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
                 "job_uid": {
                     "type": "string"
                 },
+                "lastSchedulingError": {
+                    "type": "string"
+                },
                 "last_modified": {
                     "type": "string"
                 },
@@ -539,42 +1850,87 @@ const docTemplate = `{
                 "parent": {
                     "$ref": "#/definitions/models.AlgorithmRequestRef"
                 },
+                "payloadContentHash": {
+                    "type": "string"
+                },
+                "payloadContentType": {
+                    "type": "string"
+                },
+                "payloadEncoding": {
+                    "type": "string"
+                },
+                "payloadReference": {
+                    "type": "string"
+                },
+                "payloadSize": {
+                    "type": "integer"
+                },
                 "payload_uri": {
                     "type": "string"
                 },
                 "payload_valid_for": {
                     "type": "string"
                 },
+                "queueDeadline": {
+                    "type": "string"
+                },
+                "queueTime": {
+                    "type": "string"
+                },
                 "received_at": {
                     "type": "string"
                 },
                 "received_by_host": {
                     "type": "string"
                 },
+                "resultCache": {
+                    "type": "string"
+                },
+                "resultCacheSource": {
+                    "type": "string"
+                },
                 "result_uri": {
                     "type": "string"
                 },
+                "schedulingAttempts": {
+                    "type": "integer"
+                },
                 "sent_at": {
                     "type": "string"
                 },
+                "shard": {
+                    "type": "string"
+                },
                 "tag": {
                     "type": "string"
+                },
+                "templateVariant": {
+                    "type": "string"
                 }
             }
         },
-        "models.RequestResult": {
+        "models.RunTimelineEvent": {
             "type": "object",
             "properties": {
-                "requestId": {
+                "count": {
+                    "type": "integer"
+                },
+                "message": {
                     "type": "string"
                 },
-                "resultUri": {
+                "object": {
                     "type": "string"
                 },
-                "runErrorMessage": {
+                "reason": {
                     "type": "string"
                 },
-                "status": {
+                "source": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "type": {
                     "type": "string"
                 }
             }
@@ -599,6 +1955,40 @@ const docTemplate = `{
                 }
             }
         },
+        "models.TemplateSpecChange": {
+            "type": "object",
+            "properties": {
+                "from": {},
+                "path": {
+                    "description": "Path is a dot-separated path of the value in the JSON representation of the spec",
+                    "type": "string"
+                },
+                "to": {}
+            }
+        },
+        "models.TemplateValidationStatus": {
+            "type": "object",
+            "properties": {
+                "problems": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "resourceVersion": {
+                    "type": "string"
+                },
+                "template": {
+                    "type": "string"
+                },
+                "valid": {
+                    "type": "boolean"
+                },
+                "validatedAt": {
+                    "type": "string"
+                }
+            }
+        },
         "resource.Quantity": {
             "type": "object",
             "properties": {
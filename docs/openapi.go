@@ -0,0 +1,8 @@
+package docs
+
+import _ "embed"
+
+// OpenApiV3 is the OpenAPI v3 version of the spec, converted from swagger.json
+//
+//go:embed openapi.json
+var OpenApiV3 []byte
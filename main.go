@@ -3,13 +3,20 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	nexusconf "github.com/SneaksAndData/nexus-core/pkg/configurations"
 	"github.com/SneaksAndData/nexus-core/pkg/signals"
 	"github.com/SneaksAndData/nexus-core/pkg/telemetry"
+	grpcv1 "github.com/SneaksAndData/nexus/api/grpc/v1"
 	v1 "github.com/SneaksAndData/nexus/api/v1"
 	"github.com/SneaksAndData/nexus/app"
+	nexusv1 "github.com/SneaksAndData/nexus/proto/nexus/v1"
 	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"google.golang.org/grpc"
 	"k8s.io/klog/v2"
+	"net"
 	"os"
 )
 
@@ -18,6 +25,9 @@ func setupRouter(ctx context.Context, appConfig *app.SchedulerConfig) *gin.Engin
 	router := gin.Default()
 	router.MaxMultipartMemory = appConfig.MaxPayloadSizeBytes()
 	router.Use(gin.Logger())
+	// tenant-qualified algorithm names are passed URL-encoded in a single path segment
+	router.UseRawPath = true
+	router.UnescapePathValues = true
 	// disable trusted proxies check
 	_ = router.SetTrustedProxies(nil)
 	// set runtime mode
@@ -39,21 +49,79 @@ func setupRouter(ctx context.Context, appConfig *app.SchedulerConfig) *gin.Engin
 	appServices = appServices.
 		WithRuntimeNamespace(appConfig.RuntimeNamespace).
 		WithDeployNamespace(appConfig.DeployNamespace).
+		WithSchedulingRetry(&appConfig.SchedulingRetry).
+		WithSupervisor(&appConfig.Supervisor).
+		WithJobReconciliation(&appConfig.JobReconciliation).
 		WithCache(ctx).
+		WithTenancy(&appConfig.Tenancy).
+		WithTemplateHistory(ctx, &appConfig.TemplateHistory).
+		WithAlgorithmCatalog(ctx, &appConfig.AlgorithmCatalog).
 		WithRecorder(ctx).
 		WithShards(ctx, appConfig.ShardKubeConfigPath).
+		WithTemplateValidation(&appConfig.TemplateValidation).
+		WithObjectProxy(ctx, &appConfig.S3Buffer, &appConfig.ProxyDownload).
+		WithPayloadUploads(ctx, &appConfig.S3Buffer, &appConfig.PayloadUpload).
 		BuildScheduler(ctx)
 
 	// version 1
 	apiV1 := router.Group("algorithm/v1")
+	apiV1.Use(v1.AlgorithmAliasHeaders(appServices.Cache()))
 
-	apiV1.POST("run/:algorithmName", v1.CreateRun(appServices.CheckpointBuffer(), appServices.Cache(), appServices.Scheduler(), appServices.Logger(ctx)))
+	createRun := []gin.HandlerFunc{v1.CreateRun(appServices.Submitter(), appConfig.MaxPayloadSizeBytes())}
+	if appConfig.Compression.Enabled {
+		apiV1.Use(v1.CompressResponse(appConfig.Compression.MinResponseSizeBytes(), appServices.Logger(ctx)))
+		createRun = append([]gin.HandlerFunc{v1.DecompressRequest(appConfig.MaxPayloadSizeBytes())}, createRun...)
+	}
+
+	apiV1.POST("run/:algorithmName", createRun...)
 	apiV1.POST("cancel/:algorithmName/requests/:requestId", v1.CancelRun(appServices.Scheduler(), appServices.Logger(ctx)))
-	apiV1.GET("results/:algorithmName/requests/:requestId", v1.GetRunResult(appServices.CheckpointBuffer()))
-	apiV1.GET("results/tags/:requestTag", v1.GetRunResultsByTag(appServices.CheckpointBuffer(), appServices.Logger(ctx)))
-	apiV1.GET("metadata/:algorithmName/requests/:requestId", v1.GetRunMetadata(appServices.CheckpointBuffer()))
-	apiV1.GET("buffer/:algorithmName/requests/:requestId", v1.GetBufferedRunMetadata(appServices.CheckpointBuffer()))
-	apiV1.GET("payload/:algorithmName/requests/:requestId", v1.GetRunPayload(appServices.CheckpointBuffer()))
+	apiV1.POST("cancel/:algorithmName/requests", v1.CancelAlgorithmRuns(appServices.Scheduler(), appServices.Logger(ctx)))
+	apiV1.POST("cancel/tags/:requestTag", v1.CancelTaggedRuns(appServices.Scheduler(), appServices.Logger(ctx)))
+	apiV1.GET("cancel/operations/:operationId", v1.GetCancellationOperation(appServices.Scheduler()))
+	apiV1.GET("results/:algorithmName/requests/:requestId", v1.GetRunResult(appServices.AliasedBuffer()))
+	apiV1.GET("results/tags/:requestTag", v1.GetRunResultsByTag(appServices.CheckpointBuffer(), appServices.Scheduler(), appServices.Logger(ctx)))
+	apiV1.GET("metadata/:algorithmName/requests/:requestId", v1.GetRunMetadata(appServices.AliasedBuffer(), appServices.Store()))
+	apiV1.GET("buffer/:algorithmName/requests/:requestId", v1.GetBufferedRunMetadata(appServices.AliasedBuffer()))
+	apiV1.GET("timeline/:algorithmName/requests/:requestId", v1.GetRunTimeline(appServices.Scheduler(), appServices.Logger(ctx)))
+	apiV1.GET("payload/:algorithmName/requests/:requestId", v1.GetRunPayload(appServices.AliasedBuffer()))
+	apiV1.GET("logs/:algorithmName/requests/:requestId", v1.GetRunLogs(appServices.Scheduler(), appServices.Logger(ctx)))
+	apiV1.GET("algorithms", v1.ListAlgorithms(appServices.AlgorithmCatalog(), &appConfig.AlgorithmCatalog, appServices.Logger(ctx)))
+	apiV1.GET("algorithms/:algorithmName", v1.GetAlgorithm(appServices.AlgorithmCatalog(), &appConfig.AlgorithmCatalog, appServices.Logger(ctx)))
+
+	if history := appServices.TemplateHistory(); history != nil {
+		apiV1.GET("algorithms/:algorithmName/revisions", v1.ListTemplateRevisions(appServices.AlgorithmCatalog(), history, &appConfig.AlgorithmCatalog, appServices.Logger(ctx)))
+		apiV1.GET("algorithms/:algorithmName/revisions/diff", v1.DiffTemplateRevisions(appServices.AlgorithmCatalog(), history, &appConfig.AlgorithmCatalog, appServices.Logger(ctx)))
+	}
+
+	if appConfig.TemplateValidation.Enabled {
+		apiV1.GET("validation/algorithms", v1.ListTemplateValidation(appServices.Cache()))
+		apiV1.GET("validation/algorithms/:algorithmName", v1.GetTemplateValidation(appServices.Cache()))
+	}
+
+	if proxy := appServices.ObjectProxy(); proxy != nil {
+		apiV1.GET("download/results/:algorithmName/requests/:requestId", v1.DownloadRunResult(appServices.AliasedBuffer(), proxy, appServices.Logger(ctx)))
+		apiV1.GET("download/payload/:algorithmName/requests/:requestId", v1.DownloadRunPayload(appServices.AliasedBuffer(), proxy, appServices.Logger(ctx)))
+	}
+
+	if appConfig.OpenApi.Enabled {
+		apiV1.GET("openapi/v2.json", v1.GetOpenApiV2(&appConfig.OpenApi, appServices.Logger(ctx)))
+		apiV1.GET("openapi/v3.json", v1.GetOpenApiV3(&appConfig.OpenApi, appServices.Logger(ctx)))
+		apiV1.GET("openapi/algorithms", v1.GetAlgorithmSchemas(appServices.AlgorithmCatalog(), &appConfig.AlgorithmCatalog, appServices.Logger(ctx)))
+
+		if appConfig.OpenApi.UiEnabled {
+			// relative spec URL keeps the UI working behind an ingress path prefix
+			apiV1.GET("openapi/ui/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL("../v3.json")))
+		}
+	}
+
+	if uploads := appServices.PayloadUploads(); uploads != nil {
+		apiV1.POST("upload/:algorithmName", v1.CreatePayloadUpload(appServices.Cache(), uploads, appServices.Logger(ctx)))
+		apiV1.PUT("upload/:algorithmName/:uploadId", v1.UploadPayload(appServices.Cache(), uploads, appServices.Logger(ctx)))
+	}
+
+	if appConfig.Grpc.Enabled {
+		go serveGrpc(ctx, appConfig, appServices)
+	}
 
 	go func() {
 		appServices.Start(ctx)
@@ -72,6 +140,29 @@ func setupRouter(ctx context.Context, appConfig *app.SchedulerConfig) *gin.Engin
 	return router
 }
 
+// serveGrpc serves the gRPC API on a separate port, using the same services as the REST API
+func serveGrpc(ctx context.Context, appConfig *app.SchedulerConfig, appServices *app.ApplicationServices) {
+	logger := klog.FromContext(ctx)
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", appConfig.Grpc.Port))
+	if err != nil {
+		logger.Error(err, "unable to listen on the gRPC port", "port", appConfig.Grpc.Port)
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	}
+
+	server := grpc.NewServer(grpc.MaxRecvMsgSize(int(appConfig.MaxPayloadSizeBytes())))
+	nexusv1.RegisterSchedulerServer(server, grpcv1.NewSchedulerServer(appServices.AliasedBuffer(), appServices.Store(), appServices.Scheduler(), appServices.Submitter(), appConfig.Grpc.WatchInterval, appServices.Logger(ctx)))
+
+	go func() {
+		<-ctx.Done()
+		server.GracefulStop()
+	}()
+
+	if err := server.Serve(listener); err != nil {
+		logger.Error(err, "gRPC server stopped unexpectedly")
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	}
+}
+
 // @title           Nexus Scheduler API
 // @version         1.0
 // @description     Nexus Scheduler API specification. All Nexus supported clients conform to this spec.
@@ -98,6 +189,11 @@ func main() {
 
 	klog.SetSlogLogger(appLogger)
 
+	if err := appConfig.Validate(); err != nil {
+		logger.V(0).Error(err, "invalid configuration")
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	}
+
 	r := setupRouter(ctx, &appConfig)
 
 	// Configure webhost
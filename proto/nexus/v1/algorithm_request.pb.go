@@ -0,0 +1,235 @@
+// Protobuf encoding of a CreateRun request body, accepted with Content-Type: application/x-protobuf.
+// Field names follow the JSON request, so both encodings carry the same information.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: nexus/v1/algorithm_request.proto
+
+package nexusv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// AlgorithmRequestRef identifies a parent run
+type AlgorithmRequestRef struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestId     string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	AlgorithmName string                 `protobuf:"bytes,2,opt,name=algorithm_name,json=algorithmName,proto3" json:"algorithm_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlgorithmRequestRef) Reset() {
+	*x = AlgorithmRequestRef{}
+	mi := &file_nexus_v1_algorithm_request_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlgorithmRequestRef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlgorithmRequestRef) ProtoMessage() {}
+
+func (x *AlgorithmRequestRef) ProtoReflect() protoreflect.Message {
+	mi := &file_nexus_v1_algorithm_request_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlgorithmRequestRef.ProtoReflect.Descriptor instead.
+func (*AlgorithmRequestRef) Descriptor() ([]byte, []int) {
+	return file_nexus_v1_algorithm_request_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AlgorithmRequestRef) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *AlgorithmRequestRef) GetAlgorithmName() string {
+	if x != nil {
+		return x.AlgorithmName
+	}
+	return ""
+}
+
+// AlgorithmRequest is a run configuration. The scheduler stores the message as received and passes it to the algorithm by reference
+type AlgorithmRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	AlgorithmParameters *structpb.Struct       `protobuf:"bytes,1,opt,name=algorithm_parameters,json=algorithmParameters,proto3" json:"algorithm_parameters,omitempty"`
+	// custom_configuration follows the NexusAlgorithmSpec JSON schema
+	CustomConfiguration *structpb.Struct     `protobuf:"bytes,2,opt,name=custom_configuration,json=customConfiguration,proto3" json:"custom_configuration,omitempty"`
+	RequestApiVersion   string               `protobuf:"bytes,3,opt,name=request_api_version,json=requestApiVersion,proto3" json:"request_api_version,omitempty"`
+	Tag                 string               `protobuf:"bytes,4,opt,name=tag,proto3" json:"tag,omitempty"`
+	ParentRequest       *AlgorithmRequestRef `protobuf:"bytes,5,opt,name=parent_request,json=parentRequest,proto3" json:"parent_request,omitempty"`
+	PayloadValidFor     string               `protobuf:"bytes,6,opt,name=payload_valid_for,json=payloadValidFor,proto3" json:"payload_valid_for,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *AlgorithmRequest) Reset() {
+	*x = AlgorithmRequest{}
+	mi := &file_nexus_v1_algorithm_request_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlgorithmRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlgorithmRequest) ProtoMessage() {}
+
+func (x *AlgorithmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nexus_v1_algorithm_request_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlgorithmRequest.ProtoReflect.Descriptor instead.
+func (*AlgorithmRequest) Descriptor() ([]byte, []int) {
+	return file_nexus_v1_algorithm_request_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AlgorithmRequest) GetAlgorithmParameters() *structpb.Struct {
+	if x != nil {
+		return x.AlgorithmParameters
+	}
+	return nil
+}
+
+func (x *AlgorithmRequest) GetCustomConfiguration() *structpb.Struct {
+	if x != nil {
+		return x.CustomConfiguration
+	}
+	return nil
+}
+
+func (x *AlgorithmRequest) GetRequestApiVersion() string {
+	if x != nil {
+		return x.RequestApiVersion
+	}
+	return ""
+}
+
+func (x *AlgorithmRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *AlgorithmRequest) GetParentRequest() *AlgorithmRequestRef {
+	if x != nil {
+		return x.ParentRequest
+	}
+	return nil
+}
+
+func (x *AlgorithmRequest) GetPayloadValidFor() string {
+	if x != nil {
+		return x.PayloadValidFor
+	}
+	return ""
+}
+
+var File_nexus_v1_algorithm_request_proto protoreflect.FileDescriptor
+
+const file_nexus_v1_algorithm_request_proto_rawDesc = "" +
+	"\n" +
+	" nexus/v1/algorithm_request.proto\x12\bnexus.v1\x1a\x1cgoogle/protobuf/struct.proto\"[\n" +
+	"\x13AlgorithmRequestRef\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12%\n" +
+	"\x0ealgorithm_name\x18\x02 \x01(\tR\ralgorithmName\"\xde\x02\n" +
+	"\x10AlgorithmRequest\x12J\n" +
+	"\x14algorithm_parameters\x18\x01 \x01(\v2\x17.google.protobuf.StructR\x13algorithmParameters\x12J\n" +
+	"\x14custom_configuration\x18\x02 \x01(\v2\x17.google.protobuf.StructR\x13customConfiguration\x12.\n" +
+	"\x13request_api_version\x18\x03 \x01(\tR\x11requestApiVersion\x12\x10\n" +
+	"\x03tag\x18\x04 \x01(\tR\x03tag\x12D\n" +
+	"\x0eparent_request\x18\x05 \x01(\v2\x1d.nexus.v1.AlgorithmRequestRefR\rparentRequest\x12*\n" +
+	"\x11payload_valid_for\x18\x06 \x01(\tR\x0fpayloadValidForB7Z5github.com/SneaksAndData/nexus/proto/nexus/v1;nexusv1b\x06proto3"
+
+var (
+	file_nexus_v1_algorithm_request_proto_rawDescOnce sync.Once
+	file_nexus_v1_algorithm_request_proto_rawDescData []byte
+)
+
+func file_nexus_v1_algorithm_request_proto_rawDescGZIP() []byte {
+	file_nexus_v1_algorithm_request_proto_rawDescOnce.Do(func() {
+		file_nexus_v1_algorithm_request_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_nexus_v1_algorithm_request_proto_rawDesc), len(file_nexus_v1_algorithm_request_proto_rawDesc)))
+	})
+	return file_nexus_v1_algorithm_request_proto_rawDescData
+}
+
+var file_nexus_v1_algorithm_request_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_nexus_v1_algorithm_request_proto_goTypes = []any{
+	(*AlgorithmRequestRef)(nil), // 0: nexus.v1.AlgorithmRequestRef
+	(*AlgorithmRequest)(nil),    // 1: nexus.v1.AlgorithmRequest
+	(*structpb.Struct)(nil),     // 2: google.protobuf.Struct
+}
+var file_nexus_v1_algorithm_request_proto_depIdxs = []int32{
+	2, // 0: nexus.v1.AlgorithmRequest.algorithm_parameters:type_name -> google.protobuf.Struct
+	2, // 1: nexus.v1.AlgorithmRequest.custom_configuration:type_name -> google.protobuf.Struct
+	0, // 2: nexus.v1.AlgorithmRequest.parent_request:type_name -> nexus.v1.AlgorithmRequestRef
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_nexus_v1_algorithm_request_proto_init() }
+func file_nexus_v1_algorithm_request_proto_init() {
+	if File_nexus_v1_algorithm_request_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_nexus_v1_algorithm_request_proto_rawDesc), len(file_nexus_v1_algorithm_request_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_nexus_v1_algorithm_request_proto_goTypes,
+		DependencyIndexes: file_nexus_v1_algorithm_request_proto_depIdxs,
+		MessageInfos:      file_nexus_v1_algorithm_request_proto_msgTypes,
+	}.Build()
+	File_nexus_v1_algorithm_request_proto = out.File
+	file_nexus_v1_algorithm_request_proto_goTypes = nil
+	file_nexus_v1_algorithm_request_proto_depIdxs = nil
+}
@@ -0,0 +1,1107 @@
+// gRPC counterpart of the algorithm/v1 REST API. Messages follow models of the v1 swagger spec, so both APIs return the same information.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: nexus/v1/scheduler.proto
+
+package nexusv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreateRunRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AlgorithmName string                 `protobuf:"bytes,1,opt,name=algorithm_name,json=algorithmName,proto3" json:"algorithm_name,omitempty"`
+	Request       *AlgorithmRequest      `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`
+	// dry_run generates a Job without submitting it
+	DryRun bool `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// max_queue_time overrides the maximum queue time of the algorithm template
+	MaxQueueTime *durationpb.Duration `protobuf:"bytes,4,opt,name=max_queue_time,json=maxQueueTime,proto3" json:"max_queue_time,omitempty"`
+	// payload_upload_id references a payload uploaded ahead of run creation
+	PayloadUploadId string `protobuf:"bytes,5,opt,name=payload_upload_id,json=payloadUploadId,proto3" json:"payload_upload_id,omitempty"`
+	// template_revision pins the run to a recorded revision of the algorithm template, current revision is used if not set
+	TemplateRevision int64 `protobuf:"varint,6,opt,name=template_revision,json=templateRevision,proto3" json:"template_revision,omitempty"`
+	// routing_key routes runs with the same key to the same variant of an algorithm with a traffic split
+	RoutingKey    string `protobuf:"bytes,7,opt,name=routing_key,json=routingKey,proto3" json:"routing_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateRunRequest) Reset() {
+	*x = CreateRunRequest{}
+	mi := &file_nexus_v1_scheduler_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRunRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRunRequest) ProtoMessage() {}
+
+func (x *CreateRunRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nexus_v1_scheduler_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRunRequest.ProtoReflect.Descriptor instead.
+func (*CreateRunRequest) Descriptor() ([]byte, []int) {
+	return file_nexus_v1_scheduler_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateRunRequest) GetAlgorithmName() string {
+	if x != nil {
+		return x.AlgorithmName
+	}
+	return ""
+}
+
+func (x *CreateRunRequest) GetRequest() *AlgorithmRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *CreateRunRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *CreateRunRequest) GetMaxQueueTime() *durationpb.Duration {
+	if x != nil {
+		return x.MaxQueueTime
+	}
+	return nil
+}
+
+func (x *CreateRunRequest) GetPayloadUploadId() string {
+	if x != nil {
+		return x.PayloadUploadId
+	}
+	return ""
+}
+
+func (x *CreateRunRequest) GetTemplateRevision() int64 {
+	if x != nil {
+		return x.TemplateRevision
+	}
+	return 0
+}
+
+func (x *CreateRunRequest) GetRoutingKey() string {
+	if x != nil {
+		return x.RoutingKey
+	}
+	return ""
+}
+
+type CreateRunResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestId     string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateRunResponse) Reset() {
+	*x = CreateRunResponse{}
+	mi := &file_nexus_v1_scheduler_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRunResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRunResponse) ProtoMessage() {}
+
+func (x *CreateRunResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_nexus_v1_scheduler_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRunResponse.ProtoReflect.Descriptor instead.
+func (*CreateRunResponse) Descriptor() ([]byte, []int) {
+	return file_nexus_v1_scheduler_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateRunResponse) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type CancelRunRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AlgorithmName string                 `protobuf:"bytes,1,opt,name=algorithm_name,json=algorithmName,proto3" json:"algorithm_name,omitempty"`
+	RequestId     string                 `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Initiator     string                 `protobuf:"bytes,3,opt,name=initiator,proto3" json:"initiator,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	// cancellation_policy is one of Background (default), Orphan or Foreground
+	CancellationPolicy string `protobuf:"bytes,5,opt,name=cancellation_policy,json=cancellationPolicy,proto3" json:"cancellation_policy,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CancelRunRequest) Reset() {
+	*x = CancelRunRequest{}
+	mi := &file_nexus_v1_scheduler_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelRunRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelRunRequest) ProtoMessage() {}
+
+func (x *CancelRunRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nexus_v1_scheduler_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelRunRequest.ProtoReflect.Descriptor instead.
+func (*CancelRunRequest) Descriptor() ([]byte, []int) {
+	return file_nexus_v1_scheduler_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CancelRunRequest) GetAlgorithmName() string {
+	if x != nil {
+		return x.AlgorithmName
+	}
+	return ""
+}
+
+func (x *CancelRunRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *CancelRunRequest) GetInitiator() string {
+	if x != nil {
+		return x.Initiator
+	}
+	return ""
+}
+
+func (x *CancelRunRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *CancelRunRequest) GetCancellationPolicy() string {
+	if x != nil {
+		return x.CancellationPolicy
+	}
+	return ""
+}
+
+type CancelRunResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelRunResponse) Reset() {
+	*x = CancelRunResponse{}
+	mi := &file_nexus_v1_scheduler_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelRunResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelRunResponse) ProtoMessage() {}
+
+func (x *CancelRunResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_nexus_v1_scheduler_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelRunResponse.ProtoReflect.Descriptor instead.
+func (*CancelRunResponse) Descriptor() ([]byte, []int) {
+	return file_nexus_v1_scheduler_proto_rawDescGZIP(), []int{3}
+}
+
+type GetRunResultRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AlgorithmName string                 `protobuf:"bytes,1,opt,name=algorithm_name,json=algorithmName,proto3" json:"algorithm_name,omitempty"`
+	RequestId     string                 `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRunResultRequest) Reset() {
+	*x = GetRunResultRequest{}
+	mi := &file_nexus_v1_scheduler_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRunResultRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRunResultRequest) ProtoMessage() {}
+
+func (x *GetRunResultRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nexus_v1_scheduler_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRunResultRequest.ProtoReflect.Descriptor instead.
+func (*GetRunResultRequest) Descriptor() ([]byte, []int) {
+	return file_nexus_v1_scheduler_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetRunResultRequest) GetAlgorithmName() string {
+	if x != nil {
+		return x.AlgorithmName
+	}
+	return ""
+}
+
+func (x *GetRunResultRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type RunResult struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	RequestId       string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	AlgorithmName   string                 `protobuf:"bytes,2,opt,name=algorithm_name,json=algorithmName,proto3" json:"algorithm_name,omitempty"`
+	Status          string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	ResultUri       string                 `protobuf:"bytes,4,opt,name=result_uri,json=resultUri,proto3" json:"result_uri,omitempty"`
+	RunErrorMessage string                 `protobuf:"bytes,5,opt,name=run_error_message,json=runErrorMessage,proto3" json:"run_error_message,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RunResult) Reset() {
+	*x = RunResult{}
+	mi := &file_nexus_v1_scheduler_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunResult) ProtoMessage() {}
+
+func (x *RunResult) ProtoReflect() protoreflect.Message {
+	mi := &file_nexus_v1_scheduler_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunResult.ProtoReflect.Descriptor instead.
+func (*RunResult) Descriptor() ([]byte, []int) {
+	return file_nexus_v1_scheduler_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RunResult) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *RunResult) GetAlgorithmName() string {
+	if x != nil {
+		return x.AlgorithmName
+	}
+	return ""
+}
+
+func (x *RunResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *RunResult) GetResultUri() string {
+	if x != nil {
+		return x.ResultUri
+	}
+	return ""
+}
+
+func (x *RunResult) GetRunErrorMessage() string {
+	if x != nil {
+		return x.RunErrorMessage
+	}
+	return ""
+}
+
+type GetRunMetadataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AlgorithmName string                 `protobuf:"bytes,1,opt,name=algorithm_name,json=algorithmName,proto3" json:"algorithm_name,omitempty"`
+	RequestId     string                 `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRunMetadataRequest) Reset() {
+	*x = GetRunMetadataRequest{}
+	mi := &file_nexus_v1_scheduler_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRunMetadataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRunMetadataRequest) ProtoMessage() {}
+
+func (x *GetRunMetadataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nexus_v1_scheduler_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRunMetadataRequest.ProtoReflect.Descriptor instead.
+func (*GetRunMetadataRequest) Descriptor() ([]byte, []int) {
+	return file_nexus_v1_scheduler_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetRunMetadataRequest) GetAlgorithmName() string {
+	if x != nil {
+		return x.AlgorithmName
+	}
+	return ""
+}
+
+func (x *GetRunMetadataRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type GetRunsByTagRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Tag   string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	// tenant of algorithms the runs were submitted to, tags are scoped to a tenant.
+	// Tenant is not authenticated: tenants namespace tags, they do not isolate runs from callers of other tenants
+	Tenant        string `protobuf:"bytes,2,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRunsByTagRequest) Reset() {
+	*x = GetRunsByTagRequest{}
+	mi := &file_nexus_v1_scheduler_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRunsByTagRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRunsByTagRequest) ProtoMessage() {}
+
+func (x *GetRunsByTagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nexus_v1_scheduler_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRunsByTagRequest.ProtoReflect.Descriptor instead.
+func (*GetRunsByTagRequest) Descriptor() ([]byte, []int) {
+	return file_nexus_v1_scheduler_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetRunsByTagRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *GetRunsByTagRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+type GetRunsByTagResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*RunResult           `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRunsByTagResponse) Reset() {
+	*x = GetRunsByTagResponse{}
+	mi := &file_nexus_v1_scheduler_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRunsByTagResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRunsByTagResponse) ProtoMessage() {}
+
+func (x *GetRunsByTagResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_nexus_v1_scheduler_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRunsByTagResponse.ProtoReflect.Descriptor instead.
+func (*GetRunsByTagResponse) Descriptor() ([]byte, []int) {
+	return file_nexus_v1_scheduler_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetRunsByTagResponse) GetResults() []*RunResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type WatchRunRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AlgorithmName string                 `protobuf:"bytes,1,opt,name=algorithm_name,json=algorithmName,proto3" json:"algorithm_name,omitempty"`
+	RequestId     string                 `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRunRequest) Reset() {
+	*x = WatchRunRequest{}
+	mi := &file_nexus_v1_scheduler_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRunRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRunRequest) ProtoMessage() {}
+
+func (x *WatchRunRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nexus_v1_scheduler_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRunRequest.ProtoReflect.Descriptor instead.
+func (*WatchRunRequest) Descriptor() ([]byte, []int) {
+	return file_nexus_v1_scheduler_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WatchRunRequest) GetAlgorithmName() string {
+	if x != nil {
+		return x.AlgorithmName
+	}
+	return ""
+}
+
+func (x *WatchRunRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+// RunMetadata is a checkpointed run combined with attributes recorded by the scheduler
+type RunMetadata struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	Algorithm               string                 `protobuf:"bytes,1,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	Id                      string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	LifecycleStage          string                 `protobuf:"bytes,3,opt,name=lifecycle_stage,json=lifecycleStage,proto3" json:"lifecycle_stage,omitempty"`
+	PayloadUri              string                 `protobuf:"bytes,4,opt,name=payload_uri,json=payloadUri,proto3" json:"payload_uri,omitempty"`
+	ResultUri               string                 `protobuf:"bytes,5,opt,name=result_uri,json=resultUri,proto3" json:"result_uri,omitempty"`
+	AlgorithmFailureCause   string                 `protobuf:"bytes,6,opt,name=algorithm_failure_cause,json=algorithmFailureCause,proto3" json:"algorithm_failure_cause,omitempty"`
+	AlgorithmFailureDetails string                 `protobuf:"bytes,7,opt,name=algorithm_failure_details,json=algorithmFailureDetails,proto3" json:"algorithm_failure_details,omitempty"`
+	ReceivedByHost          string                 `protobuf:"bytes,8,opt,name=received_by_host,json=receivedByHost,proto3" json:"received_by_host,omitempty"`
+	ReceivedAt              *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=received_at,json=receivedAt,proto3" json:"received_at,omitempty"`
+	SentAt                  *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"`
+	// applied_configuration follows the NexusAlgorithmSpec JSON schema
+	AppliedConfiguration   *structpb.Struct       `protobuf:"bytes,11,opt,name=applied_configuration,json=appliedConfiguration,proto3" json:"applied_configuration,omitempty"`
+	ConfigurationOverrides *structpb.Struct       `protobuf:"bytes,12,opt,name=configuration_overrides,json=configurationOverrides,proto3" json:"configuration_overrides,omitempty"`
+	ContentHash            string                 `protobuf:"bytes,13,opt,name=content_hash,json=contentHash,proto3" json:"content_hash,omitempty"`
+	LastModified           *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=last_modified,json=lastModified,proto3" json:"last_modified,omitempty"`
+	Tag                    string                 `protobuf:"bytes,15,opt,name=tag,proto3" json:"tag,omitempty"`
+	ApiVersion             string                 `protobuf:"bytes,16,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"`
+	JobUid                 string                 `protobuf:"bytes,17,opt,name=job_uid,json=jobUid,proto3" json:"job_uid,omitempty"`
+	Parent                 *AlgorithmRequestRef   `protobuf:"bytes,18,opt,name=parent,proto3" json:"parent,omitempty"`
+	PayloadValidFor        string                 `protobuf:"bytes,19,opt,name=payload_valid_for,json=payloadValidFor,proto3" json:"payload_valid_for,omitempty"`
+	Shard                  string                 `protobuf:"bytes,20,opt,name=shard,proto3" json:"shard,omitempty"`
+	SchedulingAttempts     int32                  `protobuf:"varint,21,opt,name=scheduling_attempts,json=schedulingAttempts,proto3" json:"scheduling_attempts,omitempty"`
+	LastSchedulingError    string                 `protobuf:"bytes,22,opt,name=last_scheduling_error,json=lastSchedulingError,proto3" json:"last_scheduling_error,omitempty"`
+	QueueDeadline          *timestamppb.Timestamp `protobuf:"bytes,23,opt,name=queue_deadline,json=queueDeadline,proto3" json:"queue_deadline,omitempty"`
+	QueueTime              string                 `protobuf:"bytes,24,opt,name=queue_time,json=queueTime,proto3" json:"queue_time,omitempty"`
+	CompletedAt            *timestamppb.Timestamp `protobuf:"bytes,25,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	EndToEndLatency        string                 `protobuf:"bytes,26,opt,name=end_to_end_latency,json=endToEndLatency,proto3" json:"end_to_end_latency,omitempty"`
+	PayloadReference       string                 `protobuf:"bytes,27,opt,name=payload_reference,json=payloadReference,proto3" json:"payload_reference,omitempty"`
+	PayloadContentHash     string                 `protobuf:"bytes,28,opt,name=payload_content_hash,json=payloadContentHash,proto3" json:"payload_content_hash,omitempty"`
+	PayloadSize            int64                  `protobuf:"varint,29,opt,name=payload_size,json=payloadSize,proto3" json:"payload_size,omitempty"`
+	PayloadContentType     string                 `protobuf:"bytes,30,opt,name=payload_content_type,json=payloadContentType,proto3" json:"payload_content_type,omitempty"`
+	PayloadEncoding        string                 `protobuf:"bytes,31,opt,name=payload_encoding,json=payloadEncoding,proto3" json:"payload_encoding,omitempty"`
+	ResultCache            string                 `protobuf:"bytes,32,opt,name=result_cache,json=resultCache,proto3" json:"result_cache,omitempty"`
+	ResultCacheSource      string                 `protobuf:"bytes,33,opt,name=result_cache_source,json=resultCacheSource,proto3" json:"result_cache_source,omitempty"`
+	TemplateVariant        string                 `protobuf:"bytes,34,opt,name=template_variant,json=templateVariant,proto3" json:"template_variant,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *RunMetadata) Reset() {
+	*x = RunMetadata{}
+	mi := &file_nexus_v1_scheduler_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunMetadata) ProtoMessage() {}
+
+func (x *RunMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_nexus_v1_scheduler_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunMetadata.ProtoReflect.Descriptor instead.
+func (*RunMetadata) Descriptor() ([]byte, []int) {
+	return file_nexus_v1_scheduler_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RunMetadata) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetLifecycleStage() string {
+	if x != nil {
+		return x.LifecycleStage
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetPayloadUri() string {
+	if x != nil {
+		return x.PayloadUri
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetResultUri() string {
+	if x != nil {
+		return x.ResultUri
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetAlgorithmFailureCause() string {
+	if x != nil {
+		return x.AlgorithmFailureCause
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetAlgorithmFailureDetails() string {
+	if x != nil {
+		return x.AlgorithmFailureDetails
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetReceivedByHost() string {
+	if x != nil {
+		return x.ReceivedByHost
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetReceivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReceivedAt
+	}
+	return nil
+}
+
+func (x *RunMetadata) GetSentAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SentAt
+	}
+	return nil
+}
+
+func (x *RunMetadata) GetAppliedConfiguration() *structpb.Struct {
+	if x != nil {
+		return x.AppliedConfiguration
+	}
+	return nil
+}
+
+func (x *RunMetadata) GetConfigurationOverrides() *structpb.Struct {
+	if x != nil {
+		return x.ConfigurationOverrides
+	}
+	return nil
+}
+
+func (x *RunMetadata) GetContentHash() string {
+	if x != nil {
+		return x.ContentHash
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetLastModified() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastModified
+	}
+	return nil
+}
+
+func (x *RunMetadata) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetApiVersion() string {
+	if x != nil {
+		return x.ApiVersion
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetJobUid() string {
+	if x != nil {
+		return x.JobUid
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetParent() *AlgorithmRequestRef {
+	if x != nil {
+		return x.Parent
+	}
+	return nil
+}
+
+func (x *RunMetadata) GetPayloadValidFor() string {
+	if x != nil {
+		return x.PayloadValidFor
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetShard() string {
+	if x != nil {
+		return x.Shard
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetSchedulingAttempts() int32 {
+	if x != nil {
+		return x.SchedulingAttempts
+	}
+	return 0
+}
+
+func (x *RunMetadata) GetLastSchedulingError() string {
+	if x != nil {
+		return x.LastSchedulingError
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetQueueDeadline() *timestamppb.Timestamp {
+	if x != nil {
+		return x.QueueDeadline
+	}
+	return nil
+}
+
+func (x *RunMetadata) GetQueueTime() string {
+	if x != nil {
+		return x.QueueTime
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *RunMetadata) GetEndToEndLatency() string {
+	if x != nil {
+		return x.EndToEndLatency
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetPayloadReference() string {
+	if x != nil {
+		return x.PayloadReference
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetPayloadContentHash() string {
+	if x != nil {
+		return x.PayloadContentHash
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetPayloadSize() int64 {
+	if x != nil {
+		return x.PayloadSize
+	}
+	return 0
+}
+
+func (x *RunMetadata) GetPayloadContentType() string {
+	if x != nil {
+		return x.PayloadContentType
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetPayloadEncoding() string {
+	if x != nil {
+		return x.PayloadEncoding
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetResultCache() string {
+	if x != nil {
+		return x.ResultCache
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetResultCacheSource() string {
+	if x != nil {
+		return x.ResultCacheSource
+	}
+	return ""
+}
+
+func (x *RunMetadata) GetTemplateVariant() string {
+	if x != nil {
+		return x.TemplateVariant
+	}
+	return ""
+}
+
+var File_nexus_v1_scheduler_proto protoreflect.FileDescriptor
+
+const file_nexus_v1_scheduler_proto_rawDesc = "" +
+	"\n" +
+	"\x18nexus/v1/scheduler.proto\x12\bnexus.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a nexus/v1/algorithm_request.proto\"\xc3\x02\n" +
+	"\x10CreateRunRequest\x12%\n" +
+	"\x0ealgorithm_name\x18\x01 \x01(\tR\ralgorithmName\x124\n" +
+	"\arequest\x18\x02 \x01(\v2\x1a.nexus.v1.AlgorithmRequestR\arequest\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\x12?\n" +
+	"\x0emax_queue_time\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\fmaxQueueTime\x12*\n" +
+	"\x11payload_upload_id\x18\x05 \x01(\tR\x0fpayloadUploadId\x12+\n" +
+	"\x11template_revision\x18\x06 \x01(\x03R\x10templateRevision\x12\x1f\n" +
+	"\vrouting_key\x18\a \x01(\tR\n" +
+	"routingKey\"2\n" +
+	"\x11CreateRunResponse\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\"\xbf\x01\n" +
+	"\x10CancelRunRequest\x12%\n" +
+	"\x0ealgorithm_name\x18\x01 \x01(\tR\ralgorithmName\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x02 \x01(\tR\trequestId\x12\x1c\n" +
+	"\tinitiator\x18\x03 \x01(\tR\tinitiator\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12/\n" +
+	"\x13cancellation_policy\x18\x05 \x01(\tR\x12cancellationPolicy\"\x13\n" +
+	"\x11CancelRunResponse\"[\n" +
+	"\x13GetRunResultRequest\x12%\n" +
+	"\x0ealgorithm_name\x18\x01 \x01(\tR\ralgorithmName\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x02 \x01(\tR\trequestId\"\xb4\x01\n" +
+	"\tRunResult\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12%\n" +
+	"\x0ealgorithm_name\x18\x02 \x01(\tR\ralgorithmName\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"result_uri\x18\x04 \x01(\tR\tresultUri\x12*\n" +
+	"\x11run_error_message\x18\x05 \x01(\tR\x0frunErrorMessage\"]\n" +
+	"\x15GetRunMetadataRequest\x12%\n" +
+	"\x0ealgorithm_name\x18\x01 \x01(\tR\ralgorithmName\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x02 \x01(\tR\trequestId\"?\n" +
+	"\x13GetRunsByTagRequest\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\x12\x16\n" +
+	"\x06tenant\x18\x02 \x01(\tR\x06tenant\"E\n" +
+	"\x14GetRunsByTagResponse\x12-\n" +
+	"\aresults\x18\x01 \x03(\v2\x13.nexus.v1.RunResultR\aresults\"W\n" +
+	"\x0fWatchRunRequest\x12%\n" +
+	"\x0ealgorithm_name\x18\x01 \x01(\tR\ralgorithmName\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x02 \x01(\tR\trequestId\"\x8d\f\n" +
+	"\vRunMetadata\x12\x1c\n" +
+	"\talgorithm\x18\x01 \x01(\tR\talgorithm\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12'\n" +
+	"\x0flifecycle_stage\x18\x03 \x01(\tR\x0elifecycleStage\x12\x1f\n" +
+	"\vpayload_uri\x18\x04 \x01(\tR\n" +
+	"payloadUri\x12\x1d\n" +
+	"\n" +
+	"result_uri\x18\x05 \x01(\tR\tresultUri\x126\n" +
+	"\x17algorithm_failure_cause\x18\x06 \x01(\tR\x15algorithmFailureCause\x12:\n" +
+	"\x19algorithm_failure_details\x18\a \x01(\tR\x17algorithmFailureDetails\x12(\n" +
+	"\x10received_by_host\x18\b \x01(\tR\x0ereceivedByHost\x12;\n" +
+	"\vreceived_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"receivedAt\x123\n" +
+	"\asent_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\x06sentAt\x12L\n" +
+	"\x15applied_configuration\x18\v \x01(\v2\x17.google.protobuf.StructR\x14appliedConfiguration\x12P\n" +
+	"\x17configuration_overrides\x18\f \x01(\v2\x17.google.protobuf.StructR\x16configurationOverrides\x12!\n" +
+	"\fcontent_hash\x18\r \x01(\tR\vcontentHash\x12?\n" +
+	"\rlast_modified\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\flastModified\x12\x10\n" +
+	"\x03tag\x18\x0f \x01(\tR\x03tag\x12\x1f\n" +
+	"\vapi_version\x18\x10 \x01(\tR\n" +
+	"apiVersion\x12\x17\n" +
+	"\ajob_uid\x18\x11 \x01(\tR\x06jobUid\x125\n" +
+	"\x06parent\x18\x12 \x01(\v2\x1d.nexus.v1.AlgorithmRequestRefR\x06parent\x12*\n" +
+	"\x11payload_valid_for\x18\x13 \x01(\tR\x0fpayloadValidFor\x12\x14\n" +
+	"\x05shard\x18\x14 \x01(\tR\x05shard\x12/\n" +
+	"\x13scheduling_attempts\x18\x15 \x01(\x05R\x12schedulingAttempts\x122\n" +
+	"\x15last_scheduling_error\x18\x16 \x01(\tR\x13lastSchedulingError\x12A\n" +
+	"\x0equeue_deadline\x18\x17 \x01(\v2\x1a.google.protobuf.TimestampR\rqueueDeadline\x12\x1d\n" +
+	"\n" +
+	"queue_time\x18\x18 \x01(\tR\tqueueTime\x12=\n" +
+	"\fcompleted_at\x18\x19 \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12+\n" +
+	"\x12end_to_end_latency\x18\x1a \x01(\tR\x0fendToEndLatency\x12+\n" +
+	"\x11payload_reference\x18\x1b \x01(\tR\x10payloadReference\x120\n" +
+	"\x14payload_content_hash\x18\x1c \x01(\tR\x12payloadContentHash\x12!\n" +
+	"\fpayload_size\x18\x1d \x01(\x03R\vpayloadSize\x120\n" +
+	"\x14payload_content_type\x18\x1e \x01(\tR\x12payloadContentType\x12)\n" +
+	"\x10payload_encoding\x18\x1f \x01(\tR\x0fpayloadEncoding\x12!\n" +
+	"\fresult_cache\x18  \x01(\tR\vresultCache\x12.\n" +
+	"\x13result_cache_source\x18! \x01(\tR\x11resultCacheSource\x12)\n" +
+	"\x10template_variant\x18\" \x01(\tR\x0ftemplateVariant2\xb4\x03\n" +
+	"\tScheduler\x12D\n" +
+	"\tCreateRun\x12\x1a.nexus.v1.CreateRunRequest\x1a\x1b.nexus.v1.CreateRunResponse\x12D\n" +
+	"\tCancelRun\x12\x1a.nexus.v1.CancelRunRequest\x1a\x1b.nexus.v1.CancelRunResponse\x12B\n" +
+	"\fGetRunResult\x12\x1d.nexus.v1.GetRunResultRequest\x1a\x13.nexus.v1.RunResult\x12H\n" +
+	"\x0eGetRunMetadata\x12\x1f.nexus.v1.GetRunMetadataRequest\x1a\x15.nexus.v1.RunMetadata\x12M\n" +
+	"\fGetRunsByTag\x12\x1d.nexus.v1.GetRunsByTagRequest\x1a\x1e.nexus.v1.GetRunsByTagResponse\x12>\n" +
+	"\bWatchRun\x12\x19.nexus.v1.WatchRunRequest\x1a\x15.nexus.v1.RunMetadata0\x01B7Z5github.com/SneaksAndData/nexus/proto/nexus/v1;nexusv1b\x06proto3"
+
+var (
+	file_nexus_v1_scheduler_proto_rawDescOnce sync.Once
+	file_nexus_v1_scheduler_proto_rawDescData []byte
+)
+
+func file_nexus_v1_scheduler_proto_rawDescGZIP() []byte {
+	file_nexus_v1_scheduler_proto_rawDescOnce.Do(func() {
+		file_nexus_v1_scheduler_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_nexus_v1_scheduler_proto_rawDesc), len(file_nexus_v1_scheduler_proto_rawDesc)))
+	})
+	return file_nexus_v1_scheduler_proto_rawDescData
+}
+
+var file_nexus_v1_scheduler_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_nexus_v1_scheduler_proto_goTypes = []any{
+	(*CreateRunRequest)(nil),      // 0: nexus.v1.CreateRunRequest
+	(*CreateRunResponse)(nil),     // 1: nexus.v1.CreateRunResponse
+	(*CancelRunRequest)(nil),      // 2: nexus.v1.CancelRunRequest
+	(*CancelRunResponse)(nil),     // 3: nexus.v1.CancelRunResponse
+	(*GetRunResultRequest)(nil),   // 4: nexus.v1.GetRunResultRequest
+	(*RunResult)(nil),             // 5: nexus.v1.RunResult
+	(*GetRunMetadataRequest)(nil), // 6: nexus.v1.GetRunMetadataRequest
+	(*GetRunsByTagRequest)(nil),   // 7: nexus.v1.GetRunsByTagRequest
+	(*GetRunsByTagResponse)(nil),  // 8: nexus.v1.GetRunsByTagResponse
+	(*WatchRunRequest)(nil),       // 9: nexus.v1.WatchRunRequest
+	(*RunMetadata)(nil),           // 10: nexus.v1.RunMetadata
+	(*AlgorithmRequest)(nil),      // 11: nexus.v1.AlgorithmRequest
+	(*durationpb.Duration)(nil),   // 12: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil), // 13: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),       // 14: google.protobuf.Struct
+	(*AlgorithmRequestRef)(nil),   // 15: nexus.v1.AlgorithmRequestRef
+}
+var file_nexus_v1_scheduler_proto_depIdxs = []int32{
+	11, // 0: nexus.v1.CreateRunRequest.request:type_name -> nexus.v1.AlgorithmRequest
+	12, // 1: nexus.v1.CreateRunRequest.max_queue_time:type_name -> google.protobuf.Duration
+	5,  // 2: nexus.v1.GetRunsByTagResponse.results:type_name -> nexus.v1.RunResult
+	13, // 3: nexus.v1.RunMetadata.received_at:type_name -> google.protobuf.Timestamp
+	13, // 4: nexus.v1.RunMetadata.sent_at:type_name -> google.protobuf.Timestamp
+	14, // 5: nexus.v1.RunMetadata.applied_configuration:type_name -> google.protobuf.Struct
+	14, // 6: nexus.v1.RunMetadata.configuration_overrides:type_name -> google.protobuf.Struct
+	13, // 7: nexus.v1.RunMetadata.last_modified:type_name -> google.protobuf.Timestamp
+	15, // 8: nexus.v1.RunMetadata.parent:type_name -> nexus.v1.AlgorithmRequestRef
+	13, // 9: nexus.v1.RunMetadata.queue_deadline:type_name -> google.protobuf.Timestamp
+	13, // 10: nexus.v1.RunMetadata.completed_at:type_name -> google.protobuf.Timestamp
+	0,  // 11: nexus.v1.Scheduler.CreateRun:input_type -> nexus.v1.CreateRunRequest
+	2,  // 12: nexus.v1.Scheduler.CancelRun:input_type -> nexus.v1.CancelRunRequest
+	4,  // 13: nexus.v1.Scheduler.GetRunResult:input_type -> nexus.v1.GetRunResultRequest
+	6,  // 14: nexus.v1.Scheduler.GetRunMetadata:input_type -> nexus.v1.GetRunMetadataRequest
+	7,  // 15: nexus.v1.Scheduler.GetRunsByTag:input_type -> nexus.v1.GetRunsByTagRequest
+	9,  // 16: nexus.v1.Scheduler.WatchRun:input_type -> nexus.v1.WatchRunRequest
+	1,  // 17: nexus.v1.Scheduler.CreateRun:output_type -> nexus.v1.CreateRunResponse
+	3,  // 18: nexus.v1.Scheduler.CancelRun:output_type -> nexus.v1.CancelRunResponse
+	5,  // 19: nexus.v1.Scheduler.GetRunResult:output_type -> nexus.v1.RunResult
+	10, // 20: nexus.v1.Scheduler.GetRunMetadata:output_type -> nexus.v1.RunMetadata
+	8,  // 21: nexus.v1.Scheduler.GetRunsByTag:output_type -> nexus.v1.GetRunsByTagResponse
+	10, // 22: nexus.v1.Scheduler.WatchRun:output_type -> nexus.v1.RunMetadata
+	17, // [17:23] is the sub-list for method output_type
+	11, // [11:17] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_nexus_v1_scheduler_proto_init() }
+func file_nexus_v1_scheduler_proto_init() {
+	if File_nexus_v1_scheduler_proto != nil {
+		return
+	}
+	file_nexus_v1_algorithm_request_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_nexus_v1_scheduler_proto_rawDesc), len(file_nexus_v1_scheduler_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_nexus_v1_scheduler_proto_goTypes,
+		DependencyIndexes: file_nexus_v1_scheduler_proto_depIdxs,
+		MessageInfos:      file_nexus_v1_scheduler_proto_msgTypes,
+	}.Build()
+	File_nexus_v1_scheduler_proto = out.File
+	file_nexus_v1_scheduler_proto_goTypes = nil
+	file_nexus_v1_scheduler_proto_depIdxs = nil
+}
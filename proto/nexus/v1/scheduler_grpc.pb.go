@@ -0,0 +1,333 @@
+// gRPC counterpart of the algorithm/v1 REST API. Messages follow models of the v1 swagger spec, so both APIs return the same information.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: nexus/v1/scheduler.proto
+
+package nexusv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Scheduler_CreateRun_FullMethodName      = "/nexus.v1.Scheduler/CreateRun"
+	Scheduler_CancelRun_FullMethodName      = "/nexus.v1.Scheduler/CancelRun"
+	Scheduler_GetRunResult_FullMethodName   = "/nexus.v1.Scheduler/GetRunResult"
+	Scheduler_GetRunMetadata_FullMethodName = "/nexus.v1.Scheduler/GetRunMetadata"
+	Scheduler_GetRunsByTag_FullMethodName   = "/nexus.v1.Scheduler/GetRunsByTag"
+	Scheduler_WatchRun_FullMethodName       = "/nexus.v1.Scheduler/WatchRun"
+)
+
+// SchedulerClient is the client API for Scheduler service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Scheduler creates, cancels and tracks algorithm runs
+type SchedulerClient interface {
+	// CreateRun buffers a new algorithm run, same as POST /algorithm/v1/run/{algorithmName}
+	CreateRun(ctx context.Context, in *CreateRunRequest, opts ...grpc.CallOption) (*CreateRunResponse, error)
+	// CancelRun cancels a run and its descendants, same as POST /algorithm/v1/cancel/{algorithmName}/requests/{requestId}
+	CancelRun(ctx context.Context, in *CancelRunRequest, opts ...grpc.CallOption) (*CancelRunResponse, error)
+	// GetRunResult reads a run result, same as GET /algorithm/v1/results/{algorithmName}/requests/{requestId}
+	GetRunResult(ctx context.Context, in *GetRunResultRequest, opts ...grpc.CallOption) (*RunResult, error)
+	// GetRunMetadata reads checkpointed metadata of a run, same as GET /algorithm/v1/metadata/{algorithmName}/requests/{requestId}
+	GetRunMetadata(ctx context.Context, in *GetRunMetadataRequest, opts ...grpc.CallOption) (*RunMetadata, error)
+	// GetRunsByTag reads results of all runs with a matching tag, same as GET /algorithm/v1/results/tags/{requestTag}
+	GetRunsByTag(ctx context.Context, in *GetRunsByTagRequest, opts ...grpc.CallOption) (*GetRunsByTagResponse, error)
+	// WatchRun streams run metadata every time the run changes, and completes once the run has finished
+	WatchRun(ctx context.Context, in *WatchRunRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RunMetadata], error)
+}
+
+type schedulerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSchedulerClient(cc grpc.ClientConnInterface) SchedulerClient {
+	return &schedulerClient{cc}
+}
+
+func (c *schedulerClient) CreateRun(ctx context.Context, in *CreateRunRequest, opts ...grpc.CallOption) (*CreateRunResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateRunResponse)
+	err := c.cc.Invoke(ctx, Scheduler_CreateRun_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerClient) CancelRun(ctx context.Context, in *CancelRunRequest, opts ...grpc.CallOption) (*CancelRunResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelRunResponse)
+	err := c.cc.Invoke(ctx, Scheduler_CancelRun_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerClient) GetRunResult(ctx context.Context, in *GetRunResultRequest, opts ...grpc.CallOption) (*RunResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RunResult)
+	err := c.cc.Invoke(ctx, Scheduler_GetRunResult_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerClient) GetRunMetadata(ctx context.Context, in *GetRunMetadataRequest, opts ...grpc.CallOption) (*RunMetadata, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RunMetadata)
+	err := c.cc.Invoke(ctx, Scheduler_GetRunMetadata_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerClient) GetRunsByTag(ctx context.Context, in *GetRunsByTagRequest, opts ...grpc.CallOption) (*GetRunsByTagResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRunsByTagResponse)
+	err := c.cc.Invoke(ctx, Scheduler_GetRunsByTag_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerClient) WatchRun(ctx context.Context, in *WatchRunRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RunMetadata], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Scheduler_ServiceDesc.Streams[0], Scheduler_WatchRun_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRunRequest, RunMetadata]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Scheduler_WatchRunClient = grpc.ServerStreamingClient[RunMetadata]
+
+// SchedulerServer is the server API for Scheduler service.
+// All implementations must embed UnimplementedSchedulerServer
+// for forward compatibility.
+//
+// Scheduler creates, cancels and tracks algorithm runs
+type SchedulerServer interface {
+	// CreateRun buffers a new algorithm run, same as POST /algorithm/v1/run/{algorithmName}
+	CreateRun(context.Context, *CreateRunRequest) (*CreateRunResponse, error)
+	// CancelRun cancels a run and its descendants, same as POST /algorithm/v1/cancel/{algorithmName}/requests/{requestId}
+	CancelRun(context.Context, *CancelRunRequest) (*CancelRunResponse, error)
+	// GetRunResult reads a run result, same as GET /algorithm/v1/results/{algorithmName}/requests/{requestId}
+	GetRunResult(context.Context, *GetRunResultRequest) (*RunResult, error)
+	// GetRunMetadata reads checkpointed metadata of a run, same as GET /algorithm/v1/metadata/{algorithmName}/requests/{requestId}
+	GetRunMetadata(context.Context, *GetRunMetadataRequest) (*RunMetadata, error)
+	// GetRunsByTag reads results of all runs with a matching tag, same as GET /algorithm/v1/results/tags/{requestTag}
+	GetRunsByTag(context.Context, *GetRunsByTagRequest) (*GetRunsByTagResponse, error)
+	// WatchRun streams run metadata every time the run changes, and completes once the run has finished
+	WatchRun(*WatchRunRequest, grpc.ServerStreamingServer[RunMetadata]) error
+	mustEmbedUnimplementedSchedulerServer()
+}
+
+// UnimplementedSchedulerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSchedulerServer struct{}
+
+func (UnimplementedSchedulerServer) CreateRun(context.Context, *CreateRunRequest) (*CreateRunResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateRun not implemented")
+}
+func (UnimplementedSchedulerServer) CancelRun(context.Context, *CancelRunRequest) (*CancelRunResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelRun not implemented")
+}
+func (UnimplementedSchedulerServer) GetRunResult(context.Context, *GetRunResultRequest) (*RunResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRunResult not implemented")
+}
+func (UnimplementedSchedulerServer) GetRunMetadata(context.Context, *GetRunMetadataRequest) (*RunMetadata, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRunMetadata not implemented")
+}
+func (UnimplementedSchedulerServer) GetRunsByTag(context.Context, *GetRunsByTagRequest) (*GetRunsByTagResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRunsByTag not implemented")
+}
+func (UnimplementedSchedulerServer) WatchRun(*WatchRunRequest, grpc.ServerStreamingServer[RunMetadata]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchRun not implemented")
+}
+func (UnimplementedSchedulerServer) mustEmbedUnimplementedSchedulerServer() {}
+func (UnimplementedSchedulerServer) testEmbeddedByValue()                   {}
+
+// UnsafeSchedulerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SchedulerServer will
+// result in compilation errors.
+type UnsafeSchedulerServer interface {
+	mustEmbedUnimplementedSchedulerServer()
+}
+
+func RegisterSchedulerServer(s grpc.ServiceRegistrar, srv SchedulerServer) {
+	// If the following call pancis, it indicates UnimplementedSchedulerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Scheduler_ServiceDesc, srv)
+}
+
+func _Scheduler_CreateRun_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRunRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServer).CreateRun(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Scheduler_CreateRun_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServer).CreateRun(ctx, req.(*CreateRunRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Scheduler_CancelRun_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelRunRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServer).CancelRun(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Scheduler_CancelRun_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServer).CancelRun(ctx, req.(*CancelRunRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Scheduler_GetRunResult_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRunResultRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServer).GetRunResult(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Scheduler_GetRunResult_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServer).GetRunResult(ctx, req.(*GetRunResultRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Scheduler_GetRunMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRunMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServer).GetRunMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Scheduler_GetRunMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServer).GetRunMetadata(ctx, req.(*GetRunMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Scheduler_GetRunsByTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRunsByTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServer).GetRunsByTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Scheduler_GetRunsByTag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServer).GetRunsByTag(ctx, req.(*GetRunsByTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Scheduler_WatchRun_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRunRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SchedulerServer).WatchRun(m, &grpc.GenericServerStream[WatchRunRequest, RunMetadata]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Scheduler_WatchRunServer = grpc.ServerStreamingServer[RunMetadata]
+
+// Scheduler_ServiceDesc is the grpc.ServiceDesc for Scheduler service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Scheduler_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "nexus.v1.Scheduler",
+	HandlerType: (*SchedulerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateRun",
+			Handler:    _Scheduler_CreateRun_Handler,
+		},
+		{
+			MethodName: "CancelRun",
+			Handler:    _Scheduler_CancelRun_Handler,
+		},
+		{
+			MethodName: "GetRunResult",
+			Handler:    _Scheduler_GetRunResult_Handler,
+		},
+		{
+			MethodName: "GetRunMetadata",
+			Handler:    _Scheduler_GetRunMetadata_Handler,
+		},
+		{
+			MethodName: "GetRunsByTag",
+			Handler:    _Scheduler_GetRunsByTag_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchRun",
+			Handler:       _Scheduler_WatchRun_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "nexus/v1/scheduler.proto",
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	v1 "github.com/SneaksAndData/nexus-core/pkg/apis/science/v1"
+	coremodels "github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
+	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/request"
+	"github.com/SneaksAndData/nexus-core/pkg/resolvers"
+	"github.com/SneaksAndData/nexus-core/pkg/telemetry"
+	"github.com/SneaksAndData/nexus/services/models"
+	"github.com/SneaksAndData/nexus/storage"
+	"slices"
+	"sort"
+	"strconv"
+)
+
+const (
+	// AliasesAnnotation lists additional names of the annotated template, for example [{"name": "my-old-algorithm", "deprecation": "2025-01-01T00:00:00Z", "sunset": "2025-06-01T00:00:00Z"}]
+	AliasesAnnotation = "science.sneaksanddata.com/aliases"
+	// aliasIndex indexes templates by their namespace and aliases in the template informer
+	aliasIndex = "alias"
+)
+
+// TemplateAliases returns aliases defined by a template, or nil if it has none
+func TemplateAliases(template *v1.NexusAlgorithmTemplate) ([]*models.AlgorithmAlias, error) {
+	value, ok := template.Annotations[AliasesAnnotation]
+	if !ok || value == "" {
+		return nil, nil
+	}
+
+	aliases := []*models.AlgorithmAlias{}
+	if err := json.Unmarshal([]byte(value), &aliases); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation on template %s, a JSON array is expected: %w", AliasesAnnotation, template.Name, err)
+	}
+
+	for _, alias := range aliases {
+		if alias.Name == "" || alias.Name == template.Name {
+			return nil, fmt.Errorf("invalid %s annotation on template %s, each alias requires a name different from the template name", AliasesAnnotation, template.Name)
+		}
+	}
+
+	return aliases, nil
+}
+
+// indexAliases is an informer index function. Aliases are scoped to the namespace of the template.
+// Templates with invalid aliases are not indexed, since index functions must not fail - template validation reports them instead
+func indexAliases(obj interface{}) ([]string, error) {
+	template, ok := obj.(*v1.NexusAlgorithmTemplate)
+	if !ok {
+		return []string{}, nil
+	}
+
+	aliases, err := TemplateAliases(template)
+	if err != nil {
+		return []string{}, nil
+	}
+
+	names := []string{}
+	for _, alias := range aliases {
+		names = append(names, aliasKey(template.Namespace, alias.Name))
+	}
+
+	return names, nil
+}
+
+func aliasKey(namespace string, aliasName string) string {
+	return namespace + "/" + aliasName
+}
+
+// aliasTargets returns templates in a namespace that define an alias, sorted by name
+func (c *NexusResourceCache) aliasTargets(namespace string, aliasName string) ([]*v1.NexusAlgorithmTemplate, error) {
+	objects, err := c.templateInformer.GetIndexer().ByIndex(aliasIndex, aliasKey(namespace, aliasName))
+	if err != nil { // coverage-ignore
+		return nil, err
+	}
+
+	templates := []*v1.NexusAlgorithmTemplate{}
+	for _, object := range objects {
+		if template, ok := object.(*v1.NexusAlgorithmTemplate); ok {
+			templates = append(templates, template)
+		}
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+
+	return templates, nil
+}
+
+// ResolveAlgorithmAlias returns the template an alias refers to and the alias definition, or nil if the name is not an alias.
+// Template names take precedence over aliases, and if several templates of a tenant define the same alias, the first one by name is used
+func (c *NexusResourceCache) ResolveAlgorithmAlias(algorithmName string) (*v1.NexusAlgorithmTemplate, *models.AlgorithmAlias, error) {
+	tenant, aliasName := SplitAlgorithmName(algorithmName)
+	namespace, ok := c.tenantNamespace(tenant)
+	if !ok {
+		return nil, nil, nil
+	}
+
+	template, err := resolvers.GetCachedObject[v1.NexusAlgorithmTemplate](aliasName, namespace, c.templateInformer)
+	if err != nil || template != nil {
+		return nil, nil, err
+	}
+
+	targets, err := c.aliasTargets(namespace, aliasName)
+	if err != nil || len(targets) == 0 {
+		return nil, nil, err
+	}
+
+	aliases, _ := TemplateAliases(targets[0])
+	for _, alias := range aliases {
+		if alias.Name == aliasName {
+			return targets[0], alias, nil
+		}
+	}
+
+	return nil, nil, nil // coverage-ignore
+}
+
+// AlgorithmNames returns names runs of an algorithm can be stored under: the name itself, the template name if the name is an alias, and aliases of the template.
+// Runs created before a rename are stored under the old name, while runs created via an alias are stored under the template name
+func (c *NexusResourceCache) AlgorithmNames(algorithmName string) []string {
+	names := []string{RunAlgorithmName(algorithmName)}
+	template, err := c.GetAlgorithmConfiguration(algorithmName)
+	if err != nil || template == nil {
+		return names
+	}
+
+	if name := c.RunName(template); !slices.Contains(names, name) {
+		names = append(names, name)
+	}
+
+	aliases, _ := TemplateAliases(template)
+	for _, alias := range aliases {
+		if name := RunAlgorithmName(c.qualifiedName(template.Namespace, alias.Name)); !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// RecordAliasCall logs and meters a call made via an alias, so remaining clients of a deprecated name can be found before it is removed
+func (c *NexusResourceCache) RecordAliasCall(alias *models.AlgorithmAlias, algorithmName string) {
+	telemetry.Increment(c.metrics, "alias_call", map[string]string{"algorithm": algorithmName, "alias": alias.Name, "deprecated": strconv.FormatBool(alias.Deprecated())})
+
+	if alias.Deprecated() {
+		c.logger.V(0).Info("algorithm called via a deprecated alias", "algorithm", algorithmName, "alias", alias.Name, "deprecation", alias.Deprecation, "sunset", alias.Sunset)
+	}
+}
+
+// AliasedBuffer reads runs under all names of an algorithm, so results can be retrieved using either a template name or its alias
+type AliasedBuffer struct {
+	request.Buffer
+	configCache *NexusResourceCache
+}
+
+// NewAliasedBuffer wraps a checkpoint buffer with alias resolution for run lookups
+func NewAliasedBuffer(buffer request.Buffer, configCache *NexusResourceCache) *AliasedBuffer {
+	return &AliasedBuffer{
+		Buffer:      buffer,
+		configCache: configCache,
+	}
+}
+
+// Get returns the first run found under any name of the algorithm. A run missing under one name is looked up under the next one, and reported as not found only if it is missing under all of them
+func (buffer *AliasedBuffer) Get(requestId string, algorithmName string) (*coremodels.CheckpointedRequest, error) {
+	var notFound error
+	for _, name := range buffer.configCache.AlgorithmNames(algorithmName) {
+		checkpoint, err := buffer.Buffer.Get(requestId, name)
+		switch {
+		case storage.IsNotFound(err):
+			notFound = err
+		case err != nil || checkpoint != nil:
+			return checkpoint, err
+		}
+	}
+
+	return nil, notFound
+}
+
+// GetBufferedEntry returns the first buffered entry found under any name of the algorithm, with the same not found handling as Get
+func (buffer *AliasedBuffer) GetBufferedEntry(checkpoint *coremodels.CheckpointedRequest) (*coremodels.SubmissionBufferEntry, error) {
+	var notFound error
+	for _, name := range buffer.configCache.AlgorithmNames(checkpoint.Algorithm) {
+		lookup := *checkpoint
+		lookup.Algorithm = name
+		entry, err := buffer.Buffer.GetBufferedEntry(&lookup)
+		switch {
+		case storage.IsNotFound(err):
+			notFound = err
+		case err != nil || entry != nil:
+			return entry, err
+		}
+	}
+
+	return nil, notFound
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	v1 "github.com/SneaksAndData/nexus-core/pkg/apis/science/v1"
+	coremodels "github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
+	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/request"
+	"github.com/SneaksAndData/nexus/storage"
+	"github.com/gocql/gocql"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTemplateAliases(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+		expected   int
+		valid      bool
+	}{
+		{name: "no aliases", annotation: "", expected: 0, valid: true},
+		{name: "deprecated alias", annotation: `[{"name": "old-algorithm", "deprecation": "2025-01-01T00:00:00Z", "sunset": "2025-06-01T00:00:00Z"}]`, expected: 1, valid: true},
+		{name: "not an array", annotation: `{"name": "old-algorithm"}`, valid: false},
+		{name: "missing name", annotation: `[{"sunset": "2025-06-01T00:00:00Z"}]`, valid: false},
+		{name: "template name", annotation: `[{"name": "test-algorithm"}]`, valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			template := &v1.NexusAlgorithmTemplate{ObjectMeta: metav1.ObjectMeta{Name: "test-algorithm", Annotations: map[string]string{AliasesAnnotation: test.annotation}}}
+			aliases, err := TemplateAliases(template)
+			if (err == nil) != test.valid || len(aliases) != test.expected {
+				t.Errorf("expected %d aliases and valid=%v, but got %v (%v)", test.expected, test.valid, aliases, err)
+			}
+		})
+	}
+}
+
+func TestNexusResourceCache_ResolveAlgorithmAlias(t *testing.T) {
+	f := newFixture(t, []runtime.Object{})
+	f.populateTemplates([]*v1.NexusAlgorithmTemplate{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "new-algorithm", Namespace: "test", Annotations: map[string]string{
+				AliasesAnnotation: `[{"name": "old-algorithm", "deprecation": "2025-01-01T00:00:00Z"}, {"name": "other-algorithm"}]`,
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-algorithm", Namespace: "test"},
+		},
+	})
+
+	template, err := f.configCache.GetAlgorithmConfiguration("old-algorithm")
+	if err != nil || template == nil || template.Name != "new-algorithm" {
+		t.Errorf("expected an alias to resolve to its template, but got %v (%v)", template, err)
+	}
+
+	template, alias, _ := f.configCache.ResolveAlgorithmAlias("old-algorithm")
+	if template == nil || alias == nil || !alias.Deprecated() || !alias.Deprecation.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected alias deprecation to be returned, but got %v", alias)
+	}
+
+	if template, alias, _ := f.configCache.ResolveAlgorithmAlias("other-algorithm"); template != nil || alias != nil {
+		t.Errorf("expected a template name to take precedence over an alias, but got %v", template)
+	}
+
+	if names := f.configCache.AlgorithmNames("old-algorithm"); !slices.Equal(names, []string{"old-algorithm", "new-algorithm", "other-algorithm"}) {
+		t.Errorf("expected names of an alias to include the template name, but got %v", names)
+	}
+
+	if names := f.configCache.AlgorithmNames("new-algorithm"); !slices.Equal(names, []string{"new-algorithm", "old-algorithm", "other-algorithm"}) {
+		t.Errorf("expected names of a template to include its aliases, but got %v", names)
+	}
+}
+
+func TestNexusResourceCache_ValidateAliases(t *testing.T) {
+	f, _, _ := newValidationFixture(t, []runtime.Object{})
+	first := newValidatedTemplate("first-algorithm", "default")
+	first.Annotations = map[string]string{AliasesAnnotation: `[{"name": "old-algorithm"}]`}
+	second := newValidatedTemplate("second-algorithm", "default")
+	second.Annotations = map[string]string{AliasesAnnotation: `[{"name": "old-algorithm"}, {"name": "first-algorithm"}]`}
+	f.populateTemplates([]*v1.NexusAlgorithmTemplate{first, second})
+
+	problems := f.configCache.aliasProblems(second)
+	expected := []string{"alias old-algorithm is already defined by template first-algorithm", "alias first-algorithm is the name of an existing template"}
+	if !slices.Equal(problems, expected) {
+		t.Errorf("expected problems %v, but got %v", expected, problems)
+	}
+
+	if problems := f.configCache.aliasProblems(first); len(problems) != 0 {
+		t.Errorf("expected the first template defining an alias to be valid, but got %v", problems)
+	}
+}
+
+func TestRunSubmitter_SubmitViaAlias(t *testing.T) {
+	f, submitter := newSubmitterFixture(t, true)
+	if _, err := f.scheduler.Init(f.ctx); err != nil {
+		t.Errorf("scheduler init failed: %s", err)
+		t.FailNow()
+	}
+
+	buffered := make(bufferedRuns, 1)
+	go f.buffer.Start(buffered)
+	time.Sleep(1 * time.Second)
+
+	template, _ := f.scheduler.configCache.GetAlgorithmConfiguration("test-algorithm")
+	aliased := template.DeepCopy()
+	aliased.Annotations = map[string]string{AliasesAnnotation: `[{"name": "old-algorithm", "sunset": "2030-01-01T00:00:00Z"}]`}
+	_ = f.scheduler.configCache.templateInformer.GetIndexer().Update(aliased)
+
+	requestId, err := submitter.Submit(context.TODO(), &RunSubmission{AlgorithmName: "old-algorithm", Request: newFakeRequest()})
+	if err != nil {
+		t.Errorf("failed to submit a run via an alias: %v", err)
+		t.FailNow()
+	}
+
+	buffered.wait(t)
+	checkpoint, _ := f.buffer.Get(requestId, "test-algorithm")
+	if checkpoint == nil {
+		t.Errorf("expected a run submitted via an alias to be stored under the template name")
+	}
+
+	buffer := NewAliasedBuffer(f.buffer, f.scheduler.configCache)
+	checkpoint, _ = buffer.Get(requestId, "old-algorithm")
+	if checkpoint == nil || checkpoint.Algorithm != "test-algorithm" {
+		t.Errorf("expected a run to be found via an alias, but got %v", checkpoint)
+	}
+
+	if entry, _ := buffer.GetBufferedEntry(&coremodels.CheckpointedRequest{Algorithm: "old-algorithm", Id: requestId}); entry == nil {
+		t.Errorf("expected a buffered entry to be found via an alias")
+	}
+}
+
+// notFoundBuffer reports missing runs with gocql.ErrNotFound, like the Cassandra buffer does
+type notFoundBuffer struct {
+	request.Buffer
+}
+
+func (buffer *notFoundBuffer) Get(requestId string, algorithmName string) (*coremodels.CheckpointedRequest, error) {
+	checkpoint, err := buffer.Buffer.Get(requestId, algorithmName)
+	if checkpoint == nil && err == nil {
+		return nil, gocql.ErrNotFound
+	}
+
+	return checkpoint, err
+}
+
+func (buffer *notFoundBuffer) GetBufferedEntry(checkpoint *coremodels.CheckpointedRequest) (*coremodels.SubmissionBufferEntry, error) {
+	entry, err := buffer.Buffer.GetBufferedEntry(checkpoint)
+	if entry == nil && err == nil {
+		return nil, gocql.ErrNotFound
+	}
+
+	return entry, err
+}
+
+func TestAliasedBuffer_NotFound(t *testing.T) {
+	f := newSchedulerFixture(t, []runtime.Object{}, []runtime.Object{})
+	template, _ := f.scheduler.configCache.GetAlgorithmConfiguration("test-algorithm")
+	aliased := template.DeepCopy()
+	aliased.Annotations = map[string]string{AliasesAnnotation: `[{"name": "old-algorithm", "sunset": "2030-01-01T00:00:00Z"}]`}
+	_ = f.scheduler.configCache.templateInformer.GetIndexer().Update(aliased)
+
+	checkpoint, _, _ := coremodels.FromAlgorithmRequest("test-id", "test-algorithm", newFakeRequest(), newFakeSpec())
+	checkpoint.LifecycleStage = coremodels.LifecycleStageBuffered
+	memory := f.buffer.(*request.MemoryPassthroughBuffer)
+	memory.Checkpoints = append(memory.Checkpoints, checkpoint)
+	memory.BufferedEntries = append(memory.BufferedEntries, coremodels.FromCheckpoint(checkpoint, newFakeWorkgroupSpec(), nil))
+
+	buffer := NewAliasedBuffer(&notFoundBuffer{Buffer: f.buffer}, f.scheduler.configCache)
+	if found, err := buffer.Get("test-id", "old-algorithm"); err != nil || found == nil || found.Algorithm != "test-algorithm" {
+		t.Errorf("expected a run missing under an alias to be found under the template name, but got %v (%v)", found, err)
+	}
+
+	if entry, err := buffer.GetBufferedEntry(&coremodels.CheckpointedRequest{Algorithm: "old-algorithm", Id: "test-id"}); err != nil || entry == nil {
+		t.Errorf("expected a buffered entry missing under an alias to be found under the template name, but got %v (%v)", entry, err)
+	}
+
+	if missing, err := buffer.Get("missing-id", "old-algorithm"); missing != nil || !storage.IsNotFound(err) {
+		t.Errorf("expected a run missing under all names to be reported as not found, but got %v (%v)", missing, err)
+	}
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	v1 "github.com/SneaksAndData/nexus-core/pkg/apis/science/v1"
+	"github.com/SneaksAndData/nexus/services/models"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"slices"
+	"strings"
+)
+
+// DescriptionAnnotation is a human-readable description of the annotated template, shown in the algorithm catalog
+const DescriptionAnnotation = "science.sneaksanddata.com/description"
+
+// authorizationCacheSize is the maximum number of authorization decisions kept in the catalog cache
+const authorizationCacheSize = 4096
+
+// Caller is an identity of an API caller, as provided by an authenticating proxy
+type Caller struct {
+	User   string
+	Groups []string
+}
+
+// AlgorithmCatalog lists algorithm templates to callers, without details only algorithm authors need
+type AlgorithmCatalog struct {
+	configCache *NexusResourceCache
+	kubeClient  kubernetes.Interface
+	config      *models.AlgorithmCatalogConfig
+	decisions   *cache.LRUExpireCache
+	logger      klog.Logger
+}
+
+// NewAlgorithmCatalog creates an AlgorithmCatalog. Kubernetes client is used to authorize callers, if enabled
+func NewAlgorithmCatalog(configCache *NexusResourceCache, kubeClient kubernetes.Interface, config *models.AlgorithmCatalogConfig, logger klog.Logger) *AlgorithmCatalog {
+	return &AlgorithmCatalog{
+		configCache: configCache,
+		kubeClient:  kubeClient,
+		config:      config,
+		decisions:   cache.NewLRUExpireCache(authorizationCacheSize),
+		logger:      logger,
+	}
+}
+
+// List returns catalog entries of all templates of a tenant the caller is authorized to see, or of all tenants if tenant is empty
+func (catalog *AlgorithmCatalog) List(ctx context.Context, caller *Caller, tenant string) ([]*models.AlgorithmCatalogEntry, error) {
+	templates := catalog.configCache.ListAlgorithmConfigurations()
+	if tenant != "" {
+		templates = catalog.configCache.ListTenantAlgorithmConfigurations(tenant)
+	}
+
+	// access to all templates in a namespace saves a review per template
+	authorizedAll := map[string]bool{}
+	entries := []*models.AlgorithmCatalogEntry{}
+	for _, template := range templates {
+		if _, ok := authorizedAll[template.Namespace]; !ok {
+			authorized, err := catalog.authorized(ctx, caller, template.Namespace, "")
+			if err != nil {
+				return nil, err
+			}
+			authorizedAll[template.Namespace] = authorized
+		}
+
+		if !authorizedAll[template.Namespace] {
+			authorized, err := catalog.authorized(ctx, caller, template.Namespace, template.Name)
+			if err != nil {
+				return nil, err
+			}
+
+			if !authorized {
+				continue
+			}
+		}
+
+		entry, err := catalog.newEntry(template)
+		if err != nil { // coverage-ignore
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// Get returns a catalog entry of a template, or nil if it does not exist or the caller is not authorized to see it
+func (catalog *AlgorithmCatalog) Get(ctx context.Context, caller *Caller, algorithmName string) (*models.AlgorithmCatalogEntry, error) {
+	template, err := catalog.configCache.GetAlgorithmConfiguration(algorithmName)
+	if err != nil || template == nil {
+		return nil, err
+	}
+
+	authorized, err := catalog.authorized(ctx, caller, template.Namespace, template.Name)
+	if err != nil || !authorized {
+		return nil, err
+	}
+
+	return catalog.newEntry(template)
+}
+
+// authorized checks if a caller can get a template in a namespace, or all templates of the namespace if the name is empty
+func (catalog *AlgorithmCatalog) authorized(ctx context.Context, caller *Caller, namespace string, templateName string) (bool, error) {
+	if !catalog.config.AuthorizeCallers {
+		return true, nil
+	}
+
+	// groups are sorted, so the same identity reuses a decision regardless of the header order
+	groups := slices.Sorted(slices.Values(caller.Groups))
+	key := strings.Join([]string{caller.User, strings.Join(groups, ","), namespace, templateName}, "/")
+	if catalog.config.DecisionCacheTtl > 0 {
+		if allowed, ok := catalog.decisions.Get(key); ok {
+			return allowed.(bool), nil
+		}
+	}
+
+	review, err := catalog.kubeClient.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   caller.User,
+			Groups: caller.Groups,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "get",
+				Group:     v1.SchemeGroupVersion.Group,
+				Resource:  "nexusalgorithmtemplates",
+				Name:      templateName,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	if catalog.config.DecisionCacheTtl > 0 {
+		catalog.decisions.Add(key, review.Status.Allowed, catalog.config.DecisionCacheTtl)
+	}
+
+	return review.Status.Allowed, nil
+}
+
+// newEntry creates a catalog entry from a template and the workgroup it references
+func (catalog *AlgorithmCatalog) newEntry(template *v1.NexusAlgorithmTemplate) (*models.AlgorithmCatalogEntry, error) {
+	entry := &models.AlgorithmCatalogEntry{
+		Name:                   catalog.configCache.AlgorithmName(template),
+		Tenant:                 catalog.configCache.TemplateTenant(template),
+		Description:            template.Annotations[DescriptionAnnotation],
+		ComputeResources:       template.Spec.ComputeResources,
+		ErrorHandlingBehaviour: template.Spec.ErrorHandlingBehaviour,
+	}
+
+	if template.Spec.Container != nil {
+		entry.VersionTag = template.Spec.Container.VersionTag
+	}
+
+	if template.Spec.RuntimeEnvironment != nil {
+		entry.DeadlineSeconds = template.Spec.RuntimeEnvironment.DeadlineSeconds
+		entry.MaximumRetries = template.Spec.RuntimeEnvironment.MaximumRetries
+	}
+
+	schema, err := TemplateParameterSchema(template)
+	if err != nil {
+		// an invalid schema must not hide the algorithm, it is listed without one
+		catalog.logger.V(0).Error(err, "invalid parameter schema", "template", template.Name)
+	}
+	entry.ParameterSchema = schema
+
+	if template.Spec.WorkgroupRef == nil {
+		entry.MissingWorkgroup = true
+		return entry, nil
+	}
+
+	entry.Workgroup = template.Spec.WorkgroupRef.Name
+	workgroup, err := catalog.configCache.GetTemplateWorkgroup(template)
+	if err != nil { // coverage-ignore
+		return nil, err
+	}
+
+	if workgroup == nil {
+		entry.MissingWorkgroup = true
+		return entry, nil
+	}
+
+	entry.WorkgroupDescription = workgroup.Spec.Description
+	entry.Cluster = workgroup.Spec.Cluster
+
+	return entry, nil
+}
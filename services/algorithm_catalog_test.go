@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	v1 "github.com/SneaksAndData/nexus-core/pkg/apis/science/v1"
+	"github.com/SneaksAndData/nexus/services/models"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/klog/v2"
+	"testing"
+	"time"
+)
+
+func newCatalogFixture(t *testing.T, config *models.AlgorithmCatalogConfig) (*fixture, *AlgorithmCatalog, *k8sfake.Clientset) {
+	f := newFixture(t, []runtime.Object{})
+	spec := newFakeSpec()
+	spec.Container.Registry = "private-registry"
+
+	f.populateTemplates([]*v1.NexusAlgorithmTemplate{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-algorithm",
+				Namespace:   "test",
+				Annotations: map[string]string{DescriptionAnnotation: "Test algorithm"},
+			},
+			Spec: *spec,
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "orphaned-algorithm", Namespace: "test"},
+			Spec: v1.NexusAlgorithmSpec{
+				WorkgroupRef: &v1.NexusAlgorithmWorkgroupRef{Name: "missing"},
+			},
+		},
+	})
+	f.populateWorkgroups([]*v1.NexusAlgorithmWorkgroup{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "test"},
+			Spec:       *newFakeWorkgroupSpec(),
+		},
+	})
+
+	kubeClient := k8sfake.NewClientset()
+	return f, NewAlgorithmCatalog(f.configCache, kubeClient, config, klog.FromContext(f.ctx)), kubeClient
+}
+
+func TestAlgorithmCatalog_List(t *testing.T) {
+	_, catalog, _ := newCatalogFixture(t, &models.AlgorithmCatalogConfig{})
+
+	entries, err := catalog.List(context.TODO(), &Caller{}, "")
+	if err != nil || len(entries) != 2 {
+		t.Errorf("expected all templates to be listed, but got %v (%v)", entries, err)
+		t.FailNow()
+	}
+
+	orphaned, listed := entries[0], entries[1]
+	if !orphaned.MissingWorkgroup || orphaned.Workgroup != "missing" {
+		t.Errorf("expected a template with a missing workgroup to be reported, but got %v", orphaned)
+	}
+
+	if listed.MissingWorkgroup || listed.Cluster != "test-shard" || listed.VersionTag != "v1.2.3" || listed.Description != "Test algorithm" || *listed.DeadlineSeconds != 300 {
+		t.Errorf("unexpected catalog entry: %v", listed)
+	}
+}
+
+func TestAlgorithmCatalog_Get(t *testing.T) {
+	_, catalog, _ := newCatalogFixture(t, &models.AlgorithmCatalogConfig{})
+
+	entry, err := catalog.Get(context.TODO(), &Caller{}, "test-algorithm")
+	if err != nil || entry == nil || entry.Name != "test-algorithm" {
+		t.Errorf("expected a catalog entry for an existing template, but got %v (%v)", entry, err)
+	}
+
+	if entry, err := catalog.Get(context.TODO(), &Caller{}, "missing-algorithm"); entry != nil || err != nil {
+		t.Errorf("expected no catalog entry for a missing template, but got %v (%v)", entry, err)
+	}
+}
+
+func TestAlgorithmCatalog_AuthorizeCallers(t *testing.T) {
+	_, catalog, kubeClient := newCatalogFixture(t, &models.AlgorithmCatalogConfig{AuthorizeCallers: true, DecisionCacheTtl: time.Minute})
+	reviews := []authorizationv1.SubjectAccessReviewSpec{}
+	kubeClient.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		reviews = append(reviews, review.Spec)
+		review.Status.Allowed = review.Spec.ResourceAttributes.Name == "test-algorithm"
+		return true, review, nil
+	})
+
+	caller := &Caller{User: "user", Groups: []string{"team"}}
+	entries, err := catalog.List(context.TODO(), caller, "")
+	if err != nil || len(entries) != 1 || entries[0].Name != "test-algorithm" {
+		t.Errorf("expected only authorized templates to be listed, but got %v (%v)", entries, err)
+	}
+
+	if len(reviews) != 3 || reviews[0].User != "user" || reviews[0].Groups[0] != "team" || reviews[0].ResourceAttributes.Namespace != "test" {
+		t.Errorf("expected a namespace review followed by a review per template, but got %v", reviews)
+	}
+
+	if entry, err := catalog.Get(context.TODO(), caller, "orphaned-algorithm"); entry != nil || err != nil {
+		t.Errorf("expected an unauthorized template to be hidden, but got %v (%v)", entry, err)
+	}
+
+	// decisions are reused for the same caller
+	if entries, _ := catalog.List(context.TODO(), &Caller{User: "user", Groups: []string{"team"}}, ""); len(entries) != 1 || len(reviews) != 3 {
+		t.Errorf("expected cached decisions to be reused, but got %d reviews", len(reviews))
+	}
+
+	_, _ = catalog.List(context.TODO(), &Caller{User: "other", Groups: []string{"team"}}, "")
+	if len(reviews) != 6 {
+		t.Errorf("expected decisions not to be shared between callers, but got %d reviews", len(reviews))
+	}
+}
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	v1 "github.com/SneaksAndData/nexus-core/pkg/apis/science/v1"
+)
+
+// ParameterSchemaAnnotation publishes a JSON Schema of algorithm parameters accepted by the annotated template
+const ParameterSchemaAnnotation = "science.sneaksanddata.com/parameter-schema"
+
+// TemplateParameterSchema returns the parameter schema published by a template, or nil if it has none
+func TemplateParameterSchema(template *v1.NexusAlgorithmTemplate) (map[string]interface{}, error) {
+	value, ok := template.Annotations[ParameterSchemaAnnotation]
+	if !ok || value == "" {
+		return nil, nil
+	}
+
+	schema := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(value), &schema); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation on template %s, a JSON object is expected: %w", ParameterSchemaAnnotation, template.Name, err)
+	}
+
+	return schema, nil
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"fmt"
+	coremodels "github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
+	"github.com/SneaksAndData/nexus/services/models"
+	"github.com/google/uuid"
+	"iter"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"os"
+	"slices"
+)
+
+const (
+	// cancellationProgressInterval defines how often (in processed runs) bulk cancellation progress is persisted
+	cancellationProgressInterval = 100
+)
+
+// DefaultCancellationStages are lifecycle stages targeted by a bulk cancellation when no stages are provided
+var DefaultCancellationStages = []string{coremodels.LifecycleStageBuffered, coremodels.LifecycleStageRunning}
+
+// CancelTaggedRuns starts an asynchronous cancellation of all runs with the provided tag that are in one of the provided lifecycle stages
+func (scheduler *RequestScheduler) CancelTaggedRuns(tag string, lifecycleStages []string, initiator string, reason string, policy metav1.DeletionPropagation) (*models.CancellationOperation, error) {
+	operation := models.NewCancellationOperation(uuid.New().String(), initiator, reason, string(policy))
+	operation.Tag = tag
+	operation.LifecycleStages = lifecycleStages
+
+	return scheduler.startCancellation(operation)
+}
+
+// CancelAlgorithmRuns starts an asynchronous cancellation of all runs of the provided algorithm that are in one of the provided lifecycle stages
+func (scheduler *RequestScheduler) CancelAlgorithmRuns(algorithmName string, lifecycleStages []string, initiator string, reason string, policy metav1.DeletionPropagation) (*models.CancellationOperation, error) {
+	operation := models.NewCancellationOperation(uuid.New().String(), initiator, reason, string(policy))
+	operation.Algorithm = scheduler.runName(algorithmName)
+	operation.LifecycleStages = lifecycleStages
+
+	return scheduler.startCancellation(operation)
+}
+
+// GetCancellationOperation returns the current state of a bulk cancellation, or nil if it does not exist
+func (scheduler *RequestScheduler) GetCancellationOperation(operationId string) (*models.CancellationOperation, error) {
+	return scheduler.store.ReadCancellationOperation(operationId)
+}
+
+func (scheduler *RequestScheduler) startCancellation(operation *models.CancellationOperation) (*models.CancellationOperation, error) {
+	if len(operation.LifecycleStages) == 0 {
+		operation.LifecycleStages = DefaultCancellationStages
+	}
+
+	host, err := os.Hostname()
+	if err != nil { // coverage-ignore
+		return nil, err
+	}
+
+	operation.Host = host
+
+	if err := scheduler.store.UpsertCancellationOperation(operation); err != nil {
+		return nil, err
+	}
+
+	scheduler.CancellationActor.Receive(operation)
+
+	return operation, nil
+}
+
+// resumeCancellations takes over PENDING and RUNNING operations of scheduler instances that are no longer running.
+// Runs cancelled before the takeover are no longer in the targeted lifecycle stages, so a resumed operation only processes the remaining runs
+func (scheduler *RequestScheduler) resumeCancellations(live map[string]bool) error {
+	host, err := os.Hostname()
+	if err != nil { // coverage-ignore
+		return err
+	}
+
+	for _, status := range []string{models.CancellationOperationPending, models.CancellationOperationRunning} {
+		operations, err := scheduler.store.ReadCancellationOperationsInStatus(status)
+		if err != nil { // coverage-ignore
+			return err
+		}
+
+		for _, operation := range operations {
+			if live[operation.Host] {
+				continue
+			}
+
+			scheduler.logger.V(0).Info("resuming a bulk cancellation left over by a scheduler that is not running", "operation", operation.Id, "instance", operation.Host)
+
+			operation.Host = host
+			if err := scheduler.store.UpsertCancellationOperation(operation); err != nil { // coverage-ignore
+				return err
+			}
+
+			scheduler.CancellationActor.Receive(operation)
+		}
+	}
+
+	return nil
+}
+
+// findCancellationTargets iterates checkpoints matching the operation filter. Checkpoints are read page by page, so large operations do not load all matching runs into memory
+func (scheduler *RequestScheduler) findCancellationTargets(operation *models.CancellationOperation) iter.Seq2[*coremodels.CheckpointedRequest, error] {
+	return func(yield func(*coremodels.CheckpointedRequest, error) bool) {
+		if operation.Tag != "" {
+			checkpoints, err := scheduler.buffer.GetTagged(operation.Tag)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for checkpoint, err := range checkpoints {
+				if err != nil { // coverage-ignore
+					yield(nil, err)
+					return
+				}
+
+				if (operation.Algorithm == "" || checkpoint.Algorithm == operation.Algorithm) && slices.Contains(operation.LifecycleStages, checkpoint.LifecycleStage) {
+					if !yield(checkpoint, nil) {
+						return
+					}
+				}
+			}
+
+			return
+		}
+
+		for _, stage := range operation.LifecycleStages {
+			checkpoints, err := scheduler.store.ReadCheckpointsByStage(operation.Algorithm, stage)
+			if err != nil { // coverage-ignore
+				yield(nil, err)
+				return
+			}
+
+			for checkpoint, err := range checkpoints {
+				if !yield(checkpoint, err) || err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+// cancelMatching executes a bulk cancellation and periodically persists its progress
+func (scheduler *RequestScheduler) cancelMatching(operation *models.CancellationOperation) (string, error) {
+	if operation == nil {
+		return "", fmt.Errorf("no cancellation operation provided")
+	}
+
+	progress := operation.DeepCopy()
+	progress.Status = models.CancellationOperationRunning
+	if err := scheduler.store.UpsertCancellationOperation(progress); err != nil { // coverage-ignore
+		return progress.Id, err
+	}
+
+	for checkpoint, err := range scheduler.findCancellationTargets(progress) {
+		if err != nil { // coverage-ignore
+			progress.Status = models.CancellationOperationFailed
+			progress.LastError = err.Error()
+			_ = scheduler.store.UpsertCancellationOperation(progress)
+			return progress.Id, err
+		}
+
+		progress.Matched++
+		if err := scheduler.cancelCheckpoint(checkpoint, progress.Initiator, progress.Reason, metav1.DeletionPropagation(progress.Policy)); err != nil { // coverage-ignore
+			scheduler.logger.V(0).Error(err, "error when cancelling a run", "operation", progress.Id, "request", checkpoint.Id, "template", checkpoint.Algorithm)
+			progress.Failed++
+			progress.LastError = err.Error()
+		} else {
+			progress.Cancelled++
+		}
+
+		// progress is persisted periodically, so counters of a resumed operation may miss runs cancelled since the last save
+		if progress.Matched%cancellationProgressInterval == 0 { // coverage-ignore
+			if err := scheduler.store.UpsertCancellationOperation(progress); err != nil {
+				scheduler.logger.V(0).Error(err, "error when saving cancellation progress", "operation", progress.Id)
+			}
+		}
+	}
+
+	progress.Status = models.CancellationOperationCompleted
+	if err := scheduler.store.UpsertCancellationOperation(progress); err != nil { // coverage-ignore
+		return progress.Id, err
+	}
+
+	scheduler.logger.V(0).Info("bulk cancellation completed", "operation", progress.Id, "matched", progress.Matched, "cancelled", progress.Cancelled, "failed", progress.Failed)
+
+	return progress.Id, nil
+}
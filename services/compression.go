@@ -0,0 +1,78 @@
+package services
+
+import (
+	"compress/gzip"
+	"errors"
+	"github.com/klauspost/compress/zstd"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	EncodingIdentity = "identity"
+	EncodingGzip     = "gzip"
+	EncodingZstd     = "zstd"
+)
+
+// ErrUnsupportedEncoding is returned for content encodings other than gzip and zstd
+var ErrUnsupportedEncoding = errors.New("unsupported content encoding")
+
+// NewDecoder wraps a reader with a decoder for the provided Content-Encoding. Identity encoding returns the reader as is
+func NewDecoder(encoding string, reader io.Reader) (io.ReadCloser, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "", EncodingIdentity:
+		return io.NopCloser(reader), nil
+	case EncodingGzip:
+		return gzip.NewReader(reader)
+	case EncodingZstd:
+		decoder, err := zstd.NewReader(reader, zstd.WithDecoderConcurrency(1), zstd.WithDecoderLowmem(true))
+		if err != nil { // coverage-ignore
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	default:
+		return nil, ErrUnsupportedEncoding
+	}
+}
+
+// NewEncoder wraps a writer with an encoder for the provided Content-Encoding. Encoded content is complete only after the encoder is closed
+func NewEncoder(encoding string, writer io.Writer) (io.WriteCloser, error) {
+	switch encoding {
+	case EncodingGzip:
+		return gzip.NewWriter(writer), nil
+	case EncodingZstd:
+		return zstd.NewWriter(writer, zstd.WithEncoderConcurrency(1))
+	default:
+		return nil, ErrUnsupportedEncoding
+	}
+}
+
+// NegotiateEncoding selects a supported encoding from an Accept-Encoding header, preferring the highest weight and then the order of the header. Returns an empty string if no supported encoding is accepted
+func NegotiateEncoding(acceptEncoding string) string {
+	selected := ""
+	selectedWeight := 0.0
+	for _, value := range strings.Split(acceptEncoding, ",") {
+		parts := strings.Split(value, ";")
+		encoding := strings.ToLower(strings.TrimSpace(parts[0]))
+		if encoding != EncodingGzip && encoding != EncodingZstd {
+			continue
+		}
+
+		weight := 1.0
+		for _, parameter := range parts[1:] {
+			if name, qvalue, ok := strings.Cut(strings.TrimSpace(parameter), "="); ok && name == "q" {
+				if parsed, err := strconv.ParseFloat(qvalue, 64); err == nil {
+					weight = parsed
+				}
+			}
+		}
+
+		if weight > selectedWeight {
+			selected = encoding
+			selectedWeight = weight
+		}
+	}
+
+	return selected
+}
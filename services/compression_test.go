@@ -0,0 +1,73 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestCompression_RoundTrip(t *testing.T) {
+	for _, encoding := range []string{EncodingGzip, EncodingZstd} {
+		compressed := &bytes.Buffer{}
+		encoder, err := NewEncoder(encoding, compressed)
+		if err != nil {
+			t.Errorf("failed to create a %s encoder: %s", encoding, err)
+			t.FailNow()
+		}
+
+		payload := strings.Repeat(`{"input": "value"}`, 100)
+		_, _ = encoder.Write([]byte(payload))
+		_ = encoder.Close()
+
+		if compressed.Len() >= len(payload) {
+			t.Errorf("expected %s to compress the payload, but got %d bytes", encoding, compressed.Len())
+		}
+
+		decoder, err := NewDecoder(encoding, compressed)
+		if err != nil {
+			t.Errorf("failed to create a %s decoder: %s", encoding, err)
+			t.FailNow()
+		}
+
+		decoded, err := io.ReadAll(decoder)
+		if err != nil || string(decoded) != payload {
+			t.Errorf("expected %s payload to be decoded, but got %d bytes (%v)", encoding, len(decoded), err)
+		}
+	}
+}
+
+func TestCompression_Unsupported(t *testing.T) {
+	if _, err := NewDecoder("br", strings.NewReader("")); !errors.Is(err, ErrUnsupportedEncoding) {
+		t.Errorf("expected br decoding to be unsupported, but got: %v", err)
+	}
+
+	if _, err := NewEncoder("deflate", &bytes.Buffer{}); !errors.Is(err, ErrUnsupportedEncoding) {
+		t.Errorf("expected deflate encoding to be unsupported, but got: %v", err)
+	}
+
+	decoder, err := NewDecoder("", strings.NewReader("{}"))
+	if content, _ := io.ReadAll(decoder); err != nil || string(content) != "{}" {
+		t.Errorf("expected a body without encoding to be read as is, but got %s (%v)", content, err)
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	cases := map[string]string{
+		"":                               "",
+		"br, deflate":                    "",
+		"gzip, deflate, br":              EncodingGzip,
+		"zstd, gzip":                     EncodingZstd,
+		"gzip;q=0.5, zstd;q=0.8":         EncodingZstd,
+		"GZIP":                           EncodingGzip,
+		"gzip;q=0, identity":             "",
+		"br;q=1.0, zstd;q=0.1, gzip;q=0": EncodingZstd,
+	}
+
+	for header, expected := range cases {
+		if encoding := NegotiateEncoding(header); encoding != expected {
+			t.Errorf("expected %q to negotiate %q, but got %q", header, expected, encoding)
+		}
+	}
+}
@@ -195,3 +195,34 @@ func TestNexusResourceCache_GetWorkgroup(t *testing.T) {
 		t.Errorf("algorithm workgroup should not be nil")
 	}
 }
+
+func TestNexusResourceCache_ListAlgorithmConfigurations(t *testing.T) {
+	f := newFixture(t, []runtime.Object{})
+	f.populateTemplates([]*v1.NexusAlgorithmTemplate{
+		{ObjectMeta: metav1.ObjectMeta{Name: "test-algorithm-2", Namespace: "test"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "test-algorithm-1", Namespace: "test"}},
+	})
+
+	templates := f.configCache.ListAlgorithmConfigurations()
+	if len(templates) != 2 || templates[0].Name != "test-algorithm-1" || templates[1].Name != "test-algorithm-2" {
+		t.Errorf("expected templates sorted by name, but got %v", templates)
+	}
+}
+
+func TestTemplateParameterSchema(t *testing.T) {
+	template := &v1.NexusAlgorithmTemplate{ObjectMeta: metav1.ObjectMeta{Name: "test-algorithm"}}
+	if schema, err := TemplateParameterSchema(template); schema != nil || err != nil {
+		t.Errorf("expected no schema for a template without the annotation, but got %v (%v)", schema, err)
+	}
+
+	template.Annotations = map[string]string{ParameterSchemaAnnotation: `{"type": "object", "required": ["input"]}`}
+	schema, err := TemplateParameterSchema(template)
+	if err != nil || schema["type"] != "object" {
+		t.Errorf("expected a published schema to be returned, but got %v (%v)", schema, err)
+	}
+
+	template.Annotations[ParameterSchemaAnnotation] = `["input"]`
+	if _, err := TemplateParameterSchema(template); err == nil {
+		t.Errorf("expected a schema that is not a JSON object to be rejected")
+	}
+}
@@ -3,45 +3,77 @@ package services
 import (
 	"context"
 	"fmt"
+	"github.com/DataDog/datadog-go/v5/statsd"
 	v1 "github.com/SneaksAndData/nexus-core/pkg/apis/science/v1"
 	nexuscore "github.com/SneaksAndData/nexus-core/pkg/generated/clientset/versioned"
 	nexusinf "github.com/SneaksAndData/nexus-core/pkg/generated/informers/externalversions"
 	"github.com/SneaksAndData/nexus-core/pkg/resolvers"
+	"github.com/SneaksAndData/nexus-core/pkg/shards"
+	"github.com/SneaksAndData/nexus-core/pkg/telemetry"
 	"github.com/SneaksAndData/nexus-core/pkg/util"
+	"github.com/SneaksAndData/nexus/services/models"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/diff"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
+	"sort"
+	"sync"
 	"time"
 )
 
 type NexusResourceCache struct {
 	logger            klog.Logger
+	client            nexuscore.Interface
+	resyncPeriod      time.Duration
 	factory           nexusinf.SharedInformerFactory
 	templateInformer  cache.SharedIndexInformer
 	workgroupInformer cache.SharedIndexInformer
 	prefix            string
+	metrics           *statsd.Client
+	tenancy           *models.TenancyConfig
+
+	validationConfig *models.TemplateValidationConfig
+	shardClients     []*shards.ShardClient
+	shardKubeClients map[string]kubernetes.Interface
+	recorder         record.EventRecorder
+	validationLock   sync.RWMutex
+	validationStatus map[string]*models.TemplateValidationStatus
+
+	history *TemplateHistory
 }
 
 // NewNexusResourceCache creates a new cache for Nexus resources
 func NewNexusResourceCache(client nexuscore.Interface, resourceNamespace string, logger klog.Logger, resyncPeriod *time.Duration) *NexusResourceCache {
 	defaultResyncPeriod := time.Second * 30
-	factory := nexusinf.NewSharedInformerFactoryWithOptions(client, *util.CoalescePointer(resyncPeriod, &defaultResyncPeriod), nexusinf.WithNamespace(resourceNamespace))
-	watcher := factory.Science().V1().NexusAlgorithmTemplates()
-	workgroupWatcher := factory.Science().V1().NexusAlgorithmWorkgroups()
-
-	return &NexusResourceCache{
-		logger:            logger,
-		factory:           factory,
-		templateInformer:  watcher.Informer(),
-		workgroupInformer: workgroupWatcher.Informer(),
-		prefix:            resourceNamespace,
+	c := &NexusResourceCache{
+		logger:       logger,
+		client:       client,
+		resyncPeriod: *util.CoalescePointer(resyncPeriod, &defaultResyncPeriod),
+		prefix:       resourceNamespace,
+	}
+	c.watch(nexusinf.WithNamespace(resourceNamespace))
+
+	return c
+}
+
+// watch creates informers for templates and workgroups. Informers must be created before Init is called
+func (c *NexusResourceCache) watch(options ...nexusinf.SharedInformerOption) {
+	c.factory = nexusinf.NewSharedInformerFactoryWithOptions(c.client, c.resyncPeriod, options...)
+	c.templateInformer = c.factory.Science().V1().NexusAlgorithmTemplates().Informer()
+	c.workgroupInformer = c.factory.Science().V1().NexusAlgorithmWorkgroups().Informer()
+
+	if err := c.templateInformer.AddIndexers(cache.Indexers{aliasIndex: indexAliases}); err != nil { // coverage-ignore
+		utilruntime.HandleError(err)
 	}
 }
 
 // Init starts informers and sync the cache
 func (c *NexusResourceCache) Init(ctx context.Context) error {
+	c.metrics = telemetry.GetClient(ctx)
+
 	// Set up an event handler for when Machine Learning Algorithm resources change
 	_, err := c.templateInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    c.onConfigurationAdded,
@@ -82,6 +114,8 @@ func (c *NexusResourceCache) onConfigurationAdded(obj interface{}) { // coverage
 	}
 
 	c.logger.V(3).Info("resource loaded", "resource", objectRef.Name)
+	c.onResourceChanged(obj)
+	c.recordRevision(obj)
 }
 
 func (c *NexusResourceCache) onConfigurationUpdated(old, new interface{}) {
@@ -99,6 +133,13 @@ func (c *NexusResourceCache) onConfigurationUpdated(old, new interface{}) {
 	}
 
 	c.logger.V(3).Info("resource updated", "resource", newRef.Name, "diff", diff.ObjectGoPrintSideBySide(old, new))
+	// informer resyncs deliver unmodified objects, revalidating them would repeat shard lookups for every template on every resync
+	oldObject, oldOk := old.(metav1.Object)
+	newObject, newOk := new.(metav1.Object)
+	if !oldOk || !newOk || oldObject.GetResourceVersion() == "" || oldObject.GetResourceVersion() != newObject.GetResourceVersion() {
+		c.onResourceChanged(new)
+	}
+	c.recordRevision(new)
 }
 
 func (c *NexusResourceCache) onConfigurationDeleted(obj interface{}) {
@@ -122,17 +163,63 @@ func (c *NexusResourceCache) onConfigurationDeleted(obj interface{}) {
 		}
 
 		c.logger.V(3).Info("resource deleted", "resource", object.GetName())
+		c.onResourceRemoved(object)
 	} else {
 		c.logger.V(3).Info("resource deleted", "resource", object.GetName())
+		c.onResourceRemoved(object)
 	}
 }
 
-// GetAlgorithmConfiguration retrieves a cached NexusAlgorithmTemplate resource from informer cache
+// GetAlgorithmConfiguration retrieves a cached NexusAlgorithmTemplate resource from informer cache, either by its name or by one of its aliases.
+// Templates of a tenant are retrieved by a tenant-qualified name
 func (c *NexusResourceCache) GetAlgorithmConfiguration(algorithmName string) (*v1.NexusAlgorithmTemplate, error) {
-	return resolvers.GetCachedObject[v1.NexusAlgorithmTemplate](algorithmName, c.prefix, c.templateInformer)
+	tenant, name := SplitAlgorithmName(algorithmName)
+	namespace, ok := c.tenantNamespace(tenant)
+	if !ok {
+		return nil, nil
+	}
+
+	template, err := resolvers.GetCachedObject[v1.NexusAlgorithmTemplate](name, namespace, c.templateInformer)
+	if err != nil || template != nil {
+		return template, err
+	}
+
+	template, _, err = c.ResolveAlgorithmAlias(algorithmName)
+	return template, err
 }
 
-// GetWorkgroupConfiguration retrieves a cached NexusAlgorithmTemplate resource from informer cache
+// ListAlgorithmConfigurations returns all cached NexusAlgorithmTemplate resources, sorted by algorithm name
+func (c *NexusResourceCache) ListAlgorithmConfigurations() []*v1.NexusAlgorithmTemplate {
+	templates := []*v1.NexusAlgorithmTemplate{}
+	for _, object := range c.templateInformer.GetStore().List() {
+		if template, ok := object.(*v1.NexusAlgorithmTemplate); ok && c.served(template) {
+			templates = append(templates, template)
+		}
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return c.AlgorithmName(templates[i]) < c.AlgorithmName(templates[j])
+	})
+
+	return templates
+}
+
+// GetWorkgroupConfiguration retrieves a cached NexusAlgorithmWorkgroup resource from informer cache. Workgroups of a tenant are retrieved by a tenant-qualified name
 func (c *NexusResourceCache) GetWorkgroupConfiguration(workgroupName string) (*v1.NexusAlgorithmWorkgroup, error) {
-	return resolvers.GetCachedObject[v1.NexusAlgorithmWorkgroup](workgroupName, c.prefix, c.workgroupInformer)
+	tenant, name := SplitAlgorithmName(workgroupName)
+	namespace, ok := c.tenantNamespace(tenant)
+	if !ok {
+		return nil, nil
+	}
+
+	return resolvers.GetCachedObject[v1.NexusAlgorithmWorkgroup](name, namespace, c.workgroupInformer)
+}
+
+// GetTemplateWorkgroup retrieves the workgroup a template references, from the namespace of the template. Returns nil if the template has no workgroup
+func (c *NexusResourceCache) GetTemplateWorkgroup(template *v1.NexusAlgorithmTemplate) (*v1.NexusAlgorithmWorkgroup, error) {
+	if template.Spec.WorkgroupRef == nil || template.Spec.WorkgroupRef.Name == "" {
+		return nil, nil
+	}
+
+	return resolvers.GetCachedObject[v1.NexusAlgorithmWorkgroup](template.Spec.WorkgroupRef.Name, template.Namespace, c.workgroupInformer)
 }
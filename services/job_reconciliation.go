@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	coremodels "github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
+	"github.com/SneaksAndData/nexus-core/pkg/pipeline"
+	"github.com/SneaksAndData/nexus-core/pkg/telemetry"
+	"github.com/SneaksAndData/nexus/services/models"
+	"github.com/SneaksAndData/nexus/storage"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"strings"
+	"time"
+)
+
+// JobNameLabel is set by the Job controller on pods it creates
+const JobNameLabel = "batch.kubernetes.io/job-name"
+
+// WithShardKubeClients provides Kubernetes API clients for shard clusters, keyed by shard name
+func (scheduler *RequestScheduler) WithShardKubeClients(clients map[string]kubernetes.Interface) *RequestScheduler {
+	scheduler.shardKubeClients = clients
+	return scheduler
+}
+
+// WithJobReconciliation enables Job informers on shard clusters that move finished runs to COMPLETED, FAILED or DEADLINE_EXCEEDED
+func (scheduler *RequestScheduler) WithJobReconciliation(config *models.JobReconciliationConfig) *RequestScheduler {
+	scheduler.reconciliationConfig = config
+	return scheduler
+}
+
+func (scheduler *RequestScheduler) jobReconciliationEnabled() bool {
+	return scheduler.reconciliationConfig != nil && scheduler.reconciliationConfig.Enabled
+}
+
+// initJobReconciliation creates a Job informer for each shard and Job namespace, limited to Jobs created by Nexus
+func (scheduler *RequestScheduler) initJobReconciliation() error {
+	scheduler.ReconciliationActor = pipeline.NewDefaultPipelineStageActor[*models.ShardJob, string](
+		"job_reconciliation",
+		map[string]string{},
+		scheduler.workerConfig.FailureRateBaseDelay,
+		scheduler.workerConfig.FailureRateMaxDelay,
+		scheduler.workerConfig.RateLimitElementsPerSecond,
+		scheduler.workerConfig.RateLimitElementsBurst,
+		scheduler.workerConfig.Workers,
+		scheduler.reconcileJob,
+		nil,
+	)
+
+	defaultResyncPeriod := time.Minute * 5
+	resyncPeriod := scheduler.reconciliationConfig.ResyncPeriod
+	if resyncPeriod == 0 {
+		resyncPeriod = defaultResyncPeriod
+	}
+
+	for shardName, client := range scheduler.shardKubeClients {
+		for _, namespace := range scheduler.jobNamespaces() {
+			factory := kubeinformers.NewSharedInformerFactoryWithOptions(client, resyncPeriod, kubeinformers.WithNamespace(namespace), kubeinformers.WithTweakListOptions(func(options *metav1.ListOptions) {
+				options.LabelSelector = labels.Set{coremodels.NexusComponentLabel: coremodels.JobLabelAlgorithmRun}.String()
+			}))
+
+			_, err := factory.Batch().V1().Jobs().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+				AddFunc: func(obj interface{}) {
+					scheduler.onJobEvent(shardName, obj)
+				},
+				UpdateFunc: func(_, obj interface{}) {
+					scheduler.onJobEvent(shardName, obj)
+				},
+			})
+
+			if err != nil { // coverage-ignore
+				return err
+			}
+
+			scheduler.jobInformerFactories = append(scheduler.jobInformerFactories, factory)
+		}
+	}
+
+	scheduler.logger.Info("job reconciliation configured", "shards", len(scheduler.shardKubeClients), "namespaces", scheduler.jobNamespaces())
+
+	return nil
+}
+
+// startJobReconciliation starts Job informers on all shards
+func (scheduler *RequestScheduler) startJobReconciliation(ctx context.Context) {
+	go scheduler.ReconciliationActor.Start(ctx, nil)
+
+	for _, factory := range scheduler.jobInformerFactories {
+		factory.Start(ctx.Done())
+	}
+}
+
+// onJobEvent forwards finished Jobs for reconciliation
+func (scheduler *RequestScheduler) onJobEvent(shard string, obj interface{}) {
+	job, ok := obj.(*batchv1.Job)
+	if !ok { // coverage-ignore
+		return
+	}
+
+	if jobFinishedCondition(job) != nil {
+		scheduler.ReconciliationActor.Receive(&models.ShardJob{Job: job, Shard: shard})
+	}
+}
+
+// jobFinishedCondition returns the Complete or Failed condition of a Job, or nil if the Job is still active
+func jobFinishedCondition(job *batchv1.Job) *batchv1.JobCondition {
+	for i := range job.Status.Conditions {
+		condition := &job.Status.Conditions[i]
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) && condition.Status == corev1.ConditionTrue {
+			return condition
+		}
+	}
+
+	return nil
+}
+
+// reconcileJob moves a RUNNING checkpoint to a final lifecycle stage matching its Job condition. Final stages already reported by the algorithm take precedence
+func (scheduler *RequestScheduler) reconcileJob(shardJob *models.ShardJob) (string, error) {
+	job := shardJob.Job
+	checkpoint, err := scheduler.buffer.Get(job.Name, job.Labels[coremodels.JobTemplateNameKey])
+	if err != nil && !storage.IsNotFound(err) { // coverage-ignore
+		return job.Name, err
+	}
+
+	// Job was not created by this Nexus deployment
+	if checkpoint == nil {
+		return job.Name, nil
+	}
+
+	// Job with the same name might be left over from a different run
+	if checkpoint.LifecycleStage != coremodels.LifecycleStageRunning || (checkpoint.JobUid != "" && checkpoint.JobUid != string(job.UID)) {
+		return job.Name, nil
+	}
+
+	condition := jobFinishedCondition(job)
+	reconciled := checkpoint.DeepCopy()
+
+	switch {
+	case condition.Type == batchv1.JobComplete:
+		reconciled.LifecycleStage = coremodels.LifecycleStageCompleted
+	case condition.Reason == batchv1.JobReasonDeadlineExceeded:
+		reconciled.LifecycleStage = coremodels.LifecycleStageDeadlineExceeded
+	default:
+		reconciled.LifecycleStage = coremodels.LifecycleStageFailed
+	}
+
+	if condition.Type == batchv1.JobFailed {
+		reconciled.AlgorithmFailureCause = fmt.Sprintf("Job failed: %s", condition.Reason)
+		reconciled.AlgorithmFailureDetails = condition.Message
+		if reasons := scheduler.podFailureReasons(shardJob.Shard, job); len(reasons) > 0 {
+			reconciled.AlgorithmFailureDetails = fmt.Sprintf("%s. Pod failures: %s", condition.Message, strings.Join(reasons, "; "))
+		}
+	}
+
+	scheduler.logger.V(0).Info("reconciled run with its job status", "request", job.Name, "template", reconciled.Algorithm, "shard", shardJob.Shard, "lifecycleStage", reconciled.LifecycleStage)
+	// variant tag allows comparing failure rates and durations of templates in a traffic split
+	tags := map[string]string{"algorithm": reconciled.Algorithm, "lifecycle_stage": reconciled.LifecycleStage, "variant": appliedTemplateVariant(reconciled.Algorithm, reconciled.AppliedConfiguration)}
+	telemetry.Increment(scheduler.metrics, "job_reconciled", tags)
+	if !reconciled.SentAt.IsZero() {
+		telemetry.GaugeDuration(scheduler.metrics, "run_duration", reconciled.SentAt, tags, 1)
+	}
+
+	if err := scheduler.buffer.Update(reconciled); err != nil { // coverage-ignore
+		return job.Name, err
+	}
+
+	if reconciled.LifecycleStage == coremodels.LifecycleStageCompleted && reconciled.ResultUri != "" {
+		scheduler.recordCachedResult(reconciled)
+	}
+
+	return job.Name, nil
+}
+
+// podFailureReasons collects termination and waiting reasons of containers in pods created for the Job, for example OOMKilled or ImagePullBackOff
+func (scheduler *RequestScheduler) podFailureReasons(shard string, job *batchv1.Job) []string {
+	client, ok := scheduler.shardKubeClients[shard]
+	if !ok { // coverage-ignore
+		return nil
+	}
+
+	pods, err := client.CoreV1().Pods(job.Namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: labels.Set{JobNameLabel: job.Name}.String(),
+	})
+	if err != nil { // coverage-ignore
+		scheduler.logger.V(0).Error(err, "unable to read pods of a failed job", "request", job.Name, "shard", shard)
+		return nil
+	}
+
+	reasons := []string{}
+	for _, pod := range pods.Items {
+		for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+			switch {
+			case status.State.Terminated != nil && status.State.Terminated.ExitCode != 0:
+				reasons = append(reasons, fmt.Sprintf("%s/%s: %s (exit code %d)", pod.Name, status.Name, status.State.Terminated.Reason, status.State.Terminated.ExitCode))
+			case status.State.Waiting != nil && status.State.Waiting.Reason != "":
+				reasons = append(reasons, fmt.Sprintf("%s/%s: %s", pod.Name, status.Name, status.State.Waiting.Reason))
+			}
+		}
+	}
+
+	return reasons
+}
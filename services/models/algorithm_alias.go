@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// AlgorithmAlias is an additional name a NexusAlgorithmTemplate can be called by, for example a name the algorithm had before it was renamed
+type AlgorithmAlias struct {
+	Name string `json:"name"`
+	// Deprecation is the time the alias was deprecated at. Calls via a deprecated alias receive a Deprecation header
+	Deprecation *time.Time `json:"deprecation,omitempty"`
+	// Sunset is the time the alias is planned to be removed at. Calls via the alias receive a Sunset header
+	Sunset *time.Time `json:"sunset,omitempty"`
+}
+
+// Deprecated returns true if calls via the alias should be reported to clients
+func (alias *AlgorithmAlias) Deprecated() bool {
+	return alias.Deprecation != nil || alias.Sunset != nil
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	v1 "github.com/SneaksAndData/nexus-core/pkg/apis/science/v1"
+	"time"
+)
+
+// AlgorithmCatalogConfig controls which algorithms are listed to a caller
+type AlgorithmCatalogConfig struct {
+	// AuthorizeCallers lists only templates a caller can get according to Kubernetes RBAC, for a caller identity provided by an authenticating proxy
+	AuthorizeCallers bool `mapstructure:"authorize-callers,omitempty"`
+	// UserHeader is a request header with the authenticated user name
+	UserHeader string `mapstructure:"user-header,omitempty"`
+	// GroupsHeader is a request header with groups of the authenticated user, repeated or comma-separated
+	GroupsHeader string `mapstructure:"groups-header,omitempty"`
+	// DecisionCacheTtl is how long an authorization decision for a caller is reused, or zero to review every request
+	DecisionCacheTtl time.Duration `mapstructure:"decision-cache-ttl,omitempty"`
+}
+
+// AlgorithmCatalogEntry is a view of a NexusAlgorithmTemplate that is safe to share with algorithm callers
+type AlgorithmCatalogEntry struct {
+	Name                   string                          `json:"name"`
+	Tenant                 string                          `json:"tenant,omitempty"`
+	Description            string                          `json:"description,omitempty"`
+	VersionTag             string                          `json:"versionTag,omitempty"`
+	ComputeResources       *v1.NexusAlgorithmResources     `json:"computeResources,omitempty"`
+	DeadlineSeconds        *int32                          `json:"deadlineSeconds,omitempty"`
+	MaximumRetries         *int32                          `json:"maximumRetries,omitempty"`
+	ErrorHandlingBehaviour *v1.NexusErrorHandlingBehaviour `json:"errorHandlingBehaviour,omitempty"`
+	Workgroup              string                          `json:"workgroup,omitempty"`
+	WorkgroupDescription   string                          `json:"workgroupDescription,omitempty"`
+	Cluster                string                          `json:"cluster,omitempty"`
+	// MissingWorkgroup is true if the template references a workgroup that does not exist, so runs of the algorithm are rejected
+	MissingWorkgroup bool                   `json:"missingWorkgroup"`
+	ParameterSchema  map[string]interface{} `json:"parameterSchema,omitempty"`
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"github.com/scylladb/gocqlx/v3/table"
+	"time"
+)
+
+const (
+	CancellationOperationPending   = "PENDING"
+	CancellationOperationRunning   = "RUNNING"
+	CancellationOperationCompleted = "COMPLETED"
+	CancellationOperationFailed    = "FAILED"
+)
+
+// CancellationOperation tracks progress of a cancellation of all runs matching a tag or an algorithm and lifecycle stage filter.
+// Host is the scheduler instance executing the operation, so operations of instances that are no longer running can be resumed
+type CancellationOperation struct {
+	Id              string    `json:"id"`
+	Tag             string    `json:"tag,omitempty"`
+	Algorithm       string    `json:"algorithm,omitempty"`
+	LifecycleStages []string  `json:"lifecycleStages,omitempty"`
+	Initiator       string    `json:"initiator"`
+	Reason          string    `json:"reason"`
+	Policy          string    `json:"policy"`
+	Host            string    `json:"host,omitempty"`
+	Status          string    `json:"status"`
+	Matched         int       `json:"matched"`
+	Cancelled       int       `json:"cancelled"`
+	Failed          int       `json:"failed"`
+	LastError       string    `json:"lastError,omitempty"`
+	CreatedAt       time.Time `json:"createdAt"`
+	LastModified    time.Time `json:"lastModified"`
+}
+
+var CancellationOperationTable = table.New(table.Metadata{
+	Name: "nexus.cancellation_operations",
+	Columns: []string{
+		"id",
+		"tag",
+		"algorithm",
+		"lifecycle_stages",
+		"initiator",
+		"reason",
+		"policy",
+		"host",
+		"status",
+		"matched",
+		"cancelled",
+		"failed",
+		"last_error",
+		"created_at",
+		"last_modified",
+	},
+	PartKey: []string{
+		"id",
+	},
+	SortKey: []string{},
+})
+
+// NewCancellationOperation creates a PENDING cancellation operation
+func NewCancellationOperation(id string, initiator string, reason string, policy string) *CancellationOperation {
+	return &CancellationOperation{
+		Id:           id,
+		Initiator:    initiator,
+		Reason:       reason,
+		Policy:       policy,
+		Status:       CancellationOperationPending,
+		CreatedAt:    time.Now(),
+		LastModified: time.Now(),
+	}
+}
+
+// DeepCopy creates a copy of this CancellationOperation
+func (op *CancellationOperation) DeepCopy() *CancellationOperation {
+	cloned := *op
+	if op.LifecycleStages != nil {
+		cloned.LifecycleStages = append([]string{}, op.LifecycleStages...)
+	}
+
+	return &cloned
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"github.com/scylladb/gocqlx/v3/table"
+	"time"
+)
+
+// CheckpointAttributes holds scheduler-owned properties of a run that are not part of the core checkpoint model
+type CheckpointAttributes struct {
+	Algorithm              string    `json:"algorithm"`
+	Id                     string    `json:"id"`
+	Shard                  string    `json:"shard,omitempty"`
+	Cancelled              bool      `json:"cancelled,omitempty"`
+	CancelledBy            string    `json:"cancelledBy,omitempty"`
+	CancellationReason     string    `json:"cancellationReason,omitempty"`
+	SchedulingAttempts     int       `json:"schedulingAttempts,omitempty"`
+	LastSchedulingError    string    `json:"lastSchedulingError,omitempty"`
+	LastSchedulingAttempt  time.Time `json:"lastSchedulingAttempt,omitempty"`
+	QueueDeadline          time.Time `json:"queueDeadline,omitempty"`
+	PayloadReference       string    `json:"payloadReference,omitempty"`
+	PayloadContentHash     string    `json:"payloadContentHash,omitempty"`
+	PayloadSize            int64     `json:"payloadSize,omitempty"`
+	PayloadContentType     string    `json:"payloadContentType,omitempty"`
+	PayloadContentEncoding string    `json:"payloadContentEncoding,omitempty"`
+	ContentHash            string    `json:"contentHash,omitempty"`
+	ResultCache            string    `json:"resultCache,omitempty"`
+	ResultCacheSource      string    `json:"resultCacheSource,omitempty"`
+	TemplateVariant        string    `json:"templateVariant,omitempty"`
+}
+
+const (
+	AttributeShard                  = "shard"
+	AttributeCancelled              = "cancelled"
+	AttributeCancelledBy            = "cancelled_by"
+	AttributeCancellationReason     = "cancellation_reason"
+	AttributeSchedulingAttempts     = "scheduling_attempts"
+	AttributeLastSchedulingError    = "last_scheduling_error"
+	AttributeLastSchedulingAttempt  = "last_scheduling_attempt"
+	AttributeQueueDeadline          = "queue_deadline"
+	AttributePayloadReference       = "payload_reference"
+	AttributePayloadContentHash     = "payload_content_hash"
+	AttributePayloadSize            = "payload_size"
+	AttributePayloadContentType     = "payload_content_type"
+	AttributePayloadContentEncoding = "payload_content_encoding"
+	AttributeContentHash            = "content_hash"
+	AttributeResultCache            = "result_cache"
+	AttributeResultCacheSource      = "result_cache_source"
+	AttributeTemplateVariant        = "template_variant"
+)
+
+var CheckpointAttributesTable = table.New(table.Metadata{
+	Name: "nexus.checkpoint_attributes",
+	Columns: []string{
+		"algorithm",
+		"id",
+		AttributeShard,
+		AttributeCancelled,
+		AttributeCancelledBy,
+		AttributeCancellationReason,
+		AttributeSchedulingAttempts,
+		AttributeLastSchedulingError,
+		AttributeLastSchedulingAttempt,
+		AttributeQueueDeadline,
+		AttributePayloadReference,
+		AttributePayloadContentHash,
+		AttributePayloadSize,
+		AttributePayloadContentType,
+		AttributePayloadContentEncoding,
+		AttributeContentHash,
+		AttributeResultCache,
+		AttributeResultCacheSource,
+		AttributeTemplateVariant,
+	},
+	PartKey: []string{
+		"algorithm",
+		"id",
+	},
+	SortKey: []string{},
+})
+
+// CheckpointAttributeColumns lists all attribute columns that are not part of the primary key
+var CheckpointAttributeColumns = CheckpointAttributesTable.Metadata().Columns[len(CheckpointAttributesTable.Metadata().PartKey):]
+
+// NewCheckpointAttributes creates an empty attribute set for the provided run
+func NewCheckpointAttributes(algorithm string, id string) *CheckpointAttributes {
+	return &CheckpointAttributes{
+		Algorithm: algorithm,
+		Id:        id,
+	}
+}
@@ -0,0 +1,20 @@
+package models
+
+import "k8s.io/apimachinery/pkg/api/resource"
+
+// CompressionConfig controls compressed request and response bodies
+type CompressionConfig struct {
+	Enabled bool `mapstructure:"enabled,omitempty"`
+	// MinResponseSize is the smallest JSON response that is compressed when a client accepts it
+	MinResponseSize string `mapstructure:"min-response-size,omitempty"`
+}
+
+// MinResponseSizeBytes returns the smallest response size in bytes that is compressed
+func (c *CompressionConfig) MinResponseSizeBytes() int64 {
+	if c.MinResponseSize == "" {
+		return 0
+	}
+
+	var quantity = resource.MustParse(c.MinResponseSize)
+	return quantity.Value()
+}
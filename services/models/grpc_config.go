@@ -0,0 +1,23 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// GrpcConfig controls the gRPC API, served alongside the REST API on a separate port
+type GrpcConfig struct {
+	Enabled bool `mapstructure:"enabled,omitempty"`
+	Port    int  `mapstructure:"port,omitempty"`
+	// WatchInterval is how often WatchRun checks a run for changes
+	WatchInterval time.Duration `mapstructure:"watch-interval,omitempty"`
+}
+
+// Validate checks the watch interval, which panics the run watch ticker if not positive
+func (c *GrpcConfig) Validate() error {
+	if c.Enabled && c.WatchInterval <= 0 {
+		return errors.New("gRPC watch interval must be greater than zero")
+	}
+
+	return nil
+}
@@ -0,0 +1,9 @@
+package models
+
+import "time"
+
+// JobReconciliationConfig controls Job informers on shard clusters that move finished runs to their final lifecycle stage
+type JobReconciliationConfig struct {
+	Enabled      bool          `mapstructure:"enabled,omitempty"`
+	ResyncPeriod time.Duration `mapstructure:"resync-period,omitempty"`
+}
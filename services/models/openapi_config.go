@@ -0,0 +1,12 @@
+package models
+
+// OpenApiConfig controls serving of the API specification
+type OpenApiConfig struct {
+	Enabled bool `mapstructure:"enabled,omitempty"`
+	// UiEnabled serves an interactive API explorer for the OpenAPI v3 spec
+	UiEnabled bool `mapstructure:"ui-enabled,omitempty"`
+	// Host overrides the host clients reach the scheduler at. If not set, the host is taken from the X-Forwarded-Host header of an ingress, or the request host
+	Host string `mapstructure:"host,omitempty"`
+	// BasePath overrides the path prefix the scheduler is exposed under. If not set, the prefix is taken from the X-Forwarded-Prefix header of an ingress
+	BasePath string `mapstructure:"base-path,omitempty"`
+}
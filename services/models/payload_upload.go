@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// PayloadUploadConfig controls two-step submissions, where a payload is uploaded to the payload storage before a run referencing it is created
+type PayloadUploadConfig struct {
+	Enabled bool   `mapstructure:"enabled,omitempty"`
+	MaxSize string `mapstructure:"max-size,omitempty"`
+	// UploadUrlValidFor is how long a pre-signed upload URL can be used
+	UploadUrlValidFor time.Duration `mapstructure:"upload-url-valid-for,omitempty"`
+}
+
+// PayloadUploadSlot is a location a client can upload a run payload to, either directly using the pre-signed URL or through the scheduler
+type PayloadUploadSlot struct {
+	UploadId  string `json:"uploadId"`
+	UploadUrl string `json:"uploadUrl"`
+	// UploadFields are form fields to send in a multipart/form-data POST to the upload URL, followed by the payload as the file field
+	UploadFields map[string]string `json:"uploadFields,omitempty"`
+	Method       string            `json:"method"`
+	ExpiresAt    time.Time         `json:"expiresAt"`
+}
+
+// PayloadFormat describes how a stored payload is encoded
+type PayloadFormat struct {
+	ContentType string `json:"contentType,omitempty"`
+	// ContentEncoding is set for payloads stored compressed
+	ContentEncoding string `json:"contentEncoding,omitempty"`
+}
+
+// PayloadObject is an uploaded payload
+type PayloadObject struct {
+	PayloadFormat
+	UploadId    string `json:"uploadId"`
+	Path        string `json:"-"`
+	Uri         string `json:"uri,omitempty"`
+	Size        int64  `json:"size"`
+	ContentHash string `json:"contentHash"`
+}
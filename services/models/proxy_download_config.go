@@ -0,0 +1,25 @@
+package models
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	"time"
+)
+
+// ProxyDownloadConfig controls endpoints that stream run payloads and results through the scheduler instead of redirecting clients to the object store
+type ProxyDownloadConfig struct {
+	Enabled bool          `mapstructure:"enabled,omitempty"`
+	MaxSize string        `mapstructure:"max-size,omitempty"`
+	Timeout time.Duration `mapstructure:"timeout,omitempty"`
+	// ResultStoragePath is an S3 path run results are served from. Results stored elsewhere are not proxied. Defaults to the payload storage bucket
+	ResultStoragePath string `mapstructure:"result-storage-path,omitempty"`
+}
+
+// MaxSizeBytes returns the maximum number of bytes a single proxied response may contain, or zero if not limited
+func (c *ProxyDownloadConfig) MaxSizeBytes() int64 {
+	if c.MaxSize == "" {
+		return 0
+	}
+
+	var quantity = resource.MustParse(c.MaxSize)
+	return quantity.Value()
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"github.com/scylladb/gocqlx/v3/table"
+	"time"
+)
+
+const (
+	ResultCacheHit  = "HIT"
+	ResultCacheMiss = "MISS"
+)
+
+const (
+	ResultCacheRequestId        = "request_id"
+	ResultCachePendingRequestId = "pending_request_id"
+	ResultCacheCreatedAt        = "created_at"
+)
+
+// ResultCacheEntry points to the latest completed run with the provided content hash, and to the latest run submitted with it that was not yet seen completed.
+// Runs in flight are tracked separately, so a run that is still running or fails does not hide a result of an earlier run
+type ResultCacheEntry struct {
+	Algorithm        string    `json:"algorithm"`
+	ContentHash      string    `json:"contentHash"`
+	RequestId        string    `json:"requestId"`
+	PendingRequestId string    `json:"pendingRequestId"`
+	CreatedAt        time.Time `json:"createdAt"`
+}
+
+var ResultCacheTable = table.New(table.Metadata{
+	Name: "nexus.result_cache",
+	Columns: []string{
+		"algorithm",
+		"content_hash",
+		"request_id",
+		"pending_request_id",
+		"created_at",
+	},
+	PartKey: []string{
+		"algorithm",
+		"content_hash",
+	},
+	SortKey: []string{},
+})
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+const (
+	TimelineSourceCheckpoint = "checkpoint"
+	TimelineSourceKubernetes = "kubernetes"
+)
+
+// RunTimelineEvent is a single entry in a run timeline: either a checkpoint lifecycle transition or a Kubernetes event for the run Job or its pods.
+// Time is not set for transitions whose time is not recorded, such as BUFFERED
+type RunTimelineEvent struct {
+	Time    *time.Time `json:"time,omitempty"`
+	Source  string     `json:"source"`
+	Object  string     `json:"object,omitempty"`
+	Type    string     `json:"type,omitempty"`
+	Reason  string     `json:"reason"`
+	Message string     `json:"message,omitempty"`
+	Count   int32      `json:"count,omitempty"`
+}
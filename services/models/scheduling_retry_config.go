@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+// SchedulingRetryPolicy controls how many times and how often a failed Job submission is retried
+type SchedulingRetryPolicy struct {
+	MaxAttempts int           `mapstructure:"max-attempts,omitempty"`
+	BaseDelay   time.Duration `mapstructure:"base-delay,omitempty"`
+	MaxDelay    time.Duration `mapstructure:"max-delay,omitempty"`
+}
+
+// SchedulingRetryConfig holds the default retry policy and per-algorithm overrides
+type SchedulingRetryConfig struct {
+	Default    SchedulingRetryPolicy            `mapstructure:"default,omitempty"`
+	Algorithms map[string]SchedulingRetryPolicy `mapstructure:"algorithms,omitempty"`
+}
+
+// PolicyFor returns the retry policy for the provided algorithm
+func (c *SchedulingRetryConfig) PolicyFor(algorithm string) *SchedulingRetryPolicy {
+	if policy, ok := c.Algorithms[algorithm]; ok {
+		return &policy
+	}
+
+	return &c.Default
+}
+
+// Delay returns exponential backoff delay before the provided attempt (starting from 1 for the first retry)
+func (p *SchedulingRetryPolicy) Delay(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, p.MaxDelay)
+}
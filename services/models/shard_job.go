@@ -0,0 +1,9 @@
+package models
+
+import batchv1 "k8s.io/api/batch/v1"
+
+// ShardJob is a Job observed on a shard cluster
+type ShardJob struct {
+	Job   *batchv1.Job
+	Shard string
+}
@@ -0,0 +1,9 @@
+package models
+
+import coremodels "github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
+
+// SubmittedRun is a run that has been sent to a shard and awaits a commit
+type SubmittedRun struct {
+	Checkpoint *coremodels.CheckpointedRequest
+	Shard      string
+}
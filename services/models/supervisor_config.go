@@ -0,0 +1,37 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// SupervisorConfig controls the leader-elected loop that recovers requests left over by terminated scheduler instances
+type SupervisorConfig struct {
+	Enabled       bool          `mapstructure:"enabled,omitempty"`
+	Interval      time.Duration `mapstructure:"interval,omitempty"`
+	GracePeriod   time.Duration `mapstructure:"grace-period,omitempty"`
+	LeaseName     string        `mapstructure:"lease-name,omitempty"`
+	LeaseDuration time.Duration `mapstructure:"lease-duration,omitempty"`
+	RenewDeadline time.Duration `mapstructure:"renew-deadline,omitempty"`
+	RetryPeriod   time.Duration `mapstructure:"retry-period,omitempty"`
+	// RecoveryClaimDuration is how long a scheduler instance holds an exclusive claim on recovering requests of a terminated host
+	RecoveryClaimDuration time.Duration `mapstructure:"recovery-claim-duration,omitempty"`
+}
+
+// Validate checks durations used by the supervisor loop and leader election, which panics on invalid values
+func (c *SupervisorConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.Interval <= 0 || c.LeaseDuration <= 0 || c.RenewDeadline <= 0 || c.RetryPeriod <= 0 {
+		return errors.New("supervisor interval, lease duration, renew deadline and retry period must be greater than zero")
+	}
+
+	// leader election requires retries with jitter to fit into the renew deadline, and the renew deadline to fit into the lease
+	if c.LeaseDuration <= c.RenewDeadline || c.RenewDeadline <= time.Duration(1.2*float64(c.RetryPeriod)) {
+		return errors.New("supervisor lease duration must be greater than renew deadline, and renew deadline must be greater than 1.2 times retry period")
+	}
+
+	return nil
+}
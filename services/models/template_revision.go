@@ -0,0 +1,62 @@
+package models
+
+import (
+	"encoding/json"
+	v1 "github.com/SneaksAndData/nexus-core/pkg/apis/science/v1"
+	"github.com/scylladb/gocqlx/v3/table"
+	"time"
+)
+
+// TemplateHistoryConfig controls recording of NexusAlgorithmTemplate spec revisions, so runs can be pinned to a revision
+type TemplateHistoryConfig struct {
+	Enabled bool `mapstructure:"enabled,omitempty"`
+}
+
+// TemplateRevision is a spec of a NexusAlgorithmTemplate at a generation, as seen by the scheduler
+type TemplateRevision struct {
+	Algorithm string `json:"algorithm"`
+	// Revision is the generation of the template, incremented by Kubernetes on each spec change
+	Revision        int64  `json:"revision"`
+	ResourceVersion string `json:"resourceVersion"`
+	// TemplateUid identifies the template instance, since a template deleted and created again under the same name restarts its generations
+	TemplateUid string `json:"-"`
+	// Spec is a JSON-serialized NexusAlgorithmSpec
+	Spec      string    `json:"-"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TemplateSpecChange is a single value that differs between two template revisions
+type TemplateSpecChange struct {
+	// Path is a dot-separated path of the value in the JSON representation of the spec
+	Path string      `json:"path"`
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+var TemplateRevisionsTable = table.New(table.Metadata{
+	Name: "nexus.template_revisions",
+	Columns: []string{
+		"algorithm",
+		"revision",
+		"resource_version",
+		"template_uid",
+		"spec",
+		"created_at",
+	},
+	PartKey: []string{
+		"algorithm",
+	},
+	SortKey: []string{
+		"revision",
+	},
+})
+
+// TemplateSpec deserializes the recorded spec
+func (revision *TemplateRevision) TemplateSpec() (*v1.NexusAlgorithmSpec, error) {
+	spec := &v1.NexusAlgorithmSpec{}
+	if err := json.Unmarshal([]byte(revision.Spec), spec); err != nil {
+		return nil, err
+	}
+
+	return spec, nil
+}
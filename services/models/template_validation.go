@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// TemplateValidationConfig controls consistency checks of NexusAlgorithmTemplate resources, run each time a template or a workgroup changes
+type TemplateValidationConfig struct {
+	Enabled bool `mapstructure:"enabled,omitempty"`
+	// CheckShardResources looks up service accounts, secrets and config maps referenced by a template on the shard cluster of its workgroup
+	CheckShardResources bool `mapstructure:"check-shard-resources,omitempty"`
+	// RecordEvents emits events on a template when its validation result changes
+	RecordEvents bool `mapstructure:"record-events,omitempty"`
+}
+
+// TemplateValidationStatus is the result of the latest consistency check of a NexusAlgorithmTemplate
+type TemplateValidationStatus struct {
+	Template        string    `json:"template"`
+	ResourceVersion string    `json:"resourceVersion"`
+	Valid           bool      `json:"valid"`
+	Problems        []string  `json:"problems"`
+	ValidatedAt     time.Time `json:"validatedAt"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// TenancyConfig allows teams to manage templates and workgroups in their own namespaces. Each namespace is a tenant, and its algorithms are addressed as tenant/algorithm.
+// Templates in the runtime namespace remain addressed by name only
+type TenancyConfig struct {
+	Enabled bool `mapstructure:"enabled,omitempty"`
+	// Namespaces are tenant namespaces. If empty, templates and workgroups in all namespaces are discovered
+	Namespaces []string `mapstructure:"namespaces,omitempty"`
+	// LabelSelector limits discovered templates and workgroups to those matching the selector, including those in the runtime namespace
+	LabelSelector string `mapstructure:"label-selector,omitempty"`
+	// TenantJobNamespaces creates Jobs of a tenant in a namespace with the tenant name on each shard, instead of the runtime namespace
+	TenantJobNamespaces bool `mapstructure:"tenant-job-namespaces,omitempty"`
+	// MaxActiveRuns limits the number of buffered and running runs of each tenant. Zero disables the limit.
+	// The limit is approximate: counts are cached for ActiveRunsCacheTtl and are not shared between scheduler replicas
+	MaxActiveRuns int `mapstructure:"max-active-runs,omitempty"`
+	// ActiveRunsCacheTtl is how long a count of active runs of a tenant is reused before it is read from the checkpoint store again. Runs accepted in the meantime are added to the count. Zero counts runs on every submission
+	ActiveRunsCacheTtl time.Duration `mapstructure:"active-runs-cache-ttl,omitempty"`
+}
@@ -0,0 +1,9 @@
+package models
+
+// TrafficSplitVariant routes a share of runs of an algorithm to a different template
+type TrafficSplitVariant struct {
+	// Template is the name of the variant template, in the same namespace as the algorithm template
+	Template string `json:"template"`
+	// Weight is the percentage of runs routed to the variant
+	Weight int `json:"weight"`
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
+	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/request"
+	servicemodels "github.com/SneaksAndData/nexus/services/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrObjectTooLarge is returned when a proxied response would exceed the configured maximum size
+	ErrObjectTooLarge = errors.New("object exceeds the maximum proxied download size")
+	// ErrObjectNotFound is returned when a proxied object does not exist in the object store
+	ErrObjectNotFound = errors.New("object not found")
+	// ErrInvalidRange is returned when a requested range cannot be satisfied
+	ErrInvalidRange = errors.New("requested range not satisfiable")
+	// ErrObjectOutsideStorage is returned for run results stored outside the configured result storage path
+	ErrObjectOutsideStorage = errors.New("object is stored outside of the proxied storage path")
+)
+
+// ObjectProxy streams objects from the buffer object store to clients that cannot reach it directly. Objects are always read with the buffer credentials, from locations derived from the run, so URIs recorded on a checkpoint are never requested
+type ObjectProxy struct {
+	client        *s3.Client
+	payloadBucket string
+	payloadPrefix string
+	resultBucket  string
+	resultPrefix  string
+	maxSize       int64
+	timeout       time.Duration
+}
+
+// NewObjectProxy creates a proxy for run payloads and results stored in the buffer object store
+func NewObjectProxy(bufferConfig *request.S3BufferConfig, config *servicemodels.ProxyDownloadConfig) (*ObjectProxy, error) {
+	payloadBucket, payloadPrefix, err := parseStoragePath(bufferConfig.BufferConfig.PayloadStoragePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// results are only served from the payload storage bucket, unless configured otherwise
+	resultBucket, resultPrefix := payloadBucket, ""
+	if config.ResultStoragePath != "" {
+		resultBucket, resultPrefix, err = parseStoragePath(config.ResultStoragePath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ObjectProxy{
+		client:        newBufferStoreClient(bufferConfig),
+		payloadBucket: payloadBucket,
+		payloadPrefix: payloadPrefix,
+		resultBucket:  resultBucket,
+		resultPrefix:  resultPrefix,
+		maxSize:       config.MaxSizeBytes(),
+		timeout:       config.Timeout,
+	}, nil
+}
+
+// ServePayload streams the payload the buffer persisted for a run
+func (proxy *ObjectProxy) ServePayload(ctx context.Context, checkpoint *models.CheckpointedRequest, rangeHeader string, writer http.ResponseWriter) error {
+	key := joinKey(proxy.payloadPrefix, fmt.Sprintf("algorithm=%s", checkpoint.Algorithm), checkpoint.Id)
+
+	return proxy.serve(ctx, proxy.payloadBucket, key, rangeHeader, writer)
+}
+
+// ServeResult streams the result of a run. The result URI is only used to locate the object in the result storage path, either as an S3 path or as a path-style URL
+func (proxy *ObjectProxy) ServeResult(ctx context.Context, checkpoint *models.CheckpointedRequest, rangeHeader string, writer http.ResponseWriter) error {
+	key, err := proxy.resultKey(checkpoint.ResultUri)
+	if err != nil {
+		return err
+	}
+
+	return proxy.serve(ctx, proxy.resultBucket, key, rangeHeader, writer)
+}
+
+// resultKey resolves a key of a result object, if it is stored under the result storage path
+func (proxy *ObjectProxy) resultKey(uri string) (string, error) {
+	var bucket, key string
+	if storagePathRegex.MatchString(uri) {
+		bucket, key, _ = parseStoragePath(uri)
+	} else {
+		parsed, err := url.Parse(uri)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			return "", ErrObjectOutsideStorage
+		}
+		bucket, key, _ = strings.Cut(strings.TrimPrefix(parsed.Path, "/"), "/")
+	}
+
+	if bucket != proxy.resultBucket || key == "" || (proxy.resultPrefix != "" && !strings.HasPrefix(key, proxy.resultPrefix+"/")) {
+		return "", ErrObjectOutsideStorage
+	}
+
+	return key, nil
+}
+
+// serve streams an object to the writer. Range requests are forwarded to the object store, so objects larger than the maximum size can be downloaded in parts
+func (proxy *ObjectProxy) serve(ctx context.Context, bucket string, key string, rangeHeader string, writer http.ResponseWriter) error {
+	if proxy.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, proxy.timeout)
+		defer cancel()
+	}
+
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+	if rangeHeader != "" {
+		input.Range = aws.String(rangeHeader)
+	}
+
+	object, err := proxy.client.GetObject(ctx, input)
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) {
+			switch apiErr.ErrorCode() {
+			case "NoSuchKey", "NotFound":
+				return ErrObjectNotFound
+			case "InvalidRange":
+				return ErrInvalidRange
+			}
+		}
+		return err
+	}
+	defer func() { _ = object.Body.Close() }()
+
+	// size must be known before the response is started, otherwise a client would receive a truncated object
+	if proxy.maxSize > 0 && aws.ToInt64(object.ContentLength) > proxy.maxSize {
+		return ErrObjectTooLarge
+	}
+
+	headers := writer.Header()
+	if object.ContentType != nil {
+		headers.Set("Content-Type", *object.ContentType)
+	}
+	if object.ContentLength != nil {
+		headers.Set("Content-Length", strconv.FormatInt(*object.ContentLength, 10))
+	}
+	if object.ContentEncoding != nil {
+		headers.Set("Content-Encoding", *object.ContentEncoding)
+	}
+	if object.ETag != nil {
+		headers.Set("ETag", *object.ETag)
+	}
+	if object.LastModified != nil {
+		headers.Set("Last-Modified", object.LastModified.UTC().Format(http.TimeFormat))
+	}
+	headers.Set("Accept-Ranges", "bytes")
+
+	status := http.StatusOK
+	if object.ContentRange != nil {
+		headers.Set("Content-Range", *object.ContentRange)
+		status = http.StatusPartialContent
+	}
+	writer.WriteHeader(status)
+
+	_, err = io.Copy(writer, object.Body)
+	return err
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	coremodels "github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
+	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/request"
+	"github.com/SneaksAndData/nexus/services/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newObjectStore starts a minimal S3-compatible server that serves a fixed set of objects
+func newObjectStore(t *testing.T, objects map[string]string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		content, ok := objects[request.URL.Path]
+		if !ok {
+			writer.Header().Set("Content-Type", "application/xml")
+			writer.WriteHeader(http.StatusNotFound)
+			_, _ = writer.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`))
+			return
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		http.ServeContent(writer, request, "object.json", time.Now(), strings.NewReader(content))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func newTestObjectProxy(t *testing.T, endpoint string, maxSize string) *ObjectProxy {
+	proxy, err := NewObjectProxy(&request.S3BufferConfig{
+		BufferConfig: &request.BufferConfig{
+			PayloadStoragePath: "s3a://bucket/nexus/payloads",
+			PayloadValidFor:    time.Hour,
+		},
+		AccessKeyID:     "test",
+		SecretAccessKey: "test",
+		Region:          "us-east-1",
+		Endpoint:        endpoint,
+	}, &models.ProxyDownloadConfig{Enabled: true, MaxSize: maxSize, Timeout: time.Second, ResultStoragePath: "s3a://bucket/results"})
+	if err != nil {
+		t.Errorf("failed to create an object proxy: %s", err)
+		t.FailNow()
+	}
+
+	return proxy
+}
+
+func newProxiedCheckpoint(resultUri string) *coremodels.CheckpointedRequest {
+	return &coremodels.CheckpointedRequest{
+		Algorithm:  "test-algorithm",
+		Id:         "8b2f5c4e-1f6a-4b55-9a39-2d8c2bb1b7a1",
+		ResultUri:  resultUri,
+		PayloadUri: "https://storage.example.com/bucket/nexus/payloads/algorithm=test-algorithm/8b2f5c4e-1f6a-4b55-9a39-2d8c2bb1b7a1?X-Amz-Signature=expired",
+	}
+}
+
+func TestObjectProxy_ServeResult(t *testing.T) {
+	store := newObjectStore(t, map[string]string{"/bucket/results/result.json": `{"result": 1}`})
+	proxy := newTestObjectProxy(t, store.URL, "1Ki")
+
+	// a pre-signed URI is only used to locate the object, so it is served even after the signature expires
+	recorder := httptest.NewRecorder()
+	if err := proxy.ServeResult(context.TODO(), newProxiedCheckpoint("https://storage.example.com/bucket/results/result.json?X-Amz-Signature=expired"), "", recorder); err != nil {
+		t.Errorf("failed to proxy an object: %s", err)
+		t.FailNow()
+	}
+
+	if recorder.Code != http.StatusOK || recorder.Body.String() != `{"result": 1}` {
+		t.Errorf("unexpected proxied response %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	if recorder.Header().Get("Content-Type") != "application/json" || recorder.Header().Get("Content-Length") != "13" || recorder.Header().Get("Accept-Ranges") != "bytes" {
+		t.Errorf("expected object headers to be proxied, but got %v", recorder.Header())
+	}
+}
+
+func TestObjectProxy_ServeResultOutsideStorage(t *testing.T) {
+	store := newObjectStore(t, map[string]string{"/bucket/nexus/payloads/algorithm=test-algorithm/other": `{}`})
+	proxy := newTestObjectProxy(t, store.URL, "1Ki")
+
+	for _, uri := range []string{
+		store.URL + "/bucket/nexus/payloads/algorithm=test-algorithm/other",
+		"s3a://other-bucket/results/result.json",
+		"s3a://bucket/results-other/result.json",
+		"http://169.254.169.254/latest/meta-data",
+		"file:///etc/passwd",
+	} {
+		recorder := httptest.NewRecorder()
+		if err := proxy.ServeResult(context.TODO(), newProxiedCheckpoint(uri), "", recorder); !errors.Is(err, ErrObjectOutsideStorage) || recorder.Body.Len() != 0 {
+			t.Errorf("expected a result outside of the result storage path %s to be rejected, but got: %v", uri, err)
+		}
+	}
+}
+
+func TestObjectProxy_ServePayload(t *testing.T) {
+	store := newObjectStore(t, map[string]string{"/bucket/nexus/payloads/algorithm=test-algorithm/8b2f5c4e-1f6a-4b55-9a39-2d8c2bb1b7a1": `{"payload": 1}`})
+	proxy := newTestObjectProxy(t, store.URL, "1Ki")
+
+	recorder := httptest.NewRecorder()
+	if err := proxy.ServePayload(context.TODO(), newProxiedCheckpoint(""), "bytes=2-8", recorder); err != nil {
+		t.Errorf("failed to proxy an object range: %s", err)
+		t.FailNow()
+	}
+
+	if recorder.Code != http.StatusPartialContent || recorder.Body.String() != `payload` || recorder.Header().Get("Content-Range") != "bytes 2-8/14" {
+		t.Errorf("unexpected proxied range response %d: %s (%v)", recorder.Code, recorder.Body.String(), recorder.Header())
+	}
+}
+
+func TestObjectProxy_ServeTooLarge(t *testing.T) {
+	store := newObjectStore(t, map[string]string{"/bucket/results/result.json": string(bytes.Repeat([]byte("a"), 2048))})
+	proxy := newTestObjectProxy(t, store.URL, "1Ki")
+	checkpoint := newProxiedCheckpoint("s3a://bucket/results/result.json")
+
+	recorder := httptest.NewRecorder()
+	if err := proxy.ServeResult(context.TODO(), checkpoint, "", recorder); !errors.Is(err, ErrObjectTooLarge) {
+		t.Errorf("expected an object larger than the limit to be rejected, but got: %v", err)
+	}
+
+	// range within the limit can still be downloaded
+	recorder = httptest.NewRecorder()
+	if err := proxy.ServeResult(context.TODO(), checkpoint, "bytes=0-1023", recorder); err != nil || recorder.Body.Len() != 1024 {
+		t.Errorf("expected a range within the limit to be proxied, but got %d bytes (%v)", recorder.Body.Len(), err)
+	}
+}
+
+func TestObjectProxy_ServeNotFound(t *testing.T) {
+	store := newObjectStore(t, map[string]string{})
+	proxy := newTestObjectProxy(t, store.URL, "")
+
+	recorder := httptest.NewRecorder()
+	if err := proxy.ServeResult(context.TODO(), newProxiedCheckpoint("s3a://bucket/results/missing.json"), "", recorder); !errors.Is(err, ErrObjectNotFound) || recorder.Body.Len() != 0 {
+		t.Errorf("expected a missing object to be reported without writing a response, but got: %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"fmt"
+	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/request"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"regexp"
+	"strings"
+)
+
+var storagePathRegex = regexp.MustCompile("^s3a?://([^/]+)/?(.*)$")
+
+// parseStoragePath splits an S3 path into a bucket and a key prefix
+func parseStoragePath(path string) (string, string, error) {
+	matches := storagePathRegex.FindStringSubmatch(path)
+	if matches == nil {
+		return "", "", fmt.Errorf("storage path %s is not an S3 path", path)
+	}
+
+	return matches[1], strings.TrimSuffix(matches[2], "/"), nil
+}
+
+// joinKey joins parts of an object key, skipping an empty prefix
+func joinKey(prefix string, parts ...string) string {
+	if prefix == "" {
+		return strings.Join(parts, "/")
+	}
+
+	return prefix + "/" + strings.Join(parts, "/")
+}
+
+// newBufferStoreClient creates a client for the S3-compatible storage used by the buffer, with the buffer credentials
+func newBufferStoreClient(bufferConfig *request.S3BufferConfig) *s3.Client {
+	return s3.New(s3.Options{
+		Credentials:                credentials.NewStaticCredentialsProvider(bufferConfig.AccessKeyID, bufferConfig.SecretAccessKey, ""),
+		BaseEndpoint:               aws.String(bufferConfig.Endpoint),
+		Region:                     bufferConfig.Region,
+		UsePathStyle:               true,
+		AppID:                      "nexus",
+		RequestChecksumCalculation: aws.RequestChecksumCalculationWhenRequired,
+		ResponseChecksumValidation: aws.ResponseChecksumValidationWhenRequired,
+	})
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
+	nexusv1 "github.com/SneaksAndData/nexus/proto/nexus/v1"
+	"github.com/ugorji/go/codec"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"reflect"
+	"strings"
+)
+
+const (
+	ContentTypeJson     = "application/json"
+	ContentTypeMsgpack  = "application/msgpack"
+	ContentTypeProtobuf = "application/x-protobuf"
+	ContentTypeBinary   = "application/octet-stream"
+)
+
+// ErrUnsupportedContentType is returned for request bodies that cannot be decoded into an AlgorithmRequest
+var ErrUnsupportedContentType = errors.New("unsupported content type")
+
+// NormalizeContentType maps aliases of supported request content types to the type payloads are stored with
+func NormalizeContentType(contentType string) string {
+	switch strings.ToLower(strings.TrimSpace(contentType)) {
+	case "", ContentTypeJson:
+		return ContentTypeJson
+	case ContentTypeMsgpack, "application/x-msgpack", "application/vnd.msgpack":
+		return ContentTypeMsgpack
+	case ContentTypeProtobuf, "application/protobuf", "application/vnd.google.protobuf":
+		return ContentTypeProtobuf
+	case ContentTypeBinary:
+		return ContentTypeBinary
+	default:
+		return contentType
+	}
+}
+
+// DecodeAlgorithmRequest decodes a MessagePack or Protobuf request body into an AlgorithmRequest
+func DecodeAlgorithmRequest(contentType string, body []byte, request *models.AlgorithmRequest) error {
+	switch NormalizeContentType(contentType) {
+	case ContentTypeMsgpack:
+		return decodeMsgpackRequest(body, request)
+	case ContentTypeProtobuf:
+		return decodeProtobufRequest(body, request)
+	default:
+		return ErrUnsupportedContentType
+	}
+}
+
+// decodeMsgpackRequest decodes a MessagePack map with the same keys as the JSON request
+func decodeMsgpackRequest(body []byte, request *models.AlgorithmRequest) error {
+	handle := &codec.MsgpackHandle{}
+	handle.MapType = reflect.TypeOf(map[string]interface{}(nil))
+	handle.RawToString = true
+
+	return codec.NewDecoderBytes(body, handle).Decode(request)
+}
+
+// decodeProtobufRequest decodes a nexus.v1.AlgorithmRequest message, published in proto/nexus/v1/algorithm_request.proto
+func decodeProtobufRequest(body []byte, request *models.AlgorithmRequest) error {
+	message := &nexusv1.AlgorithmRequest{}
+	if err := proto.Unmarshal(body, message); err != nil {
+		return err
+	}
+
+	return FromProtoAlgorithmRequest(message, request)
+}
+
+// FromProtoAlgorithmRequest converts a nexus.v1.AlgorithmRequest message into an AlgorithmRequest
+func FromProtoAlgorithmRequest(message *nexusv1.AlgorithmRequest, request *models.AlgorithmRequest) error {
+	// JSON mapping of the message matches the JSON request
+	serialized, err := protojson.Marshal(message)
+	if err != nil { // coverage-ignore
+		return err
+	}
+
+	return json.Unmarshal(serialized, request)
+}
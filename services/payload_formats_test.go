@@ -0,0 +1,77 @@
+package services
+
+import (
+	"errors"
+	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
+	nexusv1 "github.com/SneaksAndData/nexus/proto/nexus/v1"
+	"github.com/ugorji/go/codec"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"testing"
+)
+
+func TestDecodeAlgorithmRequest_Msgpack(t *testing.T) {
+	var body []byte
+	if err := codec.NewEncoderBytes(&body, &codec.MsgpackHandle{}).Encode(map[string]interface{}{
+		"algorithmParameters": map[string]interface{}{"input": map[string]interface{}{"size": 2}},
+		"tag":                 "msgpack",
+		"parentRequest":       map[string]interface{}{"requestId": "parent", "algorithmName": "test-algorithm"},
+	}); err != nil {
+		t.Errorf("failed to encode a msgpack request: %s", err)
+		t.FailNow()
+	}
+
+	request := &models.AlgorithmRequest{}
+	if err := DecodeAlgorithmRequest("application/x-msgpack", body, request); err != nil {
+		t.Errorf("failed to decode a msgpack request: %s", err)
+		t.FailNow()
+	}
+
+	if request.Tag != "msgpack" || request.ParentRequest == nil || request.ParentRequest.RequestId != "parent" {
+		t.Errorf("unexpected decoded msgpack request: %v", request)
+	}
+
+	// nested maps must stay serializable to JSON
+	if _, ok := request.AlgorithmParameters["input"].(map[string]interface{}); !ok {
+		t.Errorf("expected nested parameters to be decoded as string maps, but got %T", request.AlgorithmParameters["input"])
+	}
+}
+
+func TestDecodeAlgorithmRequest_Protobuf(t *testing.T) {
+	parameters, _ := structpb.NewStruct(map[string]interface{}{"input": "value", "size": 2})
+	message := &nexusv1.AlgorithmRequest{
+		AlgorithmParameters: parameters,
+		Tag:                 "protobuf",
+		ParentRequest:       &nexusv1.AlgorithmRequestRef{RequestId: "parent", AlgorithmName: "test-algorithm"},
+	}
+
+	body, err := proto.Marshal(message)
+	if err != nil {
+		t.Errorf("failed to encode a protobuf request: %s", err)
+		t.FailNow()
+	}
+
+	request := &models.AlgorithmRequest{}
+	if err := DecodeAlgorithmRequest(ContentTypeProtobuf, body, request); err != nil {
+		t.Errorf("failed to decode a protobuf request: %s", err)
+		t.FailNow()
+	}
+
+	if request.Tag != "protobuf" || request.AlgorithmParameters["input"] != "value" || request.ParentRequest == nil || request.ParentRequest.AlgorithmName != "test-algorithm" {
+		t.Errorf("unexpected decoded protobuf request: %v", request)
+	}
+
+	if err := DecodeAlgorithmRequest(ContentTypeProtobuf, []byte("not a message"), &models.AlgorithmRequest{}); err == nil {
+		t.Errorf("expected an invalid protobuf message to be rejected")
+	}
+}
+
+func TestDecodeAlgorithmRequest_Unsupported(t *testing.T) {
+	if err := DecodeAlgorithmRequest("text/csv", []byte("a,b"), &models.AlgorithmRequest{}); !errors.Is(err, ErrUnsupportedContentType) {
+		t.Errorf("expected text/csv to be unsupported, but got: %v", err)
+	}
+
+	if contentType := NormalizeContentType("application/vnd.google.protobuf"); contentType != ContentTypeProtobuf {
+		t.Errorf("expected protobuf aliases to be normalized, but got %s", contentType)
+	}
+}
@@ -0,0 +1,329 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	v1 "github.com/SneaksAndData/nexus-core/pkg/apis/science/v1"
+	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/request"
+	"github.com/SneaksAndData/nexus/services/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+	"github.com/google/uuid"
+	"io"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"net/http"
+	"time"
+)
+
+const (
+	// PayloadReferenceParameter is the algorithm parameter that points an algorithm to an uploaded payload
+	PayloadReferenceParameter = "payloadReference"
+	// PayloadEncodingAnnotation makes the scheduler store payloads of the annotated template submitted with CreateRun compressed, either gzip or zstd, and pass them to the algorithm by reference.
+	// Algorithms of the template receive only the payloadReference parameter, so the annotation must only be added to templates whose algorithms read it
+	PayloadEncodingAnnotation = "science.sneaksanddata.com/payload-encoding"
+)
+
+var (
+	// ErrPayloadNotFound is returned when a run references an upload that does not exist
+	ErrPayloadNotFound = errors.New("uploaded payload not found")
+	// ErrPayloadTooLarge is returned when an upload exceeds the maximum payload size
+	ErrPayloadTooLarge = errors.New("uploaded payload exceeds the maximum size")
+	// ErrInvalidUploadId is returned for upload identifiers that are not in the format issued by the scheduler
+	ErrInvalidUploadId = errors.New("invalid upload identifier")
+)
+
+// PayloadUploadStore manages payloads uploaded ahead of run creation, in the payload storage used by the buffer
+type PayloadUploadStore struct {
+	client          *s3.Client
+	signer          *s3.PresignClient
+	uploader        *manager.Uploader
+	bucket          string
+	prefix          string
+	maxSize         int64
+	uploadValidFor  time.Duration
+	payloadValidFor time.Duration
+}
+
+// NewPayloadUploadStore creates an upload store that uses the same S3-compatible storage and credentials as the buffer
+func NewPayloadUploadStore(bufferConfig *request.S3BufferConfig, config *models.PayloadUploadConfig) (*PayloadUploadStore, error) {
+	bucket, prefix, err := parseStoragePath(bufferConfig.BufferConfig.PayloadStoragePath)
+	if err != nil {
+		return nil, err
+	}
+
+	client := newBufferStoreClient(bufferConfig)
+
+	var maxSize int64
+	if config.MaxSize != "" {
+		quantity, err := resource.ParseQuantity(config.MaxSize)
+		if err != nil {
+			return nil, err
+		}
+		maxSize = quantity.Value()
+	}
+
+	return &PayloadUploadStore{
+		client:          client,
+		signer:          s3.NewPresignClient(client),
+		uploader:        manager.NewUploader(client),
+		bucket:          bucket,
+		prefix:          prefix,
+		maxSize:         maxSize,
+		uploadValidFor:  config.UploadUrlValidFor,
+		payloadValidFor: bufferConfig.BufferConfig.PayloadValidFor,
+	}, nil
+}
+
+// uploadKey returns a location of an upload, next to payloads persisted by the buffer
+func (store *PayloadUploadStore) uploadKey(algorithmName string, uploadId string) (string, error) {
+	if _, err := uuid.Parse(uploadId); err != nil {
+		return "", ErrInvalidUploadId
+	}
+
+	return joinKey(store.prefix, "uploads", "algorithm="+algorithmName, uploadId), nil
+}
+
+// runPayloadKey returns a location of a payload stored for a run. Run payloads are kept apart from uploads, so a request id cannot be passed as an upload id to attach a payload of another run
+func (store *PayloadUploadStore) runPayloadKey(algorithmName string, requestId string) string {
+	return joinKey(store.prefix, "runs", "algorithm="+algorithmName, requestId)
+}
+
+// CreateSlot allocates an upload location and generates a pre-signed POST form for it. Unlike a pre-signed PUT URL, the form policy limits the size of the uploaded payload
+func (store *PayloadUploadStore) CreateSlot(ctx context.Context, algorithmName string) (*models.PayloadUploadSlot, error) {
+	uploadId := uuid.New().String()
+	key, err := store.uploadKey(algorithmName, uploadId)
+	if err != nil { // coverage-ignore
+		return nil, err
+	}
+
+	presigned, err := store.signer.PresignPostObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(store.bucket),
+		Key:    aws.String(key),
+	}, func(options *s3.PresignPostOptions) {
+		options.Expires = store.uploadValidFor
+		if store.maxSize > 0 {
+			options.Conditions = append(options.Conditions, []interface{}{"content-length-range", 0, store.maxSize})
+		}
+	})
+	if err != nil { // coverage-ignore
+		return nil, err
+	}
+
+	return &models.PayloadUploadSlot{
+		UploadId:     uploadId,
+		UploadUrl:    presigned.URL,
+		UploadFields: presigned.Values,
+		Method:       http.MethodPost,
+		ExpiresAt:    time.Now().Add(store.uploadValidFor),
+	}, nil
+}
+
+// Upload streams a payload into an upload slot, without holding it in memory. Content type and encoding, if provided, are recorded on the object and the payload is stored as is
+func (store *PayloadUploadStore) Upload(ctx context.Context, algorithmName string, uploadId string, format models.PayloadFormat, body io.Reader) (*models.PayloadObject, error) {
+	key, err := store.uploadKey(algorithmName, uploadId)
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := store.put(ctx, key, format, body)
+	if err != nil {
+		return nil, err
+	}
+
+	object.UploadId = uploadId
+	return object, nil
+}
+
+// put streams a payload into the object under the key and computes its size and content hash
+func (store *PayloadUploadStore) put(ctx context.Context, key string, format models.PayloadFormat, body io.Reader) (*models.PayloadObject, error) {
+	hash := sha256.New()
+	counter := &sizeLimitedReader{reader: io.TeeReader(body, hash), maxSize: store.maxSize}
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(store.bucket),
+		Key:    aws.String(key),
+		Body:   counter,
+	}
+	if format.ContentType != "" {
+		input.ContentType = aws.String(format.ContentType)
+	}
+	if format.ContentEncoding != "" {
+		input.ContentEncoding = aws.String(format.ContentEncoding)
+	}
+
+	if _, err := store.uploader.Upload(ctx, input); err != nil {
+		if errors.Is(err, ErrPayloadTooLarge) {
+			return nil, ErrPayloadTooLarge
+		}
+		return nil, err
+	}
+
+	return &models.PayloadObject{
+		PayloadFormat: format,
+		Path:          fmt.Sprintf("s3a://%s/%s", store.bucket, key),
+		Size:          counter.size,
+		ContentHash:   hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+// TemplatePayloadEncoding returns the encoding payloads of a template are stored with, or an empty string if the buffer stores them as is
+func TemplatePayloadEncoding(template *v1.NexusAlgorithmTemplate) (string, error) {
+	encoding := template.Annotations[PayloadEncodingAnnotation]
+	if encoding != "" && encoding != EncodingGzip && encoding != EncodingZstd {
+		return "", fmt.Errorf("invalid %s annotation on template %s: encoding %s is not supported", PayloadEncodingAnnotation, template.Name, encoding)
+	}
+
+	return encoding, nil
+}
+
+// Offload stores algorithm parameters of a run compressed with the provided encoding and generates a pre-signed download URL for the algorithm
+func (store *PayloadUploadStore) Offload(ctx context.Context, algorithmName string, requestId string, encoding string, parameters map[string]interface{}) (*models.PayloadObject, error) {
+	serialized, err := json.Marshal(parameters)
+	if err != nil { // coverage-ignore
+		return nil, err
+	}
+
+	compressed := &bytes.Buffer{}
+	encoder, err := NewEncoder(encoding, compressed)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := encoder.Write(serialized); err != nil { // coverage-ignore
+		return nil, err
+	}
+
+	if err := encoder.Close(); err != nil { // coverage-ignore
+		return nil, err
+	}
+
+	return store.Store(ctx, algorithmName, requestId, models.PayloadFormat{ContentType: ContentTypeJson, ContentEncoding: encoding}, compressed)
+}
+
+// Store saves a payload of a run in its original format and generates a pre-signed download URL for the algorithm
+func (store *PayloadUploadStore) Store(ctx context.Context, algorithmName string, requestId string, format models.PayloadFormat, body io.Reader) (*models.PayloadObject, error) {
+	key := store.runPayloadKey(algorithmName, requestId)
+	object, err := store.put(ctx, key, format, body)
+	if err != nil {
+		return nil, err
+	}
+
+	object.Uri, err = store.presignDownload(ctx, key)
+	if err != nil { // coverage-ignore
+		return nil, err
+	}
+
+	return object, nil
+}
+
+// presignDownload generates a URL an algorithm can download an uploaded payload with, valid for the payload validity period of the buffer
+func (store *PayloadUploadStore) presignDownload(ctx context.Context, key string) (string, error) {
+	presigned, err := store.signer.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(store.bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(store.payloadValidFor))
+	if err != nil { // coverage-ignore
+		return "", err
+	}
+
+	return presigned.URL, nil
+}
+
+// Resolve validates that an upload exists, computes its content hash and generates a pre-signed download URL for the algorithm
+func (store *PayloadUploadStore) Resolve(ctx context.Context, algorithmName string, uploadId string) (*models.PayloadObject, error) {
+	key, err := store.uploadKey(algorithmName, uploadId)
+	if err != nil {
+		return nil, err
+	}
+
+	// objects uploaded with a pre-signed form are not seen by the scheduler before the run is created, so the size is checked before the object is read
+	head, err := store.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(store.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, payloadError(err)
+	}
+
+	if store.maxSize > 0 && aws.ToInt64(head.ContentLength) > store.maxSize {
+		return nil, ErrPayloadTooLarge
+	}
+
+	object, err := store.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket:  aws.String(store.bucket),
+		Key:     aws.String(key),
+		IfMatch: head.ETag,
+	})
+	if err != nil {
+		return nil, payloadError(err)
+	}
+	defer func() { _ = object.Body.Close() }()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, &sizeLimitedReader{reader: object.Body, maxSize: store.maxSize})
+	if err != nil {
+		return nil, err
+	}
+
+	uri, err := store.presignDownload(ctx, key)
+	if err != nil { // coverage-ignore
+		return nil, err
+	}
+
+	return &models.PayloadObject{
+		PayloadFormat: models.PayloadFormat{
+			ContentType:     aws.ToString(object.ContentType),
+			ContentEncoding: aws.ToString(object.ContentEncoding),
+		},
+		UploadId:    uploadId,
+		Path:        fmt.Sprintf("s3a://%s/%s", store.bucket, key),
+		Uri:         uri,
+		Size:        size,
+		ContentHash: hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+// payloadError maps object store errors of missing uploads to ErrPayloadNotFound
+func payloadError(err error) error {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && (apiErr.ErrorCode() == "NoSuchKey" || apiErr.ErrorCode() == "NotFound") {
+		return ErrPayloadNotFound
+	}
+
+	return err
+}
+
+// SetPayloadReference records the uploaded payload a run was created with
+func (scheduler *RequestScheduler) SetPayloadReference(requestId string, algorithmName string, payload *models.PayloadObject) error {
+	attributes := models.NewCheckpointAttributes(algorithmName, requestId)
+	attributes.PayloadReference = payload.Path
+	attributes.PayloadContentHash = payload.ContentHash
+	attributes.PayloadSize = payload.Size
+	attributes.PayloadContentType = payload.ContentType
+	attributes.PayloadContentEncoding = payload.ContentEncoding
+
+	return scheduler.store.UpsertAttributes(attributes, models.AttributePayloadReference, models.AttributePayloadContentHash, models.AttributePayloadSize, models.AttributePayloadContentType, models.AttributePayloadContentEncoding)
+}
+
+// sizeLimitedReader counts bytes read and fails once the maximum size is exceeded
+type sizeLimitedReader struct {
+	reader  io.Reader
+	maxSize int64
+	size    int64
+}
+
+func (r *sizeLimitedReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.size += int64(n)
+	if r.maxSize > 0 && r.size > r.maxSize {
+		return n, ErrPayloadTooLarge
+	}
+
+	return n, err
+}
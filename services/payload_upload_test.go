@@ -0,0 +1,304 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	v1 "github.com/SneaksAndData/nexus-core/pkg/apis/science/v1"
+	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/request"
+	"github.com/SneaksAndData/nexus/services/models"
+	"io"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newPayloadStore starts a minimal S3-compatible server that keeps objects in memory
+func newPayloadStore(t *testing.T) (*httptest.Server, map[string][]byte) {
+	objects := map[string][]byte{}
+	lock := sync.Mutex{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		switch request.Method {
+		case http.MethodPost:
+			// pre-signed form upload: the key is a form field and the payload is the file
+			if err := request.ParseMultipartForm(1 << 20); err != nil {
+				writer.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			file, _, err := request.FormFile("file")
+			if err != nil {
+				writer.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			content, _ := io.ReadAll(file)
+			objects[request.URL.Path+"/"+request.FormValue("key")] = content
+			writer.WriteHeader(http.StatusNoContent)
+		case http.MethodPut:
+			content, err := io.ReadAll(request.Body)
+			if err != nil {
+				writer.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			objects[request.URL.Path] = content
+			writer.WriteHeader(http.StatusOK)
+		case http.MethodHead:
+			content, ok := objects[request.URL.Path]
+			if !ok {
+				writer.WriteHeader(http.StatusNotFound)
+				return
+			}
+			writer.Header().Set("Content-Length", strconv.Itoa(len(content)))
+			writer.WriteHeader(http.StatusOK)
+		case http.MethodGet:
+			content, ok := objects[request.URL.Path]
+			if !ok {
+				writer.Header().Set("Content-Type", "application/xml")
+				writer.WriteHeader(http.StatusNotFound)
+				_, _ = writer.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`))
+				return
+			}
+			_, _ = writer.Write(content)
+		default:
+			writer.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, objects
+}
+
+func newTestPayloadUploadStore(t *testing.T, endpoint string, maxSize string) *PayloadUploadStore {
+	store, err := NewPayloadUploadStore(&request.S3BufferConfig{
+		BufferConfig: &request.BufferConfig{
+			PayloadStoragePath: "s3a://bucket/nexus/payloads",
+			PayloadValidFor:    time.Hour,
+		},
+		AccessKeyID:     "test",
+		SecretAccessKey: "test",
+		Region:          "us-east-1",
+		Endpoint:        endpoint,
+	}, &models.PayloadUploadConfig{
+		Enabled:           true,
+		MaxSize:           maxSize,
+		UploadUrlValidFor: time.Minute,
+	})
+	if err != nil {
+		t.Errorf("failed to create a payload upload store: %s", err)
+		t.FailNow()
+	}
+
+	return store
+}
+
+func contentHash(content string) string {
+	hash := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(hash[:])
+}
+
+func TestPayloadUploadStore_Upload(t *testing.T) {
+	server, objects := newPayloadStore(t)
+	store := newTestPayloadUploadStore(t, server.URL, "1Ki")
+	uploadId := "5d5f0b5c-2d8e-4c1a-9a8e-3a0d1f1c6b1e"
+
+	object, err := store.Upload(context.TODO(), "test-algorithm", uploadId, models.PayloadFormat{}, strings.NewReader(`{"input": "large"}`))
+	if err != nil {
+		t.Errorf("failed to upload a payload: %s", err)
+		t.FailNow()
+	}
+
+	if object.Size != 18 || object.ContentHash != contentHash(`{"input": "large"}`) || object.Path != "s3a://bucket/nexus/payloads/uploads/algorithm=test-algorithm/"+uploadId {
+		t.Errorf("unexpected uploaded payload: %v", object)
+	}
+
+	if string(objects["/bucket/nexus/payloads/uploads/algorithm=test-algorithm/"+uploadId]) != `{"input": "large"}` {
+		t.Errorf("expected the payload to be stored under the upload slot, but got %v", objects)
+	}
+}
+
+func TestPayloadUploadStore_UploadTooLarge(t *testing.T) {
+	server, objects := newPayloadStore(t)
+	store := newTestPayloadUploadStore(t, server.URL, "1Ki")
+
+	_, err := store.Upload(context.TODO(), "test-algorithm", "5d5f0b5c-2d8e-4c1a-9a8e-3a0d1f1c6b1e", models.PayloadFormat{}, bytes.NewReader(bytes.Repeat([]byte("a"), 2048)))
+	if !errors.Is(err, ErrPayloadTooLarge) {
+		t.Errorf("expected a payload larger than the limit to be rejected, but got: %v", err)
+	}
+
+	if len(objects) != 0 {
+		t.Errorf("expected a rejected payload not to be stored, but got %d objects", len(objects))
+	}
+}
+
+func TestPayloadUploadStore_UploadInvalidId(t *testing.T) {
+	server, _ := newPayloadStore(t)
+	store := newTestPayloadUploadStore(t, server.URL, "")
+
+	if _, err := store.Upload(context.TODO(), "test-algorithm", "../other-algorithm/payload", models.PayloadFormat{}, strings.NewReader("{}")); !errors.Is(err, ErrInvalidUploadId) {
+		t.Errorf("expected an upload id in an unexpected format to be rejected, but got: %v", err)
+	}
+}
+
+func TestPayloadUploadStore_PresignedUpload(t *testing.T) {
+	server, _ := newPayloadStore(t)
+	store := newTestPayloadUploadStore(t, server.URL, "1Ki")
+
+	slot, err := store.CreateSlot(context.TODO(), "test-algorithm")
+	if err != nil {
+		t.Errorf("failed to create an upload slot: %s", err)
+		t.FailNow()
+	}
+
+	if slot.Method != http.MethodPost || slot.UploadUrl != server.URL+"/bucket" || slot.UploadFields["key"] != "nexus/payloads/uploads/algorithm=test-algorithm/"+slot.UploadId {
+		t.Errorf("unexpected upload slot: %v", slot)
+		t.FailNow()
+	}
+
+	policy, _ := base64.StdEncoding.DecodeString(slot.UploadFields["policy"])
+	if !strings.Contains(string(policy), `["content-length-range",0,1024]`) {
+		t.Errorf("expected the upload policy to limit the payload size, but got %s", policy)
+	}
+
+	// resolving before the client uploads anything
+	if _, err := store.Resolve(context.TODO(), "test-algorithm", slot.UploadId); !errors.Is(err, ErrPayloadNotFound) {
+		t.Errorf("expected a missing upload to be reported, but got: %v", err)
+	}
+
+	form := &bytes.Buffer{}
+	formWriter := multipart.NewWriter(form)
+	for field, value := range slot.UploadFields {
+		_ = formWriter.WriteField(field, value)
+	}
+	file, _ := formWriter.CreateFormFile("file", "payload.json")
+	_, _ = file.Write([]byte(`{"input": "presigned"}`))
+	_ = formWriter.Close()
+
+	response, err := http.Post(slot.UploadUrl, formWriter.FormDataContentType(), form)
+	if err != nil || response.StatusCode != http.StatusNoContent {
+		t.Errorf("failed to upload using a pre-signed URL: %v", err)
+		t.FailNow()
+	}
+	_ = response.Body.Close()
+
+	object, err := store.Resolve(context.TODO(), "test-algorithm", slot.UploadId)
+	if err != nil {
+		t.Errorf("failed to resolve an uploaded payload: %s", err)
+		t.FailNow()
+	}
+
+	if object.Size != 22 || object.ContentHash != contentHash(`{"input": "presigned"}`) || !strings.HasPrefix(object.Uri, server.URL+"/bucket/nexus/payloads/uploads/algorithm%3Dtest-algorithm/"+slot.UploadId) {
+		t.Errorf("unexpected resolved payload: %v", object)
+	}
+}
+
+func TestPayloadUploadStore_ResolveTooLarge(t *testing.T) {
+	server, objects := newPayloadStore(t)
+	store := newTestPayloadUploadStore(t, server.URL, "1Ki")
+	uploadId := "5d5f0b5c-2d8e-4c1a-9a8e-3a0d1f1c6b1e"
+	objects["/bucket/nexus/payloads/uploads/algorithm=test-algorithm/"+uploadId] = bytes.Repeat([]byte("a"), 2048)
+
+	if _, err := store.Resolve(context.TODO(), "test-algorithm", uploadId); !errors.Is(err, ErrPayloadTooLarge) {
+		t.Errorf("expected an upload larger than the limit to be rejected, but got: %v", err)
+	}
+}
+
+func TestPayloadUploadStore_ResolveRunPayload(t *testing.T) {
+	server, _ := newPayloadStore(t)
+	store := newTestPayloadUploadStore(t, server.URL, "1Ki")
+	requestId := "5d5f0b5c-2d8e-4c1a-9a8e-3a0d1f1c6b1e"
+
+	if _, err := store.Store(context.TODO(), "test-algorithm", requestId, models.PayloadFormat{ContentType: ContentTypeJson}, strings.NewReader(`{"input": "other run"}`)); err != nil {
+		t.Errorf("failed to store a run payload: %s", err)
+		t.FailNow()
+	}
+
+	if _, err := store.Resolve(context.TODO(), "test-algorithm", requestId); !errors.Is(err, ErrPayloadNotFound) {
+		t.Errorf("expected a payload of another run not to be resolved as an upload, but got: %v", err)
+	}
+}
+
+func TestPayloadUploadStore_Offload(t *testing.T) {
+	server, objects := newPayloadStore(t)
+	store, err := NewPayloadUploadStore(&request.S3BufferConfig{
+		BufferConfig: &request.BufferConfig{
+			PayloadStoragePath: "s3://bucket/nexus/payloads",
+			PayloadValidFor:    time.Hour,
+		},
+		AccessKeyID:     "test",
+		SecretAccessKey: "test",
+		Region:          "us-east-1",
+		Endpoint:        server.URL,
+	}, &models.PayloadUploadConfig{
+		Enabled: true,
+	})
+	if err != nil {
+		t.Errorf("failed to create a payload upload store: %s", err)
+		t.FailNow()
+	}
+
+	requestId := "5d5f0b5c-2d8e-4c1a-9a8e-3a0d1f1c6b1e"
+	object, err := store.Offload(context.TODO(), "test-algorithm", requestId, EncodingZstd, map[string]interface{}{"input": strings.Repeat("value", 100)})
+	if err != nil {
+		t.Errorf("failed to store a compressed payload: %s", err)
+		t.FailNow()
+	}
+
+	if object.ContentEncoding != EncodingZstd || object.Uri == "" || object.Size >= 500 {
+		t.Errorf("expected the payload to be stored compressed, but got: %v", object)
+	}
+
+	decoder, _ := NewDecoder(EncodingZstd, bytes.NewReader(objects["/bucket/nexus/payloads/runs/algorithm=test-algorithm/"+requestId]))
+	decoded, _ := io.ReadAll(decoder)
+	if string(decoded) != `{"input":"`+strings.Repeat("value", 100)+`"}` {
+		t.Errorf("expected stored payload to decode to algorithm parameters, but got: %s", decoded)
+	}
+}
+
+func TestTemplatePayloadEncoding(t *testing.T) {
+	template := &v1.NexusAlgorithmTemplate{ObjectMeta: metav1.ObjectMeta{Name: "test-algorithm"}}
+	if encoding, err := TemplatePayloadEncoding(template); encoding != "" || err != nil {
+		t.Errorf("expected payloads of a template without the annotation to be stored as is, but got %s (%v)", encoding, err)
+	}
+
+	template.Annotations = map[string]string{PayloadEncodingAnnotation: EncodingZstd}
+	if encoding, err := TemplatePayloadEncoding(template); encoding != EncodingZstd || err != nil {
+		t.Errorf("expected a template to opt in to compressed payloads, but got %s (%v)", encoding, err)
+	}
+
+	template.Annotations[PayloadEncodingAnnotation] = "br"
+	if _, err := TemplatePayloadEncoding(template); err == nil {
+		t.Errorf("expected an unsupported payload encoding to be rejected")
+	}
+}
+
+func TestPayloadUploadStore_Store(t *testing.T) {
+	server, objects := newPayloadStore(t)
+	store := newTestPayloadUploadStore(t, server.URL, "1Ki")
+	requestId := "5d5f0b5c-2d8e-4c1a-9a8e-3a0d1f1c6b1e"
+
+	object, err := store.Store(context.TODO(), "test-algorithm", requestId, models.PayloadFormat{ContentType: ContentTypeBinary}, bytes.NewReader([]byte{0x50, 0x41, 0x52, 0x31}))
+	if err != nil {
+		t.Errorf("failed to store a binary payload: %s", err)
+		t.FailNow()
+	}
+
+	if object.ContentType != ContentTypeBinary || object.Uri == "" || object.Size != 4 {
+		t.Errorf("unexpected stored payload: %v", object)
+	}
+
+	if !bytes.Equal(objects["/bucket/nexus/payloads/runs/algorithm=test-algorithm/"+requestId], []byte{0x50, 0x41, 0x52, 0x31}) {
+		t.Errorf("expected a binary payload to be stored unchanged")
+	}
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"fmt"
+	v1 "github.com/SneaksAndData/nexus-core/pkg/apis/science/v1"
+	coremodels "github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
+	"github.com/SneaksAndData/nexus-core/pkg/telemetry"
+	"github.com/SneaksAndData/nexus/services/models"
+	corev1 "k8s.io/api/core/v1"
+	"time"
+)
+
+// MaxQueueTimeAnnotation sets the maximum time a run of the annotated template may stay in the queue before it is submitted, as a Go duration string
+const MaxQueueTimeAnnotation = "science.sneaksanddata.com/max-queue-time"
+
+// TemplateMaxQueueTime returns the maximum queue time configured for a template, or zero if none is set
+func TemplateMaxQueueTime(template *v1.NexusAlgorithmTemplate) (time.Duration, error) {
+	value, ok := template.Annotations[MaxQueueTimeAnnotation]
+	if !ok || value == "" {
+		return 0, nil
+	}
+
+	maxQueueTime, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s annotation on template %s: %w", MaxQueueTimeAnnotation, template.Name, err)
+	}
+
+	return maxQueueTime, nil
+}
+
+// EffectiveMaxQueueTime combines template and request limits: a request can only tighten the template limit, zero means no limit
+func EffectiveMaxQueueTime(templateLimit time.Duration, requestLimit time.Duration) time.Duration {
+	if templateLimit <= 0 {
+		return requestLimit
+	}
+
+	if requestLimit <= 0 || requestLimit > templateLimit {
+		return templateLimit
+	}
+
+	return requestLimit
+}
+
+// SetQueueDeadline records the time after which a run that has not been submitted yet will be moved to DEADLINE_EXCEEDED
+func (scheduler *RequestScheduler) SetQueueDeadline(requestId string, algorithmName string, deadline time.Time) error {
+	attributes := models.NewCheckpointAttributes(algorithmName, requestId)
+	attributes.QueueDeadline = deadline
+
+	return scheduler.store.UpsertAttributes(attributes, models.AttributeQueueDeadline)
+}
+
+// markDeadlineExceeded moves a run that has spent too long in the queue to DEADLINE_EXCEEDED
+func (scheduler *RequestScheduler) markDeadlineExceeded(checkpoint *coremodels.CheckpointedRequest, deadline time.Time) error {
+	scheduler.logger.V(0).Info("run exceeded its queue deadline - skipping", "request", checkpoint.Id, "template", checkpoint.Algorithm, "deadline", deadline)
+	telemetry.Increment(scheduler.metrics, "queue_deadline_exceeded", map[string]string{"algorithm": checkpoint.Algorithm})
+	scheduler.recordTemplateEvent(checkpoint.Algorithm, corev1.EventTypeWarning, EventReasonQueueDeadlineExceeded, "Run %s was not submitted before its queue deadline %s", checkpoint.Id, deadline.UTC().Format(time.RFC3339))
+
+	expired := checkpoint.DeepCopy()
+	expired.LifecycleStage = coremodels.LifecycleStageDeadlineExceeded
+	expired.AlgorithmFailureCause = "Maximum queue time exceeded"
+	expired.AlgorithmFailureDetails = fmt.Sprintf("Run was not submitted before its queue deadline %s", deadline.UTC().Format(time.RFC3339))
+
+	return scheduler.buffer.Update(expired)
+}
+
+// reportQueueTime emits the time a run spent between being received and being submitted
+func (scheduler *RequestScheduler) reportQueueTime(checkpoint *coremodels.CheckpointedRequest) {
+	if checkpoint.ReceivedAt.IsZero() {
+		return
+	}
+
+	telemetry.Gauge(scheduler.metrics, "queue_time", float64(checkpoint.SentAt.Sub(checkpoint.ReceivedAt).Milliseconds()), map[string]string{"algorithm": checkpoint.Algorithm}, 1)
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"os"
+	"time"
+)
+
+const (
+	RecoveryLeasePrefix   = "nexus-recovery-"
+	RecoveryHostKey       = "science.sneaksanddata.com/recovered-host"
+	defaultClaimDuration  = time.Minute * 5
+	recoveryLeaseSelector = ComponentKey + "=" + ComponentName + "," + RecoveryHostKey
+)
+
+func (scheduler *RequestScheduler) recoveryClaimDuration() time.Duration {
+	if scheduler.supervisorConfig == nil || scheduler.supervisorConfig.RecoveryClaimDuration == 0 {
+		return defaultClaimDuration
+	}
+
+	return scheduler.supervisorConfig.RecoveryClaimDuration
+}
+
+func isLeaseExpired(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+
+	return lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second).Before(now)
+}
+
+// claimRecovery acquires a Lease for recovery of requests received by the provided host. Only one scheduler instance can hold the claim until it expires.
+func (scheduler *RequestScheduler) claimRecovery(host string) (bool, error) {
+	identity, err := os.Hostname()
+	if err != nil { // coverage-ignore
+		return false, err
+	}
+
+	leases := scheduler.kubeClient.CoordinationV1().Leases(scheduler.deployNamespace)
+	now := metav1.NewMicroTime(time.Now())
+	duration := int32(scheduler.recoveryClaimDuration().Seconds())
+
+	_, err = leases.Create(context.TODO(), &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      RecoveryLeasePrefix + host,
+			Namespace: scheduler.deployNamespace,
+			Labels: map[string]string{
+				ComponentKey:    ComponentName,
+				RecoveryHostKey: host,
+			},
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &identity,
+			LeaseDurationSeconds: &duration,
+			AcquireTime:          &now,
+			RenewTime:            &now,
+		},
+	}, metav1.CreateOptions{})
+
+	if err == nil {
+		return true, nil
+	}
+
+	if !errors.IsAlreadyExists(err) { // coverage-ignore
+		return false, err
+	}
+
+	existing, err := leases.Get(context.TODO(), RecoveryLeasePrefix+host, metav1.GetOptions{})
+	if err != nil { // coverage-ignore
+		return false, err
+	}
+
+	if !isLeaseExpired(existing, now.Time) {
+		return false, nil
+	}
+
+	// take over an expired claim - update fails with a conflict if another instance has taken it over first
+	existing.Spec.HolderIdentity = &identity
+	existing.Spec.LeaseDurationSeconds = &duration
+	existing.Spec.AcquireTime = &now
+	existing.Spec.RenewTime = &now
+
+	if _, err := leases.Update(context.TODO(), existing, metav1.UpdateOptions{}); err != nil {
+		if errors.IsConflict(err) { // coverage-ignore
+			return false, nil
+		}
+
+		return false, err // coverage-ignore
+	}
+
+	return true, nil
+}
+
+// cleanupRecoveryClaims removes expired recovery Leases
+func (scheduler *RequestScheduler) cleanupRecoveryClaims() error {
+	leases := scheduler.kubeClient.CoordinationV1().Leases(scheduler.deployNamespace)
+	claims, err := leases.List(context.TODO(), metav1.ListOptions{LabelSelector: recoveryLeaseSelector})
+	if err != nil { // coverage-ignore
+		return err
+	}
+
+	now := time.Now()
+	for _, claim := range claims.Items {
+		if !isLeaseExpired(&claim, now) {
+			continue
+		}
+
+		if err := leases.Delete(context.TODO(), claim.Name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) { // coverage-ignore
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	v1 "github.com/SneaksAndData/nexus-core/pkg/apis/science/v1"
+	coremodels "github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
+	"github.com/SneaksAndData/nexus-core/pkg/telemetry"
+	"github.com/SneaksAndData/nexus/services/models"
+	"github.com/SneaksAndData/nexus/storage"
+	"time"
+)
+
+// ResultCacheTtlAnnotation enables the result cache for the annotated template: a submission identical to a run completed within this period, as a Go duration string, reuses its result instead of launching a Job
+const ResultCacheTtlAnnotation = "science.sneaksanddata.com/result-cache-ttl"
+
+// TemplateResultCacheTtl returns how long results of a template can be reused, or zero if the result cache is not enabled for it
+func TemplateResultCacheTtl(template *v1.NexusAlgorithmTemplate) (time.Duration, error) {
+	value, ok := template.Annotations[ResultCacheTtlAnnotation]
+	if !ok || value == "" {
+		return 0, nil
+	}
+
+	ttl, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s annotation on template %s: %w", ResultCacheTtlAnnotation, template.Name, err)
+	}
+
+	return ttl, nil
+}
+
+// ContentHash computes a hash of algorithm parameters and the configuration a run is launched with. Pre-signed URLs of uploaded payloads are excluded, since they differ between uploads of the same content
+func ContentHash(parameters map[string]interface{}, appliedConfiguration *v1.NexusAlgorithmSpec) (string, error) {
+	hashedParameters := parameters
+	if reference, ok := parameters[PayloadReferenceParameter].(map[string]interface{}); ok {
+		hashedParameters = map[string]interface{}{}
+		for key, value := range parameters {
+			hashedParameters[key] = value
+		}
+
+		hashedReference := map[string]interface{}{}
+		for key, value := range reference {
+			if key != "uri" {
+				hashedReference[key] = value
+			}
+		}
+		hashedParameters[PayloadReferenceParameter] = hashedReference
+	}
+
+	// map keys are serialized in sorted order, so equal content produces equal hashes
+	serialized, err := json.Marshal(map[string]interface{}{
+		"parameters":    hashedParameters,
+		"configuration": appliedConfiguration,
+	})
+	if err != nil { // coverage-ignore
+		return "", err
+	}
+
+	hash := sha256.Sum256(serialized)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// LookupCachedResult returns a run with the same content hash that completed within the ttl, or nil on a cache miss.
+// A pending run found completed becomes the cached run, so later submissions do not depend on it staying the latest submitted run
+func (scheduler *RequestScheduler) LookupCachedResult(algorithmName string, contentHash string, ttl time.Duration) (*coremodels.CheckpointedRequest, error) {
+	entry, err := scheduler.store.ReadResultCacheEntry(algorithmName, contentHash)
+	if err != nil {
+		return nil, err
+	}
+
+	var cached *coremodels.CheckpointedRequest
+	if entry != nil {
+		cached = scheduler.completedRun(entry.RequestId, algorithmName, ttl)
+		if cached == nil && entry.PendingRequestId != "" && entry.PendingRequestId != entry.RequestId {
+			cached = scheduler.completedRun(entry.PendingRequestId, algorithmName, ttl)
+			if cached != nil {
+				scheduler.recordCompletedRun(algorithmName, contentHash, cached.Id)
+			}
+		}
+	}
+
+	if cached == nil {
+		telemetry.Increment(scheduler.metrics, "result_cache_miss", map[string]string{"algorithm": algorithmName})
+		return nil, nil
+	}
+
+	telemetry.Increment(scheduler.metrics, "result_cache_hit", map[string]string{"algorithm": algorithmName})
+	return cached, nil
+}
+
+// completedRun returns a run that completed with a result within the ttl, or nil if the run cannot be reused
+func (scheduler *RequestScheduler) completedRun(requestId string, algorithmName string, ttl time.Duration) *coremodels.CheckpointedRequest {
+	if requestId == "" {
+		return nil
+	}
+
+	checkpoint, err := scheduler.buffer.Get(requestId, algorithmName)
+	if err != nil && !storage.IsNotFound(err) { // coverage-ignore
+		scheduler.logger.V(0).Error(err, "unable to read a cached run, treating as a cache miss", "request", requestId, "template", algorithmName)
+	}
+
+	// finished checkpoints are no longer modified, so the last modification time is the completion time
+	if checkpoint != nil && checkpoint.LifecycleStage == coremodels.LifecycleStageCompleted && checkpoint.ResultUri != "" && time.Since(checkpoint.LastModified) <= ttl {
+		return checkpoint
+	}
+
+	return nil
+}
+
+// recordCompletedRun makes a completed run the cached run for its content hash. Cache is an optimization, so a failed write is only logged
+func (scheduler *RequestScheduler) recordCompletedRun(algorithmName string, contentHash string, requestId string) {
+	if err := scheduler.store.UpsertResultCacheEntry(&models.ResultCacheEntry{
+		Algorithm:   algorithmName,
+		ContentHash: contentHash,
+		RequestId:   requestId,
+	}, models.ResultCacheRequestId); err != nil { // coverage-ignore
+		scheduler.logger.V(0).Error(err, "unable to record a completed run in the result cache", "request", requestId, "template", algorithmName)
+	}
+}
+
+// recordCachedResult makes a run reconciled as completed the cached run for its content hash, if it was submitted as a cache miss
+func (scheduler *RequestScheduler) recordCachedResult(checkpoint *coremodels.CheckpointedRequest) {
+	attributes, err := scheduler.store.ReadAttributes(checkpoint.Algorithm, checkpoint.Id)
+	if err != nil { // coverage-ignore
+		scheduler.logger.V(0).Error(err, "unable to read result cache attributes of a completed run", "request", checkpoint.Id, "template", checkpoint.Algorithm)
+		return
+	}
+
+	if attributes != nil && attributes.ResultCache == models.ResultCacheMiss && attributes.ContentHash != "" {
+		scheduler.recordCompletedRun(checkpoint.Algorithm, attributes.ContentHash, checkpoint.Id)
+	}
+}
+
+// CompleteFromCache creates a COMPLETED run that resolves to the result of a cached run, without buffering or submitting it
+func (scheduler *RequestScheduler) CompleteFromCache(requestId string, algorithmName string, request *coremodels.AlgorithmRequest, config *v1.NexusAlgorithmSpec, cached *coremodels.CheckpointedRequest, contentHash string) error {
+	checkpoint, _, err := coremodels.FromAlgorithmRequest(requestId, algorithmName, request, config)
+	if err != nil {
+		return err
+	}
+
+	checkpoint.LifecycleStage = coremodels.LifecycleStageCompleted
+	checkpoint.ResultUri = cached.ResultUri
+	checkpoint.PayloadUri = cached.PayloadUri
+	checkpoint.PayloadValidFor = cached.PayloadValidFor
+	checkpoint.ContentHash = contentHash
+	checkpoint.SentAt = checkpoint.ReceivedAt
+
+	attributes := models.NewCheckpointAttributes(algorithmName, requestId)
+	attributes.ContentHash = contentHash
+	attributes.ResultCache = models.ResultCacheHit
+	attributes.ResultCacheSource = cached.Id
+
+	// attributes are written first, so the run never appears completed without a reference to its source
+	if err := scheduler.store.UpsertAttributes(attributes, models.AttributeContentHash, models.AttributeResultCache, models.AttributeResultCacheSource); err != nil {
+		return err
+	}
+
+	scheduler.logger.V(0).Info("completed run from the result cache", "request", requestId, "template", algorithmName, "source", cached.Id)
+
+	return scheduler.buffer.Update(checkpoint)
+}
+
+// RecordCacheMiss makes a run that is about to be buffered the pending run for its content hash. Cached run is only replaced once the pending run is found completed
+func (scheduler *RequestScheduler) RecordCacheMiss(requestId string, algorithmName string, contentHash string) error {
+	attributes := models.NewCheckpointAttributes(algorithmName, requestId)
+	attributes.ContentHash = contentHash
+	attributes.ResultCache = models.ResultCacheMiss
+
+	if err := scheduler.store.UpsertAttributes(attributes, models.AttributeContentHash, models.AttributeResultCache); err != nil {
+		return err
+	}
+
+	return scheduler.store.UpsertResultCacheEntry(&models.ResultCacheEntry{
+		Algorithm:        algorithmName,
+		ContentHash:      contentHash,
+		PendingRequestId: requestId,
+		CreatedAt:        time.Now(),
+	}, models.ResultCachePendingRequestId, models.ResultCacheCreatedAt)
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sort"
+)
+
+// ErrRunPodsNotFound is returned when a run has no pods on its shard, for example if it has not been submitted yet or its Job has been cleaned up
+var ErrRunPodsNotFound = errors.New("no pods found for the run")
+
+// StreamRunLogs writes container logs of all pods created for the run to the writer, oldest pod first
+func (scheduler *RequestScheduler) StreamRunLogs(ctx context.Context, requestId string, algorithmName string, options *corev1.PodLogOptions, writer io.Writer) error {
+	algorithmName = RunAlgorithmName(algorithmName)
+	shard, err := scheduler.findRunShard(requestId, algorithmName)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	if shard == nil {
+		return ErrRunPodsNotFound
+	}
+
+	client, ok := scheduler.shardKubeClients[shard.Name]
+	if !ok {
+		return fmt.Errorf("no Kubernetes client configured for shard %s", shard.Name)
+	}
+
+	namespace := scheduler.runNamespace(algorithmName)
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set{JobNameLabel: requestId}.String(),
+	})
+	if err != nil { // coverage-ignore
+		return err
+	}
+
+	if len(pods.Items) == 0 {
+		return ErrRunPodsNotFound
+	}
+
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].CreationTimestamp.Before(&pods.Items[j].CreationTimestamp)
+	})
+
+	for _, pod := range pods.Items {
+		for _, container := range pod.Spec.Containers {
+			if _, err := fmt.Fprintf(writer, "==> %s/%s <==\n", pod.Name, container.Name); err != nil { // coverage-ignore
+				return err
+			}
+
+			containerOptions := options.DeepCopy()
+			containerOptions.Container = container.Name
+
+			// a container might not have logs yet, or have no previous instance - this should not hide logs of other containers
+			stream, err := client.CoreV1().Pods(namespace).GetLogs(pod.Name, containerOptions).Stream(ctx)
+			if err != nil { // coverage-ignore
+				if _, err := fmt.Fprintf(writer, "logs not available: %s\n", err.Error()); err != nil {
+					return err
+				}
+				continue
+			}
+
+			_, err = io.Copy(writer, stream)
+			_ = stream.Close()
+			if err != nil { // coverage-ignore
+				return err
+			}
+
+			if _, err := fmt.Fprintln(writer); err != nil { // coverage-ignore
+				return err
+			}
+		}
+	}
+
+	return nil
+}
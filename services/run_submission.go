@@ -0,0 +1,337 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	coremodels "github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
+	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/request"
+	"github.com/SneaksAndData/nexus/services/models"
+	"github.com/google/uuid"
+	"io"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrInvalidSubmission is returned for runs that cannot be created from the provided request
+	ErrInvalidSubmission = errors.New("invalid submission")
+	// ErrSubmissionFailed is returned when a valid run cannot be created due to an internal error
+	ErrSubmissionFailed = errors.New("submission failed")
+)
+
+// SubmissionError is returned when a run cannot be created. Message can be returned to a client as is
+type SubmissionError struct {
+	Reason  error
+	Message string
+}
+
+func (e *SubmissionError) Error() string {
+	return e.Message
+}
+
+func (e *SubmissionError) Unwrap() error {
+	return e.Reason
+}
+
+// OriginalPayload is a request body that is stored in the format it was received in
+type OriginalPayload struct {
+	ContentType string
+	Body        io.Reader
+}
+
+// RunSubmission is a run requested through the REST or gRPC API
+type RunSubmission struct {
+	AlgorithmName string
+	Request       *coremodels.AlgorithmRequest
+	DryRun        bool
+	// MaxQueueTime overrides the maximum queue time of the algorithm template, if set
+	MaxQueueTime time.Duration
+	// PayloadUploadId references a payload uploaded ahead of run creation
+	PayloadUploadId string
+	// Original is set for requests received in a format other than JSON
+	Original *OriginalPayload
+	// TemplateRevision pins the run to a recorded revision of the algorithm template. Current revision is used if 0
+	TemplateRevision int64
+	// RoutingKey routes runs with the same key to the same variant of an algorithm with a traffic split
+	RoutingKey string
+}
+
+// RunSubmitter validates run submissions and adds them to the checkpoint buffer
+type RunSubmitter struct {
+	buffer      request.Buffer
+	configCache *NexusResourceCache
+	scheduler   *RequestScheduler
+	uploads     *PayloadUploadStore
+	recorder    record.EventRecorder
+	history     *TemplateHistory
+	logger      klog.Logger
+}
+
+// NewRunSubmitter creates a RunSubmitter. Uploads can be nil if payload uploads are disabled
+func NewRunSubmitter(buffer request.Buffer, configCache *NexusResourceCache, scheduler *RequestScheduler, uploads *PayloadUploadStore, recorder record.EventRecorder, logger klog.Logger) *RunSubmitter {
+	return &RunSubmitter{
+		buffer:      buffer,
+		configCache: configCache,
+		scheduler:   scheduler,
+		uploads:     uploads,
+		recorder:    recorder,
+		logger:      logger,
+	}
+}
+
+func invalidSubmission(format string, args ...any) error {
+	return &SubmissionError{Reason: ErrInvalidSubmission, Message: fmt.Sprintf(format, args...)}
+}
+
+// failedSubmission logs an internal error and hides its details from the client
+func (submitter *RunSubmitter) failedSubmission(err error, message string, algorithmName string, requestId string) error {
+	submitter.logger.V(0).Error(err, message, "algorithm", algorithmName, "request", requestId)
+	return &SubmissionError{Reason: ErrSubmissionFailed, Message: "Internal error occurred when processing your request."}
+}
+
+// Submit creates a run and returns its request identifier. Errors returned are SubmissionError
+func (submitter *RunSubmitter) Submit(ctx context.Context, submission *RunSubmission) (string, error) {
+	var parentRef *metav1.OwnerReference
+	var err error
+
+	algorithmName := submission.AlgorithmName
+	payload := submission.Request
+	original := submission.Original
+	dryRun := submission.DryRun
+	requestId := uuid.New().String()
+
+	if original != nil && submitter.uploads == nil {
+		return "", &SubmissionError{Reason: ErrUnsupportedContentType, Message: fmt.Sprintf(`Payloads in %s format require payload uploads to be enabled. Use %s instead`, original.ContentType, ContentTypeJson)}
+	}
+
+	config, err := submitter.configCache.GetAlgorithmConfiguration(algorithmName)
+	if err != nil {
+		return "", submitter.failedSubmission(err, "error when retrieving algorithm template", algorithmName, requestId)
+	}
+
+	if config == nil {
+		return "", invalidSubmission(`No valid configuration found for: %s. Please check that algorithm name is spelled correctly and try again. Contact an algorithm author if this problem persists.`, algorithmName)
+	}
+
+	// runs submitted via an alias are stored under the template name, runs of tenant templates under a tenant-qualified name
+	algorithmName = submitter.configCache.RunName(config)
+
+	if err := validateTag(submitter.scheduler.tenancy, payload.Tag); err != nil {
+		return "", invalidSubmission(`Tag %s is invalid: %s`, payload.Tag, err.Error())
+	}
+
+	release, err := submitter.checkTenantQuota(config, requestId)
+	if err != nil {
+		return "", err
+	}
+
+	// a run only counts towards the tenant quota once it is buffered, so rejected submissions, dry runs and cached results release it
+	buffered := false
+	defer func() {
+		if !buffered {
+			release()
+		}
+	}()
+
+	// a run pinned to a revision bypasses the traffic split
+	var variant string
+	if submission.TemplateRevision == 0 {
+		config, variant, err = submitter.selectVariant(config, submission.RoutingKey, requestId)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	config, err = submitter.resolveRevision(config, submission.TemplateRevision, requestId)
+	if err != nil {
+		return "", err
+	}
+
+	workgroup, err := submitter.configCache.GetTemplateWorkgroup(config)
+	if err != nil {
+		return "", submitter.failedSubmission(err, "error when retrieving algorithm workgroup configuration", algorithmName, requestId)
+	}
+
+	if workgroup == nil {
+		submitter.recorder.Eventf(config, corev1.EventTypeWarning, EventReasonMissingWorkgroup, "Rejected request %s: workgroup %s not found", requestId, config.Spec.WorkgroupRef.Name)
+		return "", invalidSubmission(`Cannot assign requested workgroup %s to the algorithm %s. Please check the deployed configuration.`, config.Spec.WorkgroupRef.Name, algorithmName)
+	}
+
+	if payload.ParentRequest != nil {
+		// parent is recorded under the name its run is stored under, so the run is found when the parent is cancelled
+		parentName, err := submitter.scheduler.ParentRunName(payload.ParentRequest)
+		if err != nil {
+			return "", submitter.failedSubmission(err, "error when retrieving a parent request", algorithmName, requestId)
+		}
+		payload.ParentRequest = &coremodels.AlgorithmRequestRef{RequestId: payload.ParentRequest.RequestId, AlgorithmName: parentName}
+
+		if !dryRun {
+			parentRef, err = submitter.scheduler.ResolveParent(payload.ParentRequest, workgroup.Spec.Cluster, algorithmName)
+			if err != nil {
+				return "", submitter.failedSubmission(err, "error when retrieving a parent request", algorithmName, requestId)
+			}
+		} else {
+			// for dry runs parent job might not exist at all, thus we create a fake reference
+			parentRef = &metav1.OwnerReference{
+				APIVersion: "batch/v1",
+				Kind:       "Job",
+				Name:       payload.ParentRequest.RequestId,
+				UID:        types.UID(payload.ParentRequest.RequestId),
+			}
+		}
+	}
+
+	var uploadedPayload *models.PayloadObject
+	uploadId := submission.PayloadUploadId
+	if _, exists := payload.AlgorithmParameters[PayloadReferenceParameter]; exists && (uploadId != "" || original != nil) {
+		return "", invalidSubmission(`Algorithm parameter %s is reserved for uploaded payloads`, PayloadReferenceParameter)
+	}
+
+	payloadEncoding, err := TemplatePayloadEncoding(config)
+	if err != nil {
+		return "", submitter.failedSubmission(err, "error when reading payload encoding", algorithmName, requestId)
+	}
+
+	switch {
+	case uploadId != "":
+		if submitter.uploads == nil {
+			return "", invalidSubmission(`Payload uploads are not enabled`)
+		}
+
+		if original != nil && original.ContentType != ContentTypeBinary {
+			return "", invalidSubmission(`Uploaded payloads can only be combined with %s or %s requests`, ContentTypeJson, ContentTypeBinary)
+		}
+
+		uploadedPayload, err = submitter.uploads.Resolve(ctx, algorithmName, uploadId)
+		switch {
+		case errors.Is(err, ErrPayloadNotFound), errors.Is(err, ErrInvalidUploadId), errors.Is(err, ErrPayloadTooLarge):
+			return "", invalidSubmission(`Uploaded payload %s cannot be used: %s`, uploadId, err.Error())
+		case err != nil:
+			return "", submitter.failedSubmission(err, "error when resolving uploaded payload", algorithmName, requestId)
+		}
+
+		if payload.AlgorithmParameters == nil {
+			payload.AlgorithmParameters = map[string]interface{}{}
+		}
+		payload.AlgorithmParameters[PayloadReferenceParameter] = PayloadReference(uploadedPayload)
+	case original != nil && !dryRun:
+		// payload is stored in the format it was received in, so the algorithm can read it unchanged
+		uploadedPayload, err = submitter.uploads.Store(ctx, algorithmName, requestId, models.PayloadFormat{ContentType: original.ContentType}, original.Body)
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxBytesErr), errors.Is(err, ErrPayloadTooLarge):
+			return "", &SubmissionError{Reason: ErrPayloadTooLarge, Message: `Algorithm payload exceeds the maximum size`}
+		case err != nil:
+			return "", submitter.failedSubmission(err, fmt.Sprintf("error when storing %s payload", original.ContentType), algorithmName, requestId)
+		}
+
+		// binary payloads carry parameters in headers, other formats carry them in the stored payload
+		if original.ContentType != ContentTypeBinary || payload.AlgorithmParameters == nil {
+			payload.AlgorithmParameters = map[string]interface{}{}
+		}
+		payload.AlgorithmParameters[PayloadReferenceParameter] = PayloadReference(uploadedPayload)
+	case payloadEncoding != "" && submitter.uploads != nil && !dryRun:
+		// template opted in to payloads stored compressed by the scheduler, algorithm receives only a reference to it
+		uploadedPayload, err = submitter.uploads.Offload(ctx, algorithmName, requestId, payloadEncoding, payload.AlgorithmParameters)
+		if err != nil {
+			return "", submitter.failedSubmission(err, "error when storing compressed payload", algorithmName, requestId)
+		}
+
+		payload.AlgorithmParameters = map[string]interface{}{
+			PayloadReferenceParameter: PayloadReference(uploadedPayload),
+		}
+	}
+
+	resultCacheTtl, err := TemplateResultCacheTtl(config)
+	if err != nil {
+		return "", submitter.failedSubmission(err, "error when reading result cache settings", algorithmName, requestId)
+	}
+
+	var contentHash string
+	if resultCacheTtl > 0 && !dryRun {
+		contentHash, err = ContentHash(payload.AlgorithmParameters, config.Spec.Merge(payload.CustomConfiguration))
+		if err != nil { // coverage-ignore
+			return "", submitter.failedSubmission(err, "error when computing content hash", algorithmName, requestId)
+		}
+
+		cached, err := submitter.scheduler.LookupCachedResult(algorithmName, contentHash, resultCacheTtl)
+		if err != nil {
+			// cache is an optimization, a failed lookup must not fail the submission
+			submitter.logger.V(0).Error(err, "error when looking up a cached result", "algorithm", algorithmName, "request", requestId)
+		}
+
+		if cached != nil {
+			if err := submitter.scheduler.CompleteFromCache(requestId, algorithmName, payload, &config.Spec, cached, contentHash); err != nil {
+				return "", submitter.failedSubmission(err, "error when completing a run from the result cache", algorithmName, requestId)
+			}
+
+			return requestId, nil
+		}
+	}
+
+	templateMaxQueueTime, err := TemplateMaxQueueTime(config)
+	if err != nil {
+		return "", submitter.failedSubmission(err, "error when reading maximum queue time", algorithmName, requestId)
+	}
+
+	// deadline must be recorded before the request is buffered, so the scheduler sees it when dequeuing
+	if maxQueueTime := EffectiveMaxQueueTime(templateMaxQueueTime, submission.MaxQueueTime); maxQueueTime > 0 && !dryRun {
+		if err := submitter.scheduler.SetQueueDeadline(requestId, algorithmName, time.Now().Add(maxQueueTime)); err != nil {
+			return "", submitter.failedSubmission(err, "error when recording queue deadline", algorithmName, requestId)
+		}
+	}
+
+	if uploadedPayload != nil && !dryRun {
+		if err := submitter.scheduler.SetPayloadReference(requestId, algorithmName, uploadedPayload); err != nil {
+			return "", submitter.failedSubmission(err, "error when recording uploaded payload", algorithmName, requestId)
+		}
+	}
+
+	if variant != "" && !dryRun {
+		if err := submitter.scheduler.SetTemplateVariant(requestId, algorithmName, variant); err != nil {
+			return "", submitter.failedSubmission(err, "error when recording template variant", algorithmName, requestId)
+		}
+	}
+
+	if contentHash != "" {
+		if err := submitter.scheduler.RecordCacheMiss(requestId, algorithmName, contentHash); err != nil {
+			return "", submitter.failedSubmission(err, "error when recording content hash", algorithmName, requestId)
+		}
+	}
+
+	// tags are scoped to the tenant, so tag queries and cancellations of one tenant do not match runs of another tenant
+	payload.Tag = TenantTag(submitter.configCache.TemplateTenant(config), payload.Tag)
+
+	if err := submitter.buffer.Add(requestId, algorithmName, payload, &config.Spec, &workgroup.Spec, parentRef, dryRun); err != nil {
+		submitter.logger.V(0).Error(err, "error when buffering a request", "algorithm", algorithmName, "request", requestId)
+		return "", invalidSubmission(`Request buffering failed for: %s/%s`, algorithmName, requestId)
+	}
+
+	buffered = !dryRun
+	return requestId, nil
+}
+
+// PayloadReference is the algorithm parameter value that points to an uploaded payload
+func PayloadReference(object *models.PayloadObject) map[string]interface{} {
+	reference := map[string]interface{}{
+		"uri":         object.Uri,
+		"contentHash": object.ContentHash,
+		"size":        object.Size,
+	}
+
+	if object.ContentType != "" {
+		reference["contentType"] = object.ContentType
+	}
+
+	if object.ContentEncoding != "" {
+		reference["contentEncoding"] = object.ContentEncoding
+	}
+
+	return reference
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	v1 "github.com/SneaksAndData/nexus-core/pkg/apis/science/v1"
+	coremodels "github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
+	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/request"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"testing"
+	"time"
+)
+
+func newSubmitterFixture(t *testing.T, withWorkgroup bool) (*schedulerFixture, *RunSubmitter) {
+	f := newSchedulerFixture(t, []runtime.Object{}, []runtime.Object{})
+	if withWorkgroup {
+		_ = f.scheduler.configCache.workgroupInformer.GetIndexer().Add(&v1.NexusAlgorithmWorkgroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "default",
+				Namespace: "nexus",
+			},
+			Spec: *newFakeWorkgroupSpec(),
+		})
+	}
+
+	return f, NewRunSubmitter(f.buffer, f.scheduler.configCache, f.scheduler, nil, f.recorder, klog.FromContext(f.ctx))
+}
+
+func TestRunSubmitter_Submit(t *testing.T) {
+	f, submitter := newSubmitterFixture(t, true)
+	scheduler, err := f.scheduler.Init(f.ctx)
+	if err != nil {
+		t.Errorf("scheduler init failed: %s", err)
+		t.FailNow()
+	}
+
+	scheduled := newScheduledRuns(scheduler)
+	go f.buffer.Start(scheduled)
+
+	time.Sleep(1 * time.Second)
+
+	requestId, err := submitter.Submit(context.TODO(), &RunSubmission{
+		AlgorithmName: "test-algorithm",
+		Request:       newFakeRequest(),
+		MaxQueueTime:  time.Hour,
+	})
+	if err != nil {
+		t.Errorf("failed to submit a run: %s", err)
+		t.FailNow()
+	}
+
+	scheduled.wait(t)
+	checkpoint, _ := f.buffer.Get(requestId, "test-algorithm")
+	if checkpoint == nil || checkpoint.LifecycleStage != coremodels.LifecycleStageRunning {
+		t.Errorf("expected a submitted run to be running, but got %v", checkpoint)
+	}
+
+	attributes, _ := f.store.ReadAttributes("test-algorithm", requestId)
+	if attributes == nil || attributes.QueueDeadline.IsZero() {
+		t.Errorf("expected a queue deadline to be recorded for a submitted run, but got %v", attributes)
+	}
+}
+
+func TestRunSubmitter_Rejected(t *testing.T) {
+	tests := []struct {
+		name          string
+		withWorkgroup bool
+		submission    *RunSubmission
+		reason        error
+	}{
+		{
+			name:          "missing template",
+			withWorkgroup: true,
+			submission:    &RunSubmission{AlgorithmName: "missing-algorithm", Request: newFakeRequest()},
+			reason:        ErrInvalidSubmission,
+		},
+		{
+			name:          "missing workgroup",
+			withWorkgroup: false,
+			submission:    &RunSubmission{AlgorithmName: "test-algorithm", Request: newFakeRequest()},
+			reason:        ErrInvalidSubmission,
+		},
+		{
+			name:          "uploads disabled",
+			withWorkgroup: true,
+			submission:    &RunSubmission{AlgorithmName: "test-algorithm", Request: newFakeRequest(), PayloadUploadId: "upload"},
+			reason:        ErrInvalidSubmission,
+		},
+		{
+			name:          "original payload without uploads",
+			withWorkgroup: true,
+			submission:    &RunSubmission{AlgorithmName: "test-algorithm", Request: newFakeRequest(), Original: &OriginalPayload{ContentType: ContentTypeMsgpack, Body: bytes.NewReader([]byte{})}},
+			reason:        ErrUnsupportedContentType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, submitter := newSubmitterFixture(t, tt.withWorkgroup)
+
+			_, err := submitter.Submit(context.TODO(), tt.submission)
+			var submissionErr *SubmissionError
+			if !errors.As(err, &submissionErr) || !errors.Is(err, tt.reason) {
+				t.Errorf("expected a submission error caused by %v, but got %v", tt.reason, err)
+			}
+		})
+	}
+}
+
+func TestRunSubmitter_MissingWorkgroupEvent(t *testing.T) {
+	f, submitter := newSubmitterFixture(t, false)
+
+	if _, err := submitter.Submit(context.TODO(), &RunSubmission{AlgorithmName: "test-algorithm", Request: newFakeRequest()}); err == nil {
+		t.Errorf("expected a run without a workgroup to be rejected")
+	}
+
+	f.expectEvents(t, EventReasonMissingWorkgroup)
+}
+
+func TestRunSubmitter_CancelChildOfAliasedParent(t *testing.T) {
+	f := newSchedulerFixture(t, []runtime.Object{}, []runtime.Object{
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "test-parent", Namespace: "nexus", UID: "test-parent-uid"}},
+	})
+	_ = f.scheduler.configCache.workgroupInformer.GetIndexer().Add(&v1.NexusAlgorithmWorkgroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nexus"},
+		Spec:       *newFakeWorkgroupSpec(),
+	})
+	template, _ := f.scheduler.configCache.GetAlgorithmConfiguration("test-algorithm")
+	aliased := template.DeepCopy()
+	aliased.Annotations = map[string]string{AliasesAnnotation: `[{"name": "old-algorithm"}]`}
+	_ = f.scheduler.configCache.templateInformer.GetIndexer().Update(aliased)
+
+	parent, _, _ := coremodels.FromAlgorithmRequest("test-parent", "test-algorithm", newFakeRequest(), newFakeSpec())
+	parent.LifecycleStage = coremodels.LifecycleStageRunning
+	memory := f.buffer.(*request.MemoryPassthroughBuffer)
+	memory.Checkpoints = append(memory.Checkpoints, parent)
+
+	submitter := NewRunSubmitter(f.buffer, f.scheduler.configCache, f.scheduler, nil, f.recorder, klog.FromContext(f.ctx))
+	if _, err := f.scheduler.Init(f.ctx); err != nil {
+		t.Errorf("scheduler init failed: %s", err)
+		t.FailNow()
+	}
+
+	buffered := make(bufferedRuns, 1)
+	go f.buffer.Start(buffered)
+	time.Sleep(1 * time.Second)
+
+	// parent is addressed by an alias of its template, while its run is stored under the template name
+	payload := newFakeRequest()
+	payload.ParentRequest = &coremodels.AlgorithmRequestRef{RequestId: "test-parent", AlgorithmName: "old-algorithm"}
+	childId, err := submitter.Submit(context.TODO(), &RunSubmission{AlgorithmName: "test-algorithm", Request: payload})
+	if err != nil {
+		t.Errorf("failed to submit a child run: %v", err)
+		t.FailNow()
+	}
+
+	if output := buffered.wait(t); output.Checkpoint.Parent == nil || output.Checkpoint.Parent.AlgorithmName != "test-algorithm" {
+		t.Errorf("expected a parent to be recorded under its run name, but got %v", output.Checkpoint.Parent)
+	}
+
+	if exists, err := f.scheduler.CancelRun("test-parent", "test-algorithm", "tester", "test", metav1.DeletePropagationBackground); !exists || err != nil {
+		t.Errorf("failed to cancel a parent run: %v", err)
+		t.FailNow()
+	}
+
+	if child, _ := f.buffer.Get(childId, "test-algorithm"); child == nil || child.LifecycleStage != coremodels.LifecycleStageCancelled {
+		t.Errorf("expected a child of a parent submitted via an alias to be cancelled with it, but got %v", child)
+	}
+}
+
+func TestRunSubmitter_PayloadEncodingOptIn(t *testing.T) {
+	f := newSchedulerFixture(t, []runtime.Object{}, []runtime.Object{})
+	_ = f.scheduler.configCache.workgroupInformer.GetIndexer().Add(&v1.NexusAlgorithmWorkgroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nexus"},
+		Spec:       *newFakeWorkgroupSpec(),
+	})
+	server, _ := newPayloadStore(t)
+	submitter := NewRunSubmitter(f.buffer, f.scheduler.configCache, f.scheduler, newTestPayloadUploadStore(t, server.URL, "1Ki"), f.recorder, klog.FromContext(f.ctx))
+	if _, err := f.scheduler.Init(f.ctx); err != nil {
+		t.Errorf("scheduler init failed: %s", err)
+		t.FailNow()
+	}
+
+	buffered := make(bufferedRuns, 1)
+	go f.buffer.Start(buffered)
+	time.Sleep(1 * time.Second)
+
+	// algorithms of templates that did not opt in receive their parameters as before
+	plainId, err := submitter.Submit(context.TODO(), &RunSubmission{AlgorithmName: "test-algorithm", Request: newFakeRequest()})
+	if err != nil {
+		t.Errorf("failed to submit a run: %v", err)
+		t.FailNow()
+	}
+
+	buffered.wait(t)
+	if attributes, _ := f.store.ReadAttributes("test-algorithm", plainId); attributes != nil && attributes.PayloadReference != "" {
+		t.Errorf("expected parameters of a template without the payload encoding annotation to be passed as is, but got %v", attributes)
+	}
+
+	template, _ := f.scheduler.configCache.GetAlgorithmConfiguration("test-algorithm")
+	encoded := template.DeepCopy()
+	encoded.Annotations = map[string]string{PayloadEncodingAnnotation: EncodingZstd}
+	_ = f.scheduler.configCache.templateInformer.GetIndexer().Update(encoded)
+
+	encodedId, err := submitter.Submit(context.TODO(), &RunSubmission{AlgorithmName: "test-algorithm", Request: newFakeRequest()})
+	if err != nil {
+		t.Errorf("failed to submit a run: %v", err)
+		t.FailNow()
+	}
+
+	buffered.wait(t)
+	if attributes, _ := f.store.ReadAttributes("test-algorithm", encodedId); attributes == nil || attributes.PayloadReference == "" || attributes.PayloadContentEncoding != EncodingZstd {
+		t.Errorf("expected parameters of a template with the payload encoding annotation to be passed by reference, but got %v", attributes)
+	}
+}
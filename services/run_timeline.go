@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	coremodels "github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
+	"github.com/SneaksAndData/nexus/services/models"
+	"github.com/SneaksAndData/nexus/storage"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"sort"
+	"time"
+)
+
+// GetRunTimeline returns checkpoint lifecycle transitions of a run merged with Kubernetes events for its Job and pods, ordered by time. Returns nil if the run does not exist
+func (scheduler *RequestScheduler) GetRunTimeline(ctx context.Context, requestId string, algorithmName string) ([]*models.RunTimelineEvent, error) {
+	algorithmName = RunAlgorithmName(algorithmName)
+	checkpoint, err := scheduler.buffer.Get(requestId, algorithmName)
+	if err != nil && !storage.IsNotFound(err) { // coverage-ignore
+		return nil, err
+	}
+
+	if checkpoint == nil {
+		return nil, nil
+	}
+
+	shardEvents, err := scheduler.getShardEvents(ctx, requestId, algorithmName)
+	if err != nil { // coverage-ignore
+		return nil, err
+	}
+
+	received, transitions, untimed := checkpointTransitions(checkpoint)
+	timeline := append(transitions, shardEvents...)
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Time.Before(*timeline[j].Time)
+	})
+
+	// transitions without a recorded time directly follow the receipt of the run
+	return append(append([]*models.RunTimelineEvent{received}, untimed...), timeline...), nil
+}
+
+// checkpointTransitions derives lifecycle transitions from checkpoint timestamps: receipt of the run, later transitions with a recorded time, and transitions without one.
+// The buffer does not record when a request was persisted, so BUFFERED has no time
+func checkpointTransitions(checkpoint *coremodels.CheckpointedRequest) (*models.RunTimelineEvent, []*models.RunTimelineEvent, []*models.RunTimelineEvent) {
+	received := &models.RunTimelineEvent{
+		Time:    &checkpoint.ReceivedAt,
+		Source:  models.TimelineSourceCheckpoint,
+		Reason:  coremodels.LifecycleStageNew,
+		Message: fmt.Sprintf("Received by %s", checkpoint.ReceivedByHost),
+	}
+
+	untimed := []*models.RunTimelineEvent{}
+	if checkpoint.LifecycleStage != coremodels.LifecycleStageNew {
+		untimed = append(untimed, &models.RunTimelineEvent{
+			Source: models.TimelineSourceCheckpoint,
+			Reason: coremodels.LifecycleStageBuffered,
+		})
+	}
+
+	transitions := []*models.RunTimelineEvent{}
+	if !checkpoint.SentAt.IsZero() {
+		transitions = append(transitions, &models.RunTimelineEvent{
+			Time:   &checkpoint.SentAt,
+			Source: models.TimelineSourceCheckpoint,
+			Reason: coremodels.LifecycleStageRunning,
+		})
+	}
+
+	if checkpoint.IsFinished() {
+		message := checkpoint.AlgorithmFailureCause
+		if checkpoint.AlgorithmFailureDetails != "" {
+			message = fmt.Sprintf("%s: %s", message, checkpoint.AlgorithmFailureDetails)
+		}
+
+		transitions = append(transitions, &models.RunTimelineEvent{
+			Time:    &checkpoint.LastModified,
+			Source:  models.TimelineSourceCheckpoint,
+			Reason:  checkpoint.LifecycleStage,
+			Message: message,
+		})
+	}
+
+	return received, transitions, untimed
+}
+
+// getShardEvents reads Kubernetes events for the run Job and its pods from the shard the run was submitted to
+func (scheduler *RequestScheduler) getShardEvents(ctx context.Context, requestId string, algorithmName string) ([]*models.RunTimelineEvent, error) {
+	shard, err := scheduler.findRunShard(requestId, algorithmName)
+	if err != nil && !errors.IsNotFound(err) { // coverage-ignore
+		return nil, err
+	}
+
+	if shard == nil {
+		return []*models.RunTimelineEvent{}, nil
+	}
+
+	client, ok := scheduler.shardKubeClients[shard.Name]
+	if !ok { // coverage-ignore
+		scheduler.logger.V(1).Info("no Kubernetes client configured for shard - timeline will not include shard events", "shard", shard.Name)
+		return []*models.RunTimelineEvent{}, nil
+	}
+
+	objects := []corev1.ObjectReference{{Kind: "Job", Name: requestId}}
+	namespace := scheduler.runNamespace(algorithmName)
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set{JobNameLabel: requestId}.String(),
+	})
+	if err != nil { // coverage-ignore
+		return nil, err
+	}
+
+	for _, pod := range pods.Items {
+		objects = append(objects, corev1.ObjectReference{Kind: "Pod", Name: pod.Name})
+	}
+
+	result := []*models.RunTimelineEvent{}
+	for _, object := range objects {
+		events, err := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+			FieldSelector: fields.Set{"involvedObject.kind": object.Kind, "involvedObject.name": object.Name}.String(),
+		})
+		if err != nil { // coverage-ignore
+			return nil, err
+		}
+
+		for _, event := range events.Items {
+			// field selectors are not supported by all clients, so the involved object is checked again
+			if event.InvolvedObject.Kind != object.Kind || event.InvolvedObject.Name != object.Name {
+				continue
+			}
+
+			result = append(result, &models.RunTimelineEvent{
+				Time:    eventTime(&event),
+				Source:  models.TimelineSourceKubernetes,
+				Object:  fmt.Sprintf("%s/%s", object.Kind, object.Name),
+				Type:    event.Type,
+				Reason:  event.Reason,
+				Message: event.Message,
+				Count:   event.Count,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// eventTime returns the most recent occurrence of an event
+func eventTime(event *corev1.Event) *time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return &event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return &event.EventTime.Time
+	default:
+		return &event.CreationTimestamp.Time
+	}
+}
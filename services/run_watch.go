@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"errors"
+	coremodels "github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
+	"time"
+)
+
+// ErrRunNotFound is returned when watching a run that does not exist
+var ErrRunNotFound = errors.New("run not found")
+
+// WatchRun polls a run checkpoint every interval and calls onChange each time the checkpoint is modified, until the run finishes or ctx is cancelled
+func (scheduler *RequestScheduler) WatchRun(ctx context.Context, requestId string, algorithmName string, interval time.Duration, onChange func(*coremodels.CheckpointedRequest) error) error {
+	algorithmName = RunAlgorithmName(algorithmName)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastStage string
+	var lastModified time.Time
+	for {
+		checkpoint, err := scheduler.buffer.Get(requestId, algorithmName)
+		if err != nil {
+			return err
+		}
+
+		if checkpoint == nil {
+			return ErrRunNotFound
+		}
+
+		if checkpoint.LifecycleStage != lastStage || !checkpoint.LastModified.Equal(lastModified) {
+			lastStage = checkpoint.LifecycleStage
+			lastModified = checkpoint.LastModified
+			if err := onChange(checkpoint); err != nil {
+				return err
+			}
+		}
+
+		if checkpoint.IsFinished() {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -3,23 +3,29 @@ package services
 import (
 	"context"
 	"fmt"
+	"github.com/DataDog/datadog-go/v5/statsd"
 	"github.com/SneaksAndData/nexus-core/pkg/buildmeta"
 	coremodels "github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
 	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/request"
 	"github.com/SneaksAndData/nexus-core/pkg/pipeline"
 	"github.com/SneaksAndData/nexus-core/pkg/resolvers"
 	"github.com/SneaksAndData/nexus-core/pkg/shards"
+	"github.com/SneaksAndData/nexus-core/pkg/telemetry"
 	"github.com/SneaksAndData/nexus-core/pkg/util"
 	"github.com/SneaksAndData/nexus/services/models"
+	"github.com/SneaksAndData/nexus/storage"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"os"
+	"sync"
 	"time"
 )
 
@@ -35,38 +41,59 @@ type LateSubmission struct {
 }
 
 type RequestScheduler struct {
-	logger              klog.Logger
-	workerConfig        *models.PipelineWorkerConfig
-	factory             kubeinformers.SharedInformerFactory
-	podInformer         cache.SharedIndexInformer
-	eventInformer       cache.SharedIndexInformer
-	LateSubmissionActor *pipeline.DefaultPipelineStageActor[*LateSubmission, *coremodels.CheckpointedRequest]
-	SchedulerActor      *pipeline.DefaultPipelineStageActor[*request.BufferOutput, *coremodels.CheckpointedRequest]
-	CommitActor         *pipeline.DefaultPipelineStageActor[*coremodels.CheckpointedRequest, string]
-	shardClients        []*shards.ShardClient
-	jobNamespace        string
-	buffer              request.Buffer
+	logger               klog.Logger
+	workerConfig         *models.PipelineWorkerConfig
+	retryConfig          *models.SchedulingRetryConfig
+	supervisorConfig     *models.SupervisorConfig
+	reconciliationConfig *models.JobReconciliationConfig
+	kubeClient           kubernetes.Interface
+	factory              kubeinformers.SharedInformerFactory
+	podInformer          cache.SharedIndexInformer
+	eventInformer        cache.SharedIndexInformer
+	LateSubmissionActor  *pipeline.DefaultPipelineStageActor[*LateSubmission, *models.SubmittedRun]
+	SchedulerActor       *pipeline.DefaultPipelineStageActor[*request.BufferOutput, *models.SubmittedRun]
+	CommitActor          *pipeline.DefaultPipelineStageActor[*models.SubmittedRun, string]
+	CancellationActor    *pipeline.DefaultPipelineStageActor[*models.CancellationOperation, string]
+	ReconciliationActor  *pipeline.DefaultPipelineStageActor[*models.ShardJob, string]
+	shardClients         []*shards.ShardClient
+	shardKubeClients     map[string]kubernetes.Interface
+	jobNamespace         string
+	deployNamespace      string
+	buffer               request.Buffer
+	store                storage.Store
+	recovering           sync.Map
+	jobInformerFactories []kubeinformers.SharedInformerFactory
+	metrics              *statsd.Client
+	recorder             record.EventRecorder
+	configCache          *NexusResourceCache
+	tenancy              *models.TenancyConfig
+	activeRuns           *activeRunCounter
 }
 
-func NewRequestScheduler(workerConfig *models.PipelineWorkerConfig, kubeClient kubernetes.Interface, shardClients []*shards.ShardClient, buffer request.Buffer, resourceNamespace string, deployNamespace string, logger klog.Logger, resyncPeriod *time.Duration) *RequestScheduler {
+func NewRequestScheduler(workerConfig *models.PipelineWorkerConfig, retryConfig *models.SchedulingRetryConfig, kubeClient kubernetes.Interface, shardClients []*shards.ShardClient, buffer request.Buffer, store storage.Store, resourceNamespace string, deployNamespace string, logger klog.Logger, resyncPeriod *time.Duration) *RequestScheduler {
 	defaultResyncPeriod := time.Second * 30
 	factory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, *util.CoalescePointer(resyncPeriod, &defaultResyncPeriod), kubeinformers.WithNamespace(deployNamespace))
 
 	return &RequestScheduler{
-		SchedulerActor: nil,
-		workerConfig:   workerConfig,
-		shardClients:   shardClients,
-		factory:        factory,
-		podInformer:    factory.Core().V1().Pods().Informer(),
-		eventInformer:  factory.Core().V1().Events().Informer(),
-		jobNamespace:   resourceNamespace,
-		buffer:         buffer,
-		logger:         logger,
+		SchedulerActor:  nil,
+		workerConfig:    workerConfig,
+		retryConfig:     retryConfig,
+		shardClients:    shardClients,
+		factory:         factory,
+		podInformer:     factory.Core().V1().Pods().Informer(),
+		eventInformer:   factory.Core().V1().Events().Informer(),
+		jobNamespace:    resourceNamespace,
+		deployNamespace: deployNamespace,
+		kubeClient:      kubeClient,
+		buffer:          buffer,
+		store:           store,
+		logger:          logger,
 	}
 }
 
-func (scheduler *RequestScheduler) Init(_ context.Context) (*RequestScheduler, error) {
+func (scheduler *RequestScheduler) Init(ctx context.Context) (*RequestScheduler, error) {
 	scheduler.logger.Info("initializing Nexus scheduler")
+	scheduler.metrics = telemetry.GetClient(ctx)
 	_, eventErr := scheduler.eventInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: scheduler.OnEvent,
 	})
@@ -77,7 +104,7 @@ func (scheduler *RequestScheduler) Init(_ context.Context) (*RequestScheduler, e
 
 	scheduler.logger.Info("pod and event informers synced")
 
-	scheduler.CommitActor = pipeline.NewDefaultPipelineStageActor[*coremodels.CheckpointedRequest, string](
+	scheduler.CommitActor = pipeline.NewDefaultPipelineStageActor[*models.SubmittedRun, string](
 		"commit",
 		map[string]string{},
 		scheduler.workerConfig.FailureRateBaseDelay,
@@ -89,7 +116,7 @@ func (scheduler *RequestScheduler) Init(_ context.Context) (*RequestScheduler, e
 		nil,
 	)
 
-	scheduler.SchedulerActor = pipeline.NewDefaultPipelineStageActor[*request.BufferOutput, *coremodels.CheckpointedRequest](
+	scheduler.SchedulerActor = pipeline.NewDefaultPipelineStageActor[*request.BufferOutput, *models.SubmittedRun](
 		"scheduler",
 		map[string]string{},
 		scheduler.workerConfig.FailureRateBaseDelay,
@@ -101,7 +128,7 @@ func (scheduler *RequestScheduler) Init(_ context.Context) (*RequestScheduler, e
 		scheduler.CommitActor,
 	)
 
-	scheduler.LateSubmissionActor = pipeline.NewDefaultPipelineStageActor[*LateSubmission, *coremodels.CheckpointedRequest](
+	scheduler.LateSubmissionActor = pipeline.NewDefaultPipelineStageActor[*LateSubmission, *models.SubmittedRun](
 		"late_submission",
 		map[string]string{},
 		scheduler.workerConfig.FailureRateBaseDelay,
@@ -113,6 +140,24 @@ func (scheduler *RequestScheduler) Init(_ context.Context) (*RequestScheduler, e
 		scheduler.CommitActor,
 	)
 
+	scheduler.CancellationActor = pipeline.NewDefaultPipelineStageActor[*models.CancellationOperation, string](
+		"cancellation",
+		map[string]string{},
+		scheduler.workerConfig.FailureRateBaseDelay,
+		scheduler.workerConfig.FailureRateMaxDelay,
+		scheduler.workerConfig.RateLimitElementsPerSecond,
+		scheduler.workerConfig.RateLimitElementsBurst,
+		scheduler.workerConfig.Workers,
+		scheduler.cancelMatching,
+		nil,
+	)
+
+	if scheduler.jobReconciliationEnabled() {
+		if err := scheduler.initJobReconciliation(); err != nil { // coverage-ignore
+			return nil, err
+		}
+	}
+
 	scheduler.logger.Info("actors configured")
 
 	return scheduler, nil
@@ -120,6 +165,7 @@ func (scheduler *RequestScheduler) Init(_ context.Context) (*RequestScheduler, e
 
 func (scheduler *RequestScheduler) Start(ctx context.Context) {
 	go scheduler.CommitActor.Start(ctx, nil)
+	go scheduler.CancellationActor.Start(ctx, nil)
 	go scheduler.SchedulerActor.Start(ctx, nil)
 	go scheduler.LateSubmissionActor.Start(ctx, pipeline.NewActorPostStart(func(ctx context.Context) error {
 		scheduler.factory.Start(ctx.Done())
@@ -130,6 +176,14 @@ func (scheduler *RequestScheduler) Start(ctx context.Context) {
 
 		return nil
 	}))
+
+	if scheduler.supervisorConfig != nil && scheduler.supervisorConfig.Enabled {
+		go scheduler.runSupervisor(ctx)
+	}
+
+	if scheduler.jobReconciliationEnabled() {
+		scheduler.startJobReconciliation(ctx)
+	}
 }
 
 func (scheduler *RequestScheduler) OnEvent(obj interface{}) {
@@ -175,59 +229,119 @@ func (scheduler *RequestScheduler) OnEvent(obj interface{}) {
 	switch event.Reason {
 	case "Killing", "Failed", "Terminated", "Evicted":
 		scheduler.logger.V(0).Info("discovered a scheduler terminated externally", "instance", pod.Name, "eventReason", event.Reason, "eventDetail", event.Message)
-		// host has been terminated - find its BUFFERED submissions and resubmit them
-		checkpoints, err := scheduler.buffer.GetBuffered(event.InvolvedObject.Name)
+		scheduler.recoverHost(pod.Name)
+
+	case "FailedScheduling", "Nominated", "Scheduled", "Pulled", "Created":
+		// do not log startup/schedule events
+		return
+	default:
+		scheduler.logger.V(0).Info("unmapped reason - skipping", "instance", pod.Name, "eventReason", event.Reason, "eventDetail", event.Message)
+		return
+	}
+}
+
+func recoveryKey(checkpoint *coremodels.CheckpointedRequest) string {
+	return checkpoint.Algorithm + "/" + checkpoint.Id
+}
+
+// enqueueRecovery sends a request left over by a terminated host for late submission, unless this instance is already recovering it
+func (scheduler *RequestScheduler) enqueueRecovery(checkpoint *coremodels.CheckpointedRequest, entry *coremodels.SubmissionBufferEntry) {
+	if _, loaded := scheduler.recovering.LoadOrStore(recoveryKey(checkpoint), true); loaded {
+		scheduler.logger.V(1).Info("request is already being recovered - skipping", "request", checkpoint.Id, "template", checkpoint.Algorithm)
+		return
+	}
+
+	scheduler.recordTemplateEvent(checkpoint.Algorithm, corev1.EventTypeNormal, EventReasonRunRecovered, "Run %s received by terminated scheduler instance %s has been picked up for submission", checkpoint.Id, checkpoint.ReceivedByHost)
+	scheduler.LateSubmissionActor.Receive(&LateSubmission{
+		Checkpoint:    checkpoint,
+		BufferedEntry: entry,
+	})
+}
+
+// completeRecovery marks the request as no longer being recovered by this instance
+func (scheduler *RequestScheduler) completeRecovery(checkpoint *coremodels.CheckpointedRequest) {
+	scheduler.recovering.Delete(recoveryKey(checkpoint))
+}
+
+// recoverHost resubmits BUFFERED requests received by a terminated scheduler instance, and resolves its NEW requests
+func (scheduler *RequestScheduler) recoverHost(host string) {
+	// every scheduler instance observes the same termination events - only one of them should recover the host
+	claimed, err := scheduler.claimRecovery(host)
+	if err != nil { // coverage-ignore
+		utilruntime.HandleError(err)
+		return
+	}
+
+	if !claimed {
+		scheduler.logger.V(0).Info("recovery of a terminated host is claimed by another scheduler instance - skipping", "terminatedHost", host)
+		return
+	}
+
+	// host has been terminated - find its BUFFERED submissions and resubmit them
+	checkpoints, err := scheduler.buffer.GetBuffered(host)
+	if err != nil { // coverage-ignore
+		utilruntime.HandleError(err)
+		return
+	}
+	for checkpoint := range checkpoints {
+		scheduler.logger.V(0).Info("BUFFERED checkpoint left over from terminated host - scheduling", "request", checkpoint.Id, "template", checkpoint.Algorithm, "terminatedHost", host)
+
+		entry, err := scheduler.buffer.GetBufferedEntry(checkpoint)
 		if err != nil { // coverage-ignore
 			utilruntime.HandleError(err)
-			return
+		} else {
+			scheduler.enqueueRecovery(checkpoint, entry)
 		}
-		for checkpoint := range checkpoints {
-			scheduler.logger.V(0).Info("BUFFERED checkpoint left over from terminated host - scheduling", "request", checkpoint.Id, "template", checkpoint.Algorithm, "terminatedHost", pod.Name)
+	}
 
-			entry, err := scheduler.buffer.GetBufferedEntry(checkpoint)
-			if err != nil { // coverage-ignore
+	// host has been been terminated - find its NEW submissions and fail them
+	lostCheckpoints, err := scheduler.buffer.GetNew(host)
+	if err != nil { // coverage-ignore
+		utilruntime.HandleError(err)
+		return
+	}
+	for lostCheckpoint := range lostCheckpoints {
+		if lostCheckpoint.LifecycleStage == coremodels.LifecycleStageNew {
+			// payload might have been persisted before the host was terminated - in this case the run can still be submitted
+			entry, err := scheduler.buffer.GetBufferedEntry(lostCheckpoint)
+			if err != nil && !storage.IsNotFound(err) { // coverage-ignore
 				utilruntime.HandleError(err)
-			} else {
-				scheduler.LateSubmissionActor.Receive(&LateSubmission{
-					Checkpoint:    checkpoint,
-					BufferedEntry: entry,
-				})
+				continue
 			}
-		}
 
-		// host has been been terminated - find its NEW submissions and fail them
-		lostCheckpoints, err := scheduler.buffer.GetNew(event.InvolvedObject.Name)
-		if err != nil { // coverage-ignore
-			utilruntime.HandleError(err)
-			return
-		}
-		for lostCheckpoint := range lostCheckpoints {
-			if lostCheckpoint.LifecycleStage == coremodels.LifecycleStageNew {
-				scheduler.logger.V(0).Info("NEW checkpoint left over from terminated host - marking as SCHEDULING_FAILED", "request", lostCheckpoint.Id, "template", lostCheckpoint.Algorithm, "terminatedHost", pod.Name)
-
-				lostCopy := lostCheckpoint.DeepCopy()
-				lostCopy.LifecycleStage = coremodels.LifecycleStageSchedulingFailed
-				lostCopy.AlgorithmFailureCause = "Submission lost. Please resend the request."
-				lostCopy.AlgorithmFailureDetails = "Scheduler was interrupted before it could serialize the payload."
-
-				err := scheduler.buffer.Update(lostCopy)
-				if err != nil { // coverage-ignore
-					utilruntime.HandleError(err)
-					return
-				}
+			if entry != nil {
+				scheduler.logger.V(0).Info("NEW checkpoint left over from terminated host has a buffered payload - scheduling", "request", lostCheckpoint.Id, "template", lostCheckpoint.Algorithm, "terminatedHost", host)
+				scheduler.enqueueRecovery(lostCheckpoint, entry)
+				continue
 			}
-		}
 
-	case "FailedScheduling", "Nominated", "Scheduled", "Pulled", "Created":
-		// do not log startup/schedule events
-		return
-	default:
-		scheduler.logger.V(0).Info("unmapped reason - skipping", "instance", pod.Name, "eventReason", event.Reason, "eventDetail", event.Message)
-		return
+			scheduler.logger.V(0).Info("NEW checkpoint left over from terminated host - marking as SCHEDULING_FAILED", "request", lostCheckpoint.Id, "template", lostCheckpoint.Algorithm, "terminatedHost", host)
+
+			lostCopy := lostCheckpoint.DeepCopy()
+			lostCopy.LifecycleStage = coremodels.LifecycleStageSchedulingFailed
+			lostCopy.AlgorithmFailureCause = "Submission lost. Please resend the request."
+			lostCopy.AlgorithmFailureDetails = "Scheduler was interrupted before it could serialize the payload."
+
+			err = scheduler.buffer.Update(lostCopy)
+			if err != nil { // coverage-ignore
+				utilruntime.HandleError(err)
+				return
+			}
+
+			scheduler.recordTemplateEvent(lostCheckpoint.Algorithm, corev1.EventTypeWarning, EventReasonSchedulingFailed, "Run %s was lost: scheduler instance %s was terminated before it could persist the payload", lostCheckpoint.Id, host)
+		}
 	}
 }
 
-func (scheduler *RequestScheduler) commit(output *coremodels.CheckpointedRequest) (string, error) {
+func (scheduler *RequestScheduler) commit(submitted *models.SubmittedRun) (string, error) {
+	// runs cancelled before submission are skipped by the scheduler
+	if submitted == nil {
+		return "", nil
+	}
+
+	output := submitted.Checkpoint
+	defer scheduler.completeRecovery(output)
+
 	if output.JobUid == DryRunUID {
 		output.LifecycleStage = coremodels.LifecycleStageCompleted
 		output.SentAt = time.Now()
@@ -237,6 +351,12 @@ func (scheduler *RequestScheduler) commit(output *coremodels.CheckpointedRequest
 			return output.Id, err
 		}
 	} else {
+		attributes := models.NewCheckpointAttributes(output.Algorithm, output.Id)
+		attributes.Shard = submitted.Shard
+		if err := scheduler.store.UpsertAttributes(attributes, models.AttributeShard); err != nil { // coverage-ignore
+			return output.Id, err
+		}
+
 		output.LifecycleStage = coremodels.LifecycleStageRunning
 		output.SentAt = time.Now()
 		err := scheduler.buffer.Update(output)
@@ -244,10 +364,73 @@ func (scheduler *RequestScheduler) commit(output *coremodels.CheckpointedRequest
 		if err != nil { // coverage-ignore
 			return output.Id, err
 		}
+
+		scheduler.reportQueueTime(output)
+
+		// run might have been cancelled while its Job was being created - in this case CancelRun may have missed the Job
+		cancellation, err := scheduler.getCancellation(output)
+		if err != nil { // coverage-ignore
+			return output.Id, err
+		}
+
+		if cancellation != nil {
+			scheduler.logger.V(0).Info("run cancelled during submission - removing submitted job", "request", output.Id, "template", output.Algorithm)
+			if err := scheduler.markCancelled(output, cancellation.CancelledBy, cancellation.CancellationReason); err != nil { // coverage-ignore
+				return output.Id, err
+			}
+
+			if shard := scheduler.getShardByName(submitted.Shard); shard != nil {
+				if err := shard.DeleteJob(scheduler.runNamespace(output.Algorithm), output.Id, metav1.DeletePropagationBackground); err != nil && !errors.IsNotFound(err) { // coverage-ignore
+					return output.Id, err
+				}
+			}
+		}
 	}
 	return output.Id, nil
 }
 
+// getCancellation returns attributes of a cancellation recorded for the run, or nil if the run has not been cancelled
+func (scheduler *RequestScheduler) getCancellation(checkpoint *coremodels.CheckpointedRequest) (*models.CheckpointAttributes, error) {
+	attributes, err := scheduler.store.ReadAttributes(checkpoint.Algorithm, checkpoint.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if attributes == nil || !attributes.Cancelled {
+		return nil, nil
+	}
+
+	return attributes, nil
+}
+
+// skipUnschedulable checks if the run has been cancelled or has exceeded its queue deadline before submission, and ensures its checkpoint reflects that
+func (scheduler *RequestScheduler) skipUnschedulable(checkpoint *coremodels.CheckpointedRequest) (bool, error) {
+	attributes, err := scheduler.store.ReadAttributes(checkpoint.Algorithm, checkpoint.Id)
+	if err != nil { // coverage-ignore
+		return false, err
+	}
+
+	if attributes == nil {
+		return false, nil
+	}
+
+	if attributes.Cancelled {
+		scheduler.logger.V(0).Info("run cancelled before submission - skipping", "request", checkpoint.Id, "template", checkpoint.Algorithm)
+		scheduler.completeRecovery(checkpoint)
+
+		// buffer may have overwritten the cancelled checkpoint when persisting the payload
+		return true, scheduler.markCancelled(checkpoint, attributes.CancelledBy, attributes.CancellationReason)
+	}
+
+	if !attributes.QueueDeadline.IsZero() && time.Now().After(attributes.QueueDeadline) {
+		scheduler.completeRecovery(checkpoint)
+
+		return true, scheduler.markDeadlineExceeded(checkpoint, attributes.QueueDeadline)
+	}
+
+	return false, nil
+}
+
 func (scheduler *RequestScheduler) getShardByName(shardName string) *shards.ShardClient {
 	for _, shard := range scheduler.shardClients {
 		if shard.Name == shardName {
@@ -258,7 +441,41 @@ func (scheduler *RequestScheduler) getShardByName(shardName string) *shards.Shar
 	return nil
 }
 
-func (scheduler *RequestScheduler) schedule(output *request.BufferOutput) (*coremodels.CheckpointedRequest, error) {
+// getRunShard returns the shard a run was submitted to, or nil if the run has no shard recorded
+func (scheduler *RequestScheduler) getRunShard(requestId string, algorithmName string) (*shards.ShardClient, error) {
+	attributes, err := scheduler.store.ReadAttributes(algorithmName, requestId)
+	if err != nil {
+		return nil, err
+	}
+
+	if attributes == nil || attributes.Shard == "" {
+		return nil, nil
+	}
+
+	if shard := scheduler.getShardByName(attributes.Shard); shard != nil {
+		return shard, nil
+	}
+
+	return nil, fmt.Errorf("run '%s' was submitted to shard '%s' which is not configured", requestId, attributes.Shard)
+}
+
+// sendJob creates a Job in the provided shard, in the namespace of the shard or of the tenant of the algorithm. Job names are derived from request identifiers, so if a Job already exists, it has been created for the same request by another scheduler instance.
+func (scheduler *RequestScheduler) sendJob(shard *shards.ShardClient, job *batchv1.Job, algorithmName string) (*batchv1.Job, error) {
+	namespace := shard.Namespace
+	if tenantNamespace := scheduler.tenantJobNamespace(algorithmName); tenantNamespace != "" {
+		namespace = tenantNamespace
+	}
+
+	submitted, err := shard.SendJob(namespace, job)
+	if errors.IsAlreadyExists(err) {
+		scheduler.logger.V(0).Info("job has already been submitted - reusing it", "request", job.Name, "shard", shard.Name)
+		return shard.FindJob(job.Name, namespace)
+	}
+
+	return submitted, err
+}
+
+func (scheduler *RequestScheduler) schedule(output *request.BufferOutput) (*models.SubmittedRun, error) {
 	if output == nil {
 		return nil, fmt.Errorf("buffer has not provided any data to schedule")
 	}
@@ -267,7 +484,11 @@ func (scheduler *RequestScheduler) schedule(output *request.BufferOutput) (*core
 		scheduler.logger.V(0).Info("request marked as dry run - skipping job creation")
 		resultCheckpoint := output.Checkpoint.DeepCopy()
 		resultCheckpoint.JobUid = DryRunUID
-		return resultCheckpoint, nil
+		return &models.SubmittedRun{Checkpoint: resultCheckpoint}, nil
+	}
+
+	if skip, err := scheduler.skipUnschedulable(output.Checkpoint); skip || err != nil {
+		return nil, err
 	}
 
 	var job = output.Checkpoint.ToV1Job(fmt.Sprintf("%s-%s", buildmeta.AppVersion, buildmeta.BuildNumber), output.Workgroup, output.ParentReference)
@@ -275,29 +496,34 @@ func (scheduler *RequestScheduler) schedule(output *request.BufferOutput) (*core
 	var submitErr error
 
 	if shard := scheduler.getShardByName(output.Workgroup.Cluster); shard != nil {
-		submitted, submitErr = shard.SendJob(shard.Namespace, &job)
+		submitted, submitErr = scheduler.sendJob(shard, &job, output.Checkpoint.Algorithm)
 	} else {
-		return nil, fmt.Errorf("shard API server %s not configured", output.Workgroup.Cluster)
+		return nil, scheduler.retryOrFail(output.Checkpoint, nil, fmt.Errorf("shard API server %s not configured", output.Workgroup.Cluster))
 	}
 
-	if submitErr != nil { // coverage-ignore
-		return nil, submitErr
+	if submitErr != nil {
+		return nil, scheduler.retryOrFail(output.Checkpoint, output.Entry, submitErr)
 	}
 
 	resultCheckpoint := output.Checkpoint.DeepCopy()
 	resultCheckpoint.JobUid = string(submitted.UID)
 
-	return resultCheckpoint, nil
+	return &models.SubmittedRun{Checkpoint: resultCheckpoint, Shard: output.Workgroup.Cluster}, nil
 }
 
-func (scheduler *RequestScheduler) lateSchedule(submission *LateSubmission) (*coremodels.CheckpointedRequest, error) {
+func (scheduler *RequestScheduler) lateSchedule(submission *LateSubmission) (*models.SubmittedRun, error) {
 	if submission == nil {
 		return nil, fmt.Errorf("no buffer entry provided")
 	}
 
+	if skip, err := scheduler.skipUnschedulable(submission.Checkpoint); skip || err != nil {
+		return nil, err
+	}
+
 	job, err := submission.BufferedEntry.SubmissionTemplate()
 
 	if err != nil { // coverage-ignore
+		scheduler.completeRecovery(submission.Checkpoint)
 		return nil, err
 	}
 
@@ -306,24 +532,75 @@ func (scheduler *RequestScheduler) lateSchedule(submission *LateSubmission) (*co
 
 	if shard := scheduler.getShardByName(submission.BufferedEntry.Cluster); shard != nil {
 		scheduler.logger.V(0).Info("picked up a delayed request - submitting", "request", job.Name, "template", submission.BufferedEntry.Algorithm)
-		submitted, submitErr = shard.SendJob(shard.Namespace, job)
+		submitted, submitErr = scheduler.sendJob(shard, job, submission.Checkpoint.Algorithm)
 	} else { // coverage-ignore
-		return nil, fmt.Errorf("shard API server %s not configured", submission.BufferedEntry.Cluster)
+		return nil, scheduler.retryOrFail(submission.Checkpoint, nil, fmt.Errorf("shard API server %s not configured", submission.BufferedEntry.Cluster))
 	}
 
-	if submitErr != nil { // coverage-ignore
-		return nil, submitErr
+	if submitErr != nil {
+		return nil, scheduler.retryOrFail(submission.Checkpoint, submission.BufferedEntry, submitErr)
 	}
 
 	resultCheckpoint := submission.Checkpoint.DeepCopy()
 	resultCheckpoint.JobUid = string(submitted.UID)
 
-	return resultCheckpoint, nil
+	return &models.SubmittedRun{Checkpoint: resultCheckpoint, Shard: submission.BufferedEntry.Cluster}, nil
 }
 
-func (scheduler *RequestScheduler) ResolveParent(parentRequestId string, clusterName string) (*metav1.OwnerReference, error) {
+// ParentRunName returns the name a parent run is stored under. Clients may address a parent by an alias or a tenant-qualified name, while descendants of a run are looked up by the name the run is stored under
+func (scheduler *RequestScheduler) ParentRunName(parent *coremodels.AlgorithmRequestRef) (string, error) {
+	if scheduler.configCache == nil { // coverage-ignore
+		return RunAlgorithmName(parent.AlgorithmName), nil
+	}
+
+	for _, name := range scheduler.configCache.AlgorithmNames(parent.AlgorithmName) {
+		checkpoint, err := scheduler.buffer.Get(parent.RequestId, name)
+		if err != nil && !storage.IsNotFound(err) {
+			return "", err
+		}
+
+		if checkpoint != nil {
+			return name, nil
+		}
+	}
+
+	// parent run may not be buffered yet, in which case it is stored under the name of its template
+	return scheduler.runName(parent.AlgorithmName), nil
+}
+
+// runName resolves an algorithm name, a template alias or a tenant-qualified name to the name new runs are stored under, the same way submissions do
+func (scheduler *RequestScheduler) runName(algorithmName string) string {
+	if scheduler.configCache == nil { // coverage-ignore
+		return RunAlgorithmName(algorithmName)
+	}
+
+	if template, _ := scheduler.configCache.GetAlgorithmConfiguration(algorithmName); template != nil {
+		return scheduler.configCache.RunName(template)
+	}
+
+	return RunAlgorithmName(algorithmName)
+}
+
+// ResolveParent creates an owner reference to the Job of a parent run. Parent Job must be located in the shard and the namespace the child run is submitted to.
+func (scheduler *RequestScheduler) ResolveParent(parent *coremodels.AlgorithmRequestRef, clusterName string, algorithmName string) (*metav1.OwnerReference, error) {
+	parentAlgorithm := RunAlgorithmName(parent.AlgorithmName)
+	parentShard, err := scheduler.getRunShard(parent.RequestId, parentAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	if parentShard != nil && parentShard.Name != clusterName {
+		return nil, fmt.Errorf("parent run '%s' is executed by shard '%s' and cannot own a run submitted to shard '%s'", parent.RequestId, parentShard.Name, clusterName)
+	}
+
+	// owner references cannot cross namespaces
+	parentNamespace := scheduler.runNamespace(parentAlgorithm)
+	if namespace := scheduler.runNamespace(algorithmName); namespace != parentNamespace {
+		return nil, fmt.Errorf("parent run '%s' is executed in namespace '%s' and cannot own a run submitted to namespace '%s'", parent.RequestId, parentNamespace, namespace)
+	}
+
 	if shard := scheduler.getShardByName(clusterName); shard != nil {
-		job, err := shard.FindJob(parentRequestId, scheduler.jobNamespace)
+		job, err := shard.FindJob(parent.RequestId, parentNamespace)
 
 		if err != nil {
 			return nil, err
@@ -340,28 +617,150 @@ func (scheduler *RequestScheduler) ResolveParent(parentRequestId string, cluster
 	}
 }
 
-func (scheduler *RequestScheduler) CancelRun(requestId string, algorithmName string, initiator string, reason string, policy metav1.DeletionPropagation) (exists bool, err error) {
+// findRunShard locates a shard that has a Job for the run. Shard recorded at commit time is used if present, otherwise all shards are scanned.
+func (scheduler *RequestScheduler) findRunShard(requestId string, algorithmName string) (*shards.ShardClient, error) {
+	shard, err := scheduler.getRunShard(requestId, algorithmName)
+	if err != nil {
+		return nil, err
+	}
+
+	if shard != nil {
+		if _, err := shard.FindJob(requestId, scheduler.runNamespace(algorithmName)); err != nil {
+			return nil, err
+		}
+
+		return shard, nil
+	}
+
+	// legacy runs do not have a shard recorded
 	for _, shard := range scheduler.shardClients {
-		if _, err := shard.FindJob(requestId, scheduler.jobNamespace); err == nil {
+		if _, err := shard.FindJob(requestId, scheduler.runNamespace(algorithmName)); err == nil {
+			return shard, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// markCancelled sets the run lifecycle stage to CANCELLED and records who cancelled it and why
+func (scheduler *RequestScheduler) markCancelled(checkpoint *coremodels.CheckpointedRequest, initiator string, reason string) error {
+	cancelled := checkpoint.DeepCopy()
+	cancelled.LifecycleStage = coremodels.LifecycleStageCancelled
+	cancelled.AlgorithmFailureCause = fmt.Sprintf("Cancelled by '%s'", initiator)
+	cancelled.AlgorithmFailureDetails = fmt.Sprintf("Run cancelled, reason: '%s'", reason)
+
+	return scheduler.buffer.Update(cancelled)
+}
 
-			checkpoint, err := scheduler.buffer.Get(requestId, algorithmName)
-			if err != nil {
-				return true, err
+// cancelCheckpoint cancels a run and, unless Orphan policy is used, all runs submitted with it as a parent
+func (scheduler *RequestScheduler) cancelCheckpoint(checkpoint *coremodels.CheckpointedRequest, initiator string, reason string, policy metav1.DeletionPropagation) error {
+	if err := scheduler.cancelSingleRun(checkpoint, initiator, reason, policy); err != nil {
+		return err
+	}
+
+	if policy == metav1.DeletePropagationOrphan {
+		return nil
+	}
+
+	return scheduler.cancelDescendants(checkpoint, initiator, reason, policy)
+}
+
+// cancelDescendants walks the run tree starting from the provided run and cancels all descendants that have not finished yet
+func (scheduler *RequestScheduler) cancelDescendants(root *coremodels.CheckpointedRequest, initiator string, reason string, policy metav1.DeletionPropagation) error {
+	parents := []*coremodels.AlgorithmRequestRef{{RequestId: root.Id, AlgorithmName: root.Algorithm}}
+	visited := map[string]bool{}
+
+	for len(parents) > 0 {
+		parent := parents[0]
+		parents = parents[1:]
+
+		children, err := scheduler.store.ReadChildCheckpoints(parent)
+		if err != nil { // coverage-ignore
+			return err
+		}
+
+		for child, err := range children {
+			if err != nil { // coverage-ignore
+				return err
 			}
 
-			cancelled := checkpoint.DeepCopy()
-			cancelled.LifecycleStage = coremodels.LifecycleStageCancelled
-			cancelled.AlgorithmFailureCause = fmt.Sprintf("Cancelled by '%s'", initiator)
-			cancelled.AlgorithmFailureDetails = fmt.Sprintf("Run cancelled, reason: '%s'", reason)
-			err = scheduler.buffer.Update(cancelled)
+			childKey := child.Algorithm + "/" + child.Id
+			if visited[childKey] {
+				continue
+			}
+			visited[childKey] = true
 
-			if err != nil {
-				return true, err
+			// finished runs may still have running descendants
+			parents = append(parents, &coremodels.AlgorithmRequestRef{RequestId: child.Id, AlgorithmName: child.Algorithm})
+			if child.IsFinished() {
+				continue
 			}
 
-			return true, shard.DeleteJob(scheduler.jobNamespace, requestId, policy)
+			scheduler.logger.V(0).Info("cancelling a child run", "request", child.Id, "template", child.Algorithm, "parentRequest", parent.RequestId, "parentTemplate", parent.AlgorithmName)
+			if err := scheduler.cancelSingleRun(child, initiator, reason, policy); err != nil {
+				return err
+			}
 		}
 	}
 
-	return false, fmt.Errorf("no shard has a run with identifier '%s'", requestId)
+	return nil
+}
+
+// cancelSingleRun cancels a run and deletes its Job if the run has been submitted. Runs that have not been submitted yet are skipped by the scheduler.
+func (scheduler *RequestScheduler) cancelSingleRun(checkpoint *coremodels.CheckpointedRequest, initiator string, reason string, policy metav1.DeletionPropagation) error {
+	// record cancellation first, so a concurrent submission can detect it after creating the Job
+	cancellation := models.NewCheckpointAttributes(checkpoint.Algorithm, checkpoint.Id)
+	cancellation.Cancelled = true
+	cancellation.CancelledBy = initiator
+	cancellation.CancellationReason = reason
+	if err := scheduler.store.UpsertAttributes(cancellation, models.AttributeCancelled, models.AttributeCancelledBy, models.AttributeCancellationReason); err != nil {
+		return err
+	}
+
+	var shard *shards.ShardClient
+	var err error
+	if checkpoint.LifecycleStage == coremodels.LifecycleStageRunning {
+		shard, err = scheduler.findRunShard(checkpoint.Id, checkpoint.Algorithm)
+	} else {
+		// a Job for NEW or BUFFERED run exists only if the submission has been committed concurrently
+		shard, err = scheduler.getRunShard(checkpoint.Id, checkpoint.Algorithm)
+	}
+
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	if shard != nil {
+		if err := shard.DeleteJob(scheduler.runNamespace(checkpoint.Algorithm), checkpoint.Id, policy); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	if err := scheduler.markCancelled(checkpoint, initiator, reason); err != nil { // coverage-ignore
+		return err
+	}
+
+	scheduler.recordTemplateEvent(checkpoint.Algorithm, corev1.EventTypeNormal, EventReasonRunCancelled, "Run %s cancelled by '%s', reason: '%s'", checkpoint.Id, initiator, reason)
+
+	return nil
+}
+
+// CancelRun cancels a run that has not finished yet. Returns false if the run does not exist.
+func (scheduler *RequestScheduler) CancelRun(requestId string, algorithmName string, initiator string, reason string, policy metav1.DeletionPropagation) (exists bool, err error) {
+	algorithmName = RunAlgorithmName(algorithmName)
+	checkpoint, err := scheduler.buffer.Get(requestId, algorithmName)
+	if storage.IsNotFound(err) || (err == nil && checkpoint == nil) {
+		return false, fmt.Errorf("run with identifier '%s' does not exist", requestId)
+	}
+
+	if err != nil { // coverage-ignore
+		return true, err
+	}
+
+	if checkpoint.IsFinished() {
+		scheduler.logger.V(0).Info("run has already finished - skipping cancellation", "request", requestId, "template", algorithmName, "lifecycleStage", checkpoint.LifecycleStage)
+		return true, nil
+	}
+
+	return true, scheduler.cancelCheckpoint(checkpoint, initiator, reason, policy)
 }
@@ -1,7 +1,9 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	goerrors "errors"
 	v1 "github.com/SneaksAndData/nexus-core/pkg/apis/science/v1"
 	coremodels "github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
 	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/request"
@@ -9,6 +11,7 @@ import (
 	"github.com/SneaksAndData/nexus-core/pkg/generated/clientset/versioned/fake"
 	"github.com/SneaksAndData/nexus-core/pkg/shards"
 	"github.com/SneaksAndData/nexus/services/models"
+	"github.com/SneaksAndData/nexus/storage"
 	"github.com/aws/smithy-go/ptr"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -17,8 +20,12 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/ktesting"
+	"os"
+	"strings"
 	"testing"
 	"time"
 )
@@ -29,6 +36,8 @@ type schedulerFixture struct {
 	shardClient      kubernetes.Interface
 	nexusShardClient nexuscore.Interface
 	buffer           request.Buffer
+	store            *storage.MemoryStore
+	recorder         *record.FakeRecorder
 	ctx              context.Context
 }
 
@@ -116,6 +125,17 @@ func newSchedulerFixture(t *testing.T, existingObjects []runtime.Object, existin
 	f.shardClient = k8sfake.NewClientset(existingShardObjects...)
 	f.nexusShardClient = fake.NewClientset()
 	f.buffer = request.NewMemoryPassthroughBuffer(ctx, map[string]string{})
+	f.store = storage.NewMemoryStore(f.buffer.(*request.MemoryPassthroughBuffer))
+	f.recorder = record.NewFakeRecorder(1000)
+
+	configCache := NewNexusResourceCache(fake.NewClientset(), "nexus", klog.FromContext(ctx), &resyncPeriod)
+	_ = configCache.templateInformer.GetIndexer().Add(&v1.NexusAlgorithmTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-algorithm",
+			Namespace: "nexus",
+		},
+		Spec: *newFakeSpec(),
+	})
 
 	f.scheduler = NewRequestScheduler(&models.PipelineWorkerConfig{
 		FailureRateBaseDelay:       time.Second,
@@ -123,13 +143,98 @@ func newSchedulerFixture(t *testing.T, existingObjects []runtime.Object, existin
 		RateLimitElementsPerSecond: 10,
 		RateLimitElementsBurst:     10,
 		Workers:                    2,
+	}, &models.SchedulingRetryConfig{
+		Default: models.SchedulingRetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Millisecond * 100,
+			MaxDelay:    time.Second,
+		},
 	}, f.kubeClient, []*shards.ShardClient{
 		shards.NewShardClient(f.shardClient, f.nexusShardClient, "test-shard", "nexus", klog.FromContext(f.ctx)),
-	}, f.buffer, "nexus", "nexus", klog.FromContext(ctx), &resyncPeriod)
+	}, f.buffer, f.store, "nexus", "nexus", klog.FromContext(ctx), &resyncPeriod).WithEventRecorder(f.recorder, configCache)
 
 	return f
 }
 
+// expectEvents checks that the recorder has received events with the provided reasons, in order
+func (f *schedulerFixture) expectEvents(t *testing.T, reasons ...string) {
+	recorded := []string{}
+	for len(f.recorder.Events) > 0 {
+		recorded = append(recorded, <-f.recorder.Events)
+	}
+
+	index := 0
+	for _, event := range recorded {
+		if index < len(reasons) && strings.Contains(event, " "+reasons[index]+" ") {
+			index++
+		}
+	}
+
+	if index != len(reasons) {
+		t.Errorf("expected events %v, but recorded %v", reasons, recorded)
+	}
+}
+
+// bufferedRuns receives runs persisted by the memory buffer in place of the scheduler actor. Receiving from it orders a test after the buffer writes, which polling the buffer does not
+type bufferedRuns chan *request.BufferOutput
+
+func (runs bufferedRuns) Receive(output *request.BufferOutput) {
+	runs <- output
+}
+
+// wait blocks until the buffer persists a run
+func (runs bufferedRuns) wait(t *testing.T) *request.BufferOutput {
+	select {
+	case output := <-runs:
+		return output
+	case <-time.After(10 * time.Second):
+		t.Errorf("expected a run to be buffered")
+		t.FailNow()
+		return nil
+	}
+}
+
+// persistedRuns stores runs as BUFFERED before passing them on, the same way a persistent buffer does. The memory buffer keeps the checkpoints it receives NEW
+type persistedRuns struct {
+	buffer *request.MemoryPassthroughBuffer
+	next   interface{ Receive(*request.BufferOutput) }
+}
+
+func (runs *persistedRuns) Receive(output *request.BufferOutput) {
+	_ = runs.buffer.Update(output.Checkpoint.DeepCopy())
+	runs.next.Receive(output)
+}
+
+// scheduledRuns schedules and commits runs persisted by the memory buffer on the buffer goroutine, in place of the scheduler actor. Receiving from it orders a test after the scheduler updates the buffer
+type scheduledRuns struct {
+	scheduler *RequestScheduler
+	runs      chan *models.SubmittedRun
+}
+
+func newScheduledRuns(scheduler *RequestScheduler) *scheduledRuns {
+	return &scheduledRuns{scheduler: scheduler, runs: make(chan *models.SubmittedRun, 10)}
+}
+
+func (runs *scheduledRuns) Receive(output *request.BufferOutput) {
+	submitted, err := runs.scheduler.schedule(output)
+	if err == nil {
+		_, _ = runs.scheduler.commit(submitted)
+	}
+	runs.runs <- submitted
+}
+
+// wait blocks until the scheduler commits a run
+func (runs *scheduledRuns) wait(t *testing.T) *models.SubmittedRun {
+	select {
+	case submitted := <-runs.runs:
+		return submitted
+	case <-time.After(10 * time.Second):
+		t.Errorf("expected a run to be scheduled")
+		t.FailNow()
+		return nil
+	}
+}
+
 func TestScheduler(t *testing.T) {
 	f := newSchedulerFixture(t, []runtime.Object{}, []runtime.Object{})
 	scheduler, err := f.scheduler.Init(f.ctx)
@@ -170,6 +275,12 @@ func TestScheduler(t *testing.T) {
 		t.Errorf("The checkpoint lifecycle stage must be running, but %s", checkpoint.LifecycleStage)
 		t.FailNow()
 	}
+
+	attributes, _ := f.store.ReadAttributes("test-algorithm", "test")
+	if attributes == nil || attributes.Shard != "test-shard" {
+		t.Errorf("The run must have shard 'test-shard' recorded, but found %v", attributes)
+		t.FailNow()
+	}
 }
 
 func TestScheduler_Restart(t *testing.T) {
@@ -233,6 +344,14 @@ func TestScheduler_Restart(t *testing.T) {
 	buffer.Checkpoints = append(buffer.Checkpoints, checkpoint)
 	buffer.BufferedEntries = append(buffer.BufferedEntries, coremodels.FromCheckpoint(checkpoint, newFakeWorkgroupSpec(), nil))
 
+	// add NEW submission with a persisted payload
+	newCheckpoint, _, _ := coremodels.FromAlgorithmRequest("test-new-id", "test-algorithm", newFakeRequest(), newFakeSpec())
+	newCheckpoint.LifecycleStage = coremodels.LifecycleStageNew
+	newCheckpoint.ReceivedByHost = "test-terminated-scheduler"
+
+	buffer.Checkpoints = append(buffer.Checkpoints, newCheckpoint)
+	buffer.BufferedEntries = append(buffer.BufferedEntries, coremodels.FromCheckpoint(newCheckpoint, newFakeWorkgroupSpec(), nil))
+
 	_, err := f.scheduler.Init(f.ctx)
 
 	if err != nil {
@@ -260,6 +379,13 @@ func TestScheduler_Restart(t *testing.T) {
 		t.Errorf("The checkpoint lifecycle stage must be running, but %s", lateCheckpoint.LifecycleStage)
 		t.FailNow()
 	}
+
+	recoveredCheckpoint, _ := f.buffer.Get("test-new-id", "test-algorithm")
+	if recoveredCheckpoint.LifecycleStage != coremodels.LifecycleStageRunning {
+		t.Errorf("The NEW checkpoint with a buffered payload must be running, but %s", recoveredCheckpoint.LifecycleStage)
+	}
+
+	f.expectEvents(t, EventReasonRunRecovered, EventReasonRunRecovered)
 }
 
 func TestScheduler_ResolveParent(t *testing.T) {
@@ -309,7 +435,7 @@ func TestScheduler_ResolveParent(t *testing.T) {
 	time.Sleep(1 * time.Second)
 
 	// check status
-	owner, err := f.scheduler.ResolveParent("test-job-1", "test-shard")
+	owner, err := f.scheduler.ResolveParent(&coremodels.AlgorithmRequestRef{RequestId: "test-job-1", AlgorithmName: "test-algorithm"}, "test-shard", "test-algorithm")
 	if err != nil {
 		t.Errorf("failed to resolve parent: %s", err)
 		t.FailNow()
@@ -320,6 +446,20 @@ func TestScheduler_ResolveParent(t *testing.T) {
 	}
 }
 
+func TestScheduler_ResolveParentOtherShard(t *testing.T) {
+	f := newSchedulerFixture(t, []runtime.Object{}, []runtime.Object{})
+	_ = f.store.UpsertAttributes(&models.CheckpointAttributes{
+		Algorithm: "test-algorithm",
+		Id:        "test-job-1",
+		Shard:     "other-shard",
+	})
+
+	_, err := f.scheduler.ResolveParent(&coremodels.AlgorithmRequestRef{RequestId: "test-job-1", AlgorithmName: "test-algorithm"}, "test-shard", "test-algorithm")
+	if err == nil {
+		t.Errorf("expected parent resolution to fail for a parent submitted to a different shard")
+	}
+}
+
 func TestScheduler_CancelRun(t *testing.T) {
 	jobs := []batchv1.Job{
 		{
@@ -404,3 +544,1182 @@ func TestScheduler_CancelRun(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func TestScheduler_CancelTaggedRuns(t *testing.T) {
+	jobs := []batchv1.Job{
+		{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Job",
+				APIVersion: "batch/v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-job-1",
+				Namespace: "nexus",
+				UID:       "test-job-1-uid",
+			},
+			Spec: batchv1.JobSpec{},
+		},
+	}
+
+	objects := []runtime.Object{}
+
+	for _, job := range jobs {
+		objects = append(objects, &job)
+	}
+
+	f := newSchedulerFixture(t, []runtime.Object{}, objects)
+	buffer := f.buffer.(*request.MemoryPassthroughBuffer)
+
+	// add RUNNING, BUFFERED and COMPLETED submissions sharing a tag
+	for id, stage := range map[string]string{
+		"test-job-1": coremodels.LifecycleStageRunning,
+		"test-job-2": coremodels.LifecycleStageBuffered,
+		"test-job-3": coremodels.LifecycleStageCompleted,
+	} {
+		checkpoint, _, _ := coremodels.FromAlgorithmRequest(id, "test-algorithm", newFakeRequest(), newFakeSpec())
+		checkpoint.LifecycleStage = stage
+		checkpoint.Tag = "test-tag"
+		buffer.Checkpoints = append(buffer.Checkpoints, checkpoint)
+	}
+	_ = f.store.UpsertAttributes(&models.CheckpointAttributes{
+		Algorithm: "test-algorithm",
+		Id:        "test-job-1",
+		Shard:     "test-shard",
+	})
+
+	_, err := f.scheduler.Init(f.ctx)
+
+	if err != nil {
+		t.Errorf("failed to initialize scheduler: %s", err)
+		t.FailNow()
+	}
+	go f.scheduler.Start(f.ctx)
+
+	time.Sleep(1 * time.Second)
+
+	operation, err := f.scheduler.CancelTaggedRuns("test-tag", nil, "tester", "test", metav1.DeletePropagationBackground)
+	if err != nil {
+		t.Errorf("failed to start a bulk cancellation: %s", err)
+		t.FailNow()
+	}
+
+	// allow cancellation to happen
+	time.Sleep(2 * time.Second)
+
+	progress, err := f.scheduler.GetCancellationOperation(operation.Id)
+	if err != nil || progress == nil {
+		t.Errorf("failed to read cancellation operation: %v", err)
+		t.FailNow()
+	}
+
+	if progress.Status != models.CancellationOperationCompleted || progress.Matched != 2 || progress.Cancelled != 2 {
+		t.Errorf("expected a completed operation with 2 cancelled runs, but found %v", progress)
+		t.FailNow()
+	}
+
+	for id, expected := range map[string]string{
+		"test-job-1": coremodels.LifecycleStageCancelled,
+		"test-job-2": coremodels.LifecycleStageCancelled,
+		"test-job-3": coremodels.LifecycleStageCompleted,
+	} {
+		checkpoint, _ := f.buffer.Get(id, "test-algorithm")
+		if checkpoint.LifecycleStage != expected {
+			t.Errorf("expected lifecycle stage of %s to be %s, but %s", id, expected, checkpoint.LifecycleStage)
+		}
+	}
+
+	if _, err := f.shardClient.BatchV1().Jobs("nexus").Get(f.ctx, "test-job-1", metav1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("expected job of a cancelled run to be deleted, but found: %v", err)
+	}
+}
+
+func TestScheduler_CancelAlgorithmRuns(t *testing.T) {
+	f := newSchedulerFixture(t, []runtime.Object{}, []runtime.Object{})
+	buffer := f.buffer.(*request.MemoryPassthroughBuffer)
+
+	for id, stage := range map[string]string{
+		"test-job-1": coremodels.LifecycleStageBuffered,
+		"test-job-2": coremodels.LifecycleStageNew,
+	} {
+		checkpoint, _, _ := coremodels.FromAlgorithmRequest(id, "test-algorithm", newFakeRequest(), newFakeSpec())
+		checkpoint.LifecycleStage = stage
+		buffer.Checkpoints = append(buffer.Checkpoints, checkpoint)
+	}
+
+	template, _ := f.scheduler.configCache.GetAlgorithmConfiguration("test-algorithm")
+	aliased := template.DeepCopy()
+	aliased.Annotations = map[string]string{AliasesAnnotation: `[{"name": "old-algorithm"}]`}
+	_ = f.scheduler.configCache.templateInformer.GetIndexer().Update(aliased)
+
+	_, err := f.scheduler.Init(f.ctx)
+
+	if err != nil {
+		t.Errorf("failed to initialize scheduler: %s", err)
+		t.FailNow()
+	}
+	go f.scheduler.Start(f.ctx)
+
+	time.Sleep(1 * time.Second)
+
+	// runs are addressed by an alias, while they are stored under the template name
+	operation, err := f.scheduler.CancelAlgorithmRuns("old-algorithm", []string{coremodels.LifecycleStageBuffered}, "tester", "test", metav1.DeletePropagationBackground)
+	if err != nil {
+		t.Errorf("failed to start a bulk cancellation: %s", err)
+		t.FailNow()
+	}
+
+	if operation.Algorithm != "test-algorithm" {
+		t.Errorf("expected the operation to target runs of test-algorithm, but got %s", operation.Algorithm)
+	}
+
+	// allow cancellation to happen
+	time.Sleep(2 * time.Second)
+
+	progress, _ := f.scheduler.GetCancellationOperation(operation.Id)
+	if progress == nil || progress.Status != models.CancellationOperationCompleted || progress.Cancelled != 1 {
+		t.Errorf("expected a completed operation with 1 cancelled run, but found %v", progress)
+		t.FailNow()
+	}
+
+	if checkpoint, _ := f.buffer.Get("test-job-2", "test-algorithm"); checkpoint.LifecycleStage != coremodels.LifecycleStageNew {
+		t.Errorf("expected a NEW run to be left as is, but %s", checkpoint.LifecycleStage)
+	}
+}
+
+func TestScheduler_CancelBufferedRun(t *testing.T) {
+	f := newSchedulerFixture(t, []runtime.Object{}, []runtime.Object{})
+
+	// add BUFFERED submission
+	buffer := f.buffer.(*request.MemoryPassthroughBuffer)
+	checkpoint, _, _ := coremodels.FromAlgorithmRequest("test-id", "test-algorithm", newFakeRequest(), newFakeSpec())
+	checkpoint.LifecycleStage = coremodels.LifecycleStageBuffered
+	entry := coremodels.FromCheckpoint(checkpoint, newFakeWorkgroupSpec(), nil)
+
+	buffer.Checkpoints = append(buffer.Checkpoints, checkpoint)
+	buffer.BufferedEntries = append(buffer.BufferedEntries, entry)
+
+	_, err := f.scheduler.Init(f.ctx)
+
+	if err != nil {
+		t.Errorf("failed to initialize scheduler: %s", err)
+		t.FailNow()
+	}
+	go f.scheduler.Start(f.ctx)
+
+	time.Sleep(1 * time.Second)
+
+	exists, err := f.scheduler.CancelRun("test-id", "test-algorithm", "tester", "test", metav1.DeletePropagationBackground)
+
+	if !exists || err != nil {
+		t.Errorf("failed to cancel a buffered run: %v", err)
+		t.FailNow()
+	}
+
+	// submit the cancelled run
+	f.scheduler.LateSubmissionActor.Receive(&LateSubmission{
+		Checkpoint:    checkpoint,
+		BufferedEntry: entry,
+	})
+
+	time.Sleep(2 * time.Second)
+
+	cancelled, _ := f.buffer.Get("test-id", "test-algorithm")
+	if cancelled.LifecycleStage != coremodels.LifecycleStageCancelled {
+		t.Errorf("expected lifecycle stage to be cancelled, but %s", cancelled.LifecycleStage)
+	}
+
+	if _, err := f.shardClient.BatchV1().Jobs("nexus").Get(f.ctx, "test-id", metav1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("expected no job to be created for a cancelled run, but found: %v", err)
+	}
+
+	f.expectEvents(t, EventReasonRunCancelled)
+}
+
+func TestScheduler_CancelMissingRun(t *testing.T) {
+	f := newSchedulerFixture(t, []runtime.Object{}, []runtime.Object{})
+
+	if exists, _ := f.scheduler.CancelRun("test-id", "test-algorithm", "tester", "test", metav1.DeletePropagationBackground); exists {
+		t.Errorf("expected a missing run to be reported as not existing")
+	}
+}
+
+func TestScheduler_CommitCancelledRun(t *testing.T) {
+	jobs := []batchv1.Job{
+		{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Job",
+				APIVersion: "batch/v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-id",
+				Namespace: "nexus",
+				UID:       "test-id-uid",
+			},
+			Spec: batchv1.JobSpec{},
+		},
+	}
+
+	objects := []runtime.Object{}
+
+	for _, job := range jobs {
+		objects = append(objects, &job)
+	}
+
+	f := newSchedulerFixture(t, []runtime.Object{}, objects)
+
+	// the run is cancelled while its Job is being created
+	buffer := f.buffer.(*request.MemoryPassthroughBuffer)
+	checkpoint, _, _ := coremodels.FromAlgorithmRequest("test-id", "test-algorithm", newFakeRequest(), newFakeSpec())
+	checkpoint.LifecycleStage = coremodels.LifecycleStageBuffered
+	buffer.Checkpoints = append(buffer.Checkpoints, checkpoint)
+
+	_ = f.store.UpsertAttributes(&models.CheckpointAttributes{
+		Algorithm:          "test-algorithm",
+		Id:                 "test-id",
+		Cancelled:          true,
+		CancelledBy:        "tester",
+		CancellationReason: "test",
+	})
+
+	submitted := checkpoint.DeepCopy()
+	submitted.JobUid = "test-id-uid"
+	if _, err := f.scheduler.commit(&models.SubmittedRun{Checkpoint: submitted, Shard: "test-shard"}); err != nil {
+		t.Errorf("failed to commit a run: %s", err)
+		t.FailNow()
+	}
+
+	cancelled, _ := f.buffer.Get("test-id", "test-algorithm")
+	if cancelled.LifecycleStage != coremodels.LifecycleStageCancelled {
+		t.Errorf("expected lifecycle stage to be cancelled, but %s", cancelled.LifecycleStage)
+	}
+
+	if _, err := f.shardClient.BatchV1().Jobs("nexus").Get(f.ctx, "test-id", metav1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("expected job of a cancelled run to be deleted, but found: %v", err)
+	}
+
+	attributes, _ := f.store.ReadAttributes("test-algorithm", "test-id")
+	if attributes.Shard != "test-shard" || !attributes.Cancelled {
+		t.Errorf("expected shard and cancellation to be recorded, but found %v", attributes)
+	}
+}
+
+func newRunTreeFixture(t *testing.T) *schedulerFixture {
+	f := newSchedulerFixture(t, []runtime.Object{}, []runtime.Object{})
+	buffer := f.buffer.(*request.MemoryPassthroughBuffer)
+
+	// parent -> child -> grandchild
+	parent, _, _ := coremodels.FromAlgorithmRequest("test-parent", "test-algorithm", newFakeRequest(), newFakeSpec())
+	parent.LifecycleStage = coremodels.LifecycleStageRunning
+
+	child, _, _ := coremodels.FromAlgorithmRequest("test-child", "test-child-algorithm", newFakeRequest(), newFakeSpec())
+	child.LifecycleStage = coremodels.LifecycleStageCompleted
+	child.Parent = &coremodels.AlgorithmRequestRef{RequestId: "test-parent", AlgorithmName: "test-algorithm"}
+
+	grandchild, _, _ := coremodels.FromAlgorithmRequest("test-grandchild", "test-child-algorithm", newFakeRequest(), newFakeSpec())
+	grandchild.LifecycleStage = coremodels.LifecycleStageBuffered
+	grandchild.Parent = &coremodels.AlgorithmRequestRef{RequestId: "test-child", AlgorithmName: "test-child-algorithm"}
+
+	buffer.Checkpoints = append(buffer.Checkpoints, parent, child, grandchild)
+
+	return f
+}
+
+func TestScheduler_CancelRunTree(t *testing.T) {
+	f := newRunTreeFixture(t)
+
+	exists, err := f.scheduler.CancelRun("test-parent", "test-algorithm", "tester", "test", metav1.DeletePropagationBackground)
+	if !exists || err != nil {
+		t.Errorf("failed to cancel a run tree: %v", err)
+		t.FailNow()
+	}
+
+	for id, expected := range map[string][]string{
+		"test-parent":     {"test-algorithm", coremodels.LifecycleStageCancelled},
+		"test-child":      {"test-child-algorithm", coremodels.LifecycleStageCompleted},
+		"test-grandchild": {"test-child-algorithm", coremodels.LifecycleStageCancelled},
+	} {
+		checkpoint, _ := f.buffer.Get(id, expected[0])
+		if checkpoint.LifecycleStage != expected[1] {
+			t.Errorf("expected lifecycle stage of %s to be %s, but %s", id, expected[1], checkpoint.LifecycleStage)
+		}
+	}
+
+	if grandchild, _ := f.buffer.Get("test-grandchild", "test-child-algorithm"); grandchild.AlgorithmFailureCause != "Cancelled by 'tester'" {
+		t.Errorf("expected descendant cancellation to record the initiator, but found '%s'", grandchild.AlgorithmFailureCause)
+	}
+}
+
+func TestScheduler_CancelRunTreeOrphan(t *testing.T) {
+	f := newRunTreeFixture(t)
+
+	exists, err := f.scheduler.CancelRun("test-parent", "test-algorithm", "tester", "test", metav1.DeletePropagationOrphan)
+	if !exists || err != nil {
+		t.Errorf("failed to cancel a run: %v", err)
+		t.FailNow()
+	}
+
+	if grandchild, _ := f.buffer.Get("test-grandchild", "test-child-algorithm"); grandchild.LifecycleStage != coremodels.LifecycleStageBuffered {
+		t.Errorf("expected descendants to be left alive with Orphan policy, but %s", grandchild.LifecycleStage)
+	}
+}
+
+func newFailingSubmissionFixture(t *testing.T, failures int) *schedulerFixture {
+	f := newSchedulerFixture(t, []runtime.Object{}, []runtime.Object{})
+	attempts := 0
+	f.shardClient.(*k8sfake.Clientset).PrependReactor("create", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		attempts++
+		if attempts <= failures {
+			return true, nil, errors.NewServiceUnavailable("shard is unavailable")
+		}
+
+		return false, nil, nil
+	})
+
+	// retries are resubmitted by the supervisor
+	f.scheduler.WithSupervisor(&models.SupervisorConfig{
+		Enabled:       true,
+		Interval:      time.Millisecond * 200,
+		GracePeriod:   time.Minute,
+		LeaseName:     "test-supervisor",
+		LeaseDuration: time.Second * 3,
+		RenewDeadline: time.Second * 2,
+		RetryPeriod:   time.Millisecond * 500,
+	})
+
+	return f
+}
+
+func submitTestRun(t *testing.T, f *schedulerFixture) {
+	scheduler, err := f.scheduler.Init(f.ctx)
+
+	if err != nil {
+		t.Errorf("scheduler init failed: %s", err)
+		t.FailNow()
+	}
+
+	go f.scheduler.Start(f.ctx)
+	go f.buffer.Start(&persistedRuns{buffer: f.buffer.(*request.MemoryPassthroughBuffer), next: scheduler.SchedulerActor})
+
+	time.Sleep(1 * time.Second)
+
+	if err := f.buffer.Add("test", "test-algorithm", newFakeRequest(), newFakeSpec(), newFakeWorkgroupSpec(), nil, false); err != nil {
+		t.Errorf("failed to buffer an element: %s", err)
+		t.FailNow()
+	}
+
+	// allow scheduling and retries to happen
+	time.Sleep(5 * time.Second)
+}
+
+func TestScheduler_RetrySchedulingFailure(t *testing.T) {
+	f := newFailingSubmissionFixture(t, 1)
+	submitTestRun(t, f)
+
+	checkpoint, _ := f.buffer.Get("test", "test-algorithm")
+	if checkpoint.LifecycleStage != coremodels.LifecycleStageRunning {
+		t.Errorf("The checkpoint lifecycle stage must be running after a retry, but %s", checkpoint.LifecycleStage)
+	}
+
+	attributes, _ := f.store.ReadAttributes("test-algorithm", "test")
+	if attributes == nil || attributes.SchedulingAttempts != 1 || attributes.LastSchedulingError == "" {
+		t.Errorf("expected a failed attempt to be recorded, but found %v", attributes)
+	}
+}
+
+func TestScheduler_RetryBudgetExhausted(t *testing.T) {
+	f := newFailingSubmissionFixture(t, 10)
+	submitTestRun(t, f)
+
+	checkpoint, _ := f.buffer.Get("test", "test-algorithm")
+	if checkpoint.LifecycleStage != coremodels.LifecycleStageSchedulingFailed {
+		t.Errorf("The checkpoint lifecycle stage must be scheduling failed, but %s", checkpoint.LifecycleStage)
+	}
+
+	attributes, _ := f.store.ReadAttributes("test-algorithm", "test")
+	if attributes == nil || attributes.SchedulingAttempts != 3 {
+		t.Errorf("expected 3 failed attempts to be recorded, but found %v", attributes)
+	}
+
+	f.expectEvents(t, EventReasonSubmissionFailed, EventReasonSubmissionFailed, EventReasonSchedulingFailed)
+}
+
+func TestScheduler_SupervisorRetriesFailedSubmission(t *testing.T) {
+	f := newSchedulerFixture(t, []runtime.Object{}, []runtime.Object{})
+	buffer := f.buffer.(*request.MemoryPassthroughBuffer)
+	host, _ := os.Hostname()
+
+	// a run with a failed attempt recorded by a scheduler instance that has been restarted since
+	checkpoint, _, _ := coremodels.FromAlgorithmRequest("test-retried-id", "test-algorithm", newFakeRequest(), newFakeSpec())
+	checkpoint.LifecycleStage = coremodels.LifecycleStageBuffered
+	checkpoint.ReceivedByHost = host
+	checkpoint.ReceivedAt = time.Now().Add(-time.Hour)
+	buffer.Checkpoints = append(buffer.Checkpoints, checkpoint)
+	buffer.BufferedEntries = append(buffer.BufferedEntries, coremodels.FromCheckpoint(checkpoint, newFakeWorkgroupSpec(), nil))
+
+	attributes := models.NewCheckpointAttributes("test-algorithm", "test-retried-id")
+	attributes.SchedulingAttempts = 1
+	attributes.LastSchedulingError = "shard is unavailable"
+	attributes.LastSchedulingAttempt = time.Now().Add(-time.Minute)
+	if recorded, _ := f.store.RecordSchedulingAttempt(attributes, 0); !recorded {
+		t.Errorf("expected the first attempt to be recorded")
+		t.FailNow()
+	}
+
+	if recorded, _ := f.store.RecordSchedulingAttempt(attributes, 0); recorded {
+		t.Errorf("expected an attempt based on an outdated attempt count to be rejected")
+	}
+
+	_, err := f.scheduler.WithSupervisor(&models.SupervisorConfig{
+		Enabled:       true,
+		Interval:      time.Second,
+		GracePeriod:   time.Minute,
+		LeaseName:     "test-supervisor",
+		LeaseDuration: time.Second * 3,
+		RenewDeadline: time.Second * 2,
+		RetryPeriod:   time.Millisecond * 500,
+	}).Init(f.ctx)
+
+	if err != nil {
+		t.Errorf("failed to initialize scheduler: %s", err)
+		t.FailNow()
+	}
+	go f.scheduler.Start(f.ctx)
+
+	// wait for leader election and a supervisor run
+	time.Sleep(5 * time.Second)
+
+	if retried, _ := f.buffer.Get("test-retried-id", "test-algorithm"); retried.LifecycleStage != coremodels.LifecycleStageRunning {
+		t.Errorf("expected the run to be resubmitted once its backoff has elapsed, but %s", retried.LifecycleStage)
+	}
+}
+
+func TestScheduler_Supervisor(t *testing.T) {
+	livePods := []corev1.Pod{
+		{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Pod",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-live-scheduler",
+				Namespace: "nexus",
+				Labels: map[string]string{
+					ComponentKey: ComponentName,
+				},
+			},
+			Spec: corev1.PodSpec{},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+			},
+		},
+	}
+
+	objects := []runtime.Object{}
+
+	for _, pod := range livePods {
+		objects = append(objects, &pod)
+	}
+
+	f := newSchedulerFixture(t, objects, []runtime.Object{})
+	buffer := f.buffer.(*request.MemoryPassthroughBuffer)
+
+	// BUFFERED submissions from a scheduler that no longer exists and from a live one
+	for id, host := range map[string]string{
+		"test-orphaned-id": "test-missing-scheduler",
+		"test-live-id":     "test-live-scheduler",
+	} {
+		checkpoint, _, _ := coremodels.FromAlgorithmRequest(id, "test-algorithm", newFakeRequest(), newFakeSpec())
+		checkpoint.LifecycleStage = coremodels.LifecycleStageBuffered
+		checkpoint.ReceivedByHost = host
+		checkpoint.ReceivedAt = time.Now().Add(-time.Hour)
+
+		buffer.Checkpoints = append(buffer.Checkpoints, checkpoint)
+		buffer.BufferedEntries = append(buffer.BufferedEntries, coremodels.FromCheckpoint(checkpoint, newFakeWorkgroupSpec(), nil))
+	}
+
+	_, err := f.scheduler.WithSupervisor(&models.SupervisorConfig{
+		Enabled:       true,
+		Interval:      time.Second,
+		GracePeriod:   time.Minute,
+		LeaseName:     "test-supervisor",
+		LeaseDuration: time.Second * 3,
+		RenewDeadline: time.Second * 2,
+		RetryPeriod:   time.Millisecond * 500,
+	}).Init(f.ctx)
+
+	if err != nil {
+		t.Errorf("failed to initialize scheduler: %s", err)
+		t.FailNow()
+	}
+	go f.scheduler.Start(f.ctx)
+
+	// wait for leader election and a supervisor run
+	time.Sleep(5 * time.Second)
+
+	if orphaned, _ := f.buffer.Get("test-orphaned-id", "test-algorithm"); orphaned.LifecycleStage != coremodels.LifecycleStageRunning {
+		t.Errorf("The orphaned checkpoint lifecycle stage must be running, but %s", orphaned.LifecycleStage)
+	}
+
+	if live, _ := f.buffer.Get("test-live-id", "test-algorithm"); live.LifecycleStage != coremodels.LifecycleStageBuffered {
+		t.Errorf("The checkpoint of a live scheduler must stay buffered, but %s", live.LifecycleStage)
+	}
+
+	if _, err := f.kubeClient.CoordinationV1().Leases("nexus").Get(f.ctx, "test-supervisor", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected supervisor lease to be acquired, but: %s", err)
+	}
+}
+
+func TestScheduler_SupervisorResumesCancellation(t *testing.T) {
+	f := newSchedulerFixture(t, []runtime.Object{}, []runtime.Object{})
+	buffer := f.buffer.(*request.MemoryPassthroughBuffer)
+
+	for id, stage := range map[string]string{
+		"test-cancelled-id": coremodels.LifecycleStageCancelled,
+		"test-remaining-id": coremodels.LifecycleStageBuffered,
+	} {
+		checkpoint, _, _ := coremodels.FromAlgorithmRequest(id, "test-algorithm", newFakeRequest(), newFakeSpec())
+		checkpoint.LifecycleStage = stage
+		checkpoint.Tag = "test-tag"
+		buffer.Checkpoints = append(buffer.Checkpoints, checkpoint)
+	}
+
+	// an operation interrupted by a scheduler that no longer exists, after it has cancelled one run
+	interrupted := models.NewCancellationOperation("test-interrupted", "tester", "test", string(metav1.DeletePropagationBackground))
+	interrupted.Tag = "test-tag"
+	interrupted.LifecycleStages = DefaultCancellationStages
+	interrupted.Host = "test-missing-scheduler"
+	interrupted.Status = models.CancellationOperationRunning
+	interrupted.Matched = 1
+	interrupted.Cancelled = 1
+	_ = f.store.UpsertCancellationOperation(interrupted)
+
+	_, err := f.scheduler.WithSupervisor(&models.SupervisorConfig{
+		Enabled:       true,
+		Interval:      time.Second,
+		GracePeriod:   time.Minute,
+		LeaseName:     "test-supervisor",
+		LeaseDuration: time.Second * 3,
+		RenewDeadline: time.Second * 2,
+		RetryPeriod:   time.Millisecond * 500,
+	}).Init(f.ctx)
+
+	if err != nil {
+		t.Errorf("failed to initialize scheduler: %s", err)
+		t.FailNow()
+	}
+	go f.scheduler.Start(f.ctx)
+
+	// wait for leader election and a supervisor run
+	time.Sleep(5 * time.Second)
+
+	progress, _ := f.scheduler.GetCancellationOperation("test-interrupted")
+	if progress == nil || progress.Status != models.CancellationOperationCompleted || progress.Matched != 2 || progress.Cancelled != 2 {
+		t.Errorf("expected the interrupted operation to be resumed and completed with 2 cancelled runs, but found %v", progress)
+		t.FailNow()
+	}
+
+	if host, _ := os.Hostname(); progress.Host != host {
+		t.Errorf("expected the operation to be taken over by %s, but it is held by %s", host, progress.Host)
+	}
+
+	if checkpoint, _ := f.buffer.Get("test-remaining-id", "test-algorithm"); checkpoint.LifecycleStage != coremodels.LifecycleStageCancelled {
+		t.Errorf("expected the remaining run to be cancelled, but %s", checkpoint.LifecycleStage)
+	}
+}
+
+func TestScheduler_ClaimRecovery(t *testing.T) {
+	f := newSchedulerFixture(t, []runtime.Object{}, []runtime.Object{})
+
+	if claimed, err := f.scheduler.claimRecovery("test-terminated-scheduler"); !claimed || err != nil {
+		t.Errorf("expected recovery to be claimed, but: %v", err)
+		t.FailNow()
+	}
+
+	if claimed, _ := f.scheduler.claimRecovery("test-terminated-scheduler"); claimed {
+		t.Errorf("expected recovery claimed by another instance to be rejected")
+		t.FailNow()
+	}
+
+	// expire the claim
+	leases := f.kubeClient.CoordinationV1().Leases("nexus")
+	lease, _ := leases.Get(f.ctx, RecoveryLeasePrefix+"test-terminated-scheduler", metav1.GetOptions{})
+	expired := metav1.NewMicroTime(time.Now().Add(-time.Hour))
+	lease.Spec.RenewTime = &expired
+	_, _ = leases.Update(f.ctx, lease, metav1.UpdateOptions{})
+
+	if claimed, err := f.scheduler.claimRecovery("test-terminated-scheduler"); !claimed || err != nil {
+		t.Errorf("expected expired recovery claim to be taken over, but: %v", err)
+		t.FailNow()
+	}
+
+	// expire again and clean up
+	lease, _ = leases.Get(f.ctx, RecoveryLeasePrefix+"test-terminated-scheduler", metav1.GetOptions{})
+	lease.Spec.RenewTime = &expired
+	_, _ = leases.Update(f.ctx, lease, metav1.UpdateOptions{})
+
+	if err := f.scheduler.cleanupRecoveryClaims(); err != nil {
+		t.Errorf("failed to clean up recovery claims: %s", err)
+		t.FailNow()
+	}
+
+	if _, err := leases.Get(f.ctx, RecoveryLeasePrefix+"test-terminated-scheduler", metav1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("expected expired recovery claim to be deleted, but: %v", err)
+	}
+}
+
+func TestScheduler_LateSubmissionAlreadyExists(t *testing.T) {
+	jobs := []batchv1.Job{
+		{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Job",
+				APIVersion: "batch/v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-id",
+				Namespace: "nexus",
+				UID:       "test-id-uid",
+			},
+			Spec: batchv1.JobSpec{},
+		},
+	}
+
+	objects := []runtime.Object{}
+
+	for _, job := range jobs {
+		objects = append(objects, &job)
+	}
+
+	f := newSchedulerFixture(t, []runtime.Object{}, objects)
+
+	// the Job has been created by another scheduler instance
+	buffer := f.buffer.(*request.MemoryPassthroughBuffer)
+	checkpoint, _, _ := coremodels.FromAlgorithmRequest("test-id", "test-algorithm", newFakeRequest(), newFakeSpec())
+	checkpoint.LifecycleStage = coremodels.LifecycleStageBuffered
+	entry := coremodels.FromCheckpoint(checkpoint, newFakeWorkgroupSpec(), nil)
+	buffer.Checkpoints = append(buffer.Checkpoints, checkpoint)
+
+	submitted, err := f.scheduler.lateSchedule(&LateSubmission{
+		Checkpoint:    checkpoint,
+		BufferedEntry: entry,
+	})
+
+	if err != nil {
+		t.Errorf("expected an existing Job to be treated as a successful submission, but: %s", err)
+		t.FailNow()
+	}
+
+	if submitted.Checkpoint.JobUid != "test-id-uid" {
+		t.Errorf("expected existing Job uid to be recorded, but found '%s'", submitted.Checkpoint.JobUid)
+	}
+}
+
+func TestScheduler_QueueDeadlineExceeded(t *testing.T) {
+	f := newSchedulerFixture(t, []runtime.Object{}, []runtime.Object{})
+
+	if err := f.scheduler.SetQueueDeadline("test", "test-algorithm", time.Now().Add(-time.Minute)); err != nil {
+		t.Errorf("failed to set a queue deadline: %s", err)
+		t.FailNow()
+	}
+
+	submitTestRun(t, f)
+
+	checkpoint, _ := f.buffer.Get("test", "test-algorithm")
+	if checkpoint.LifecycleStage != coremodels.LifecycleStageDeadlineExceeded {
+		t.Errorf("The checkpoint lifecycle stage must be deadline exceeded, but %s", checkpoint.LifecycleStage)
+	}
+
+	if _, err := f.shardClient.BatchV1().Jobs("nexus").Get(f.ctx, "test", metav1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("expected no job to be created for an expired run, but found: %v", err)
+	}
+
+	f.expectEvents(t, EventReasonQueueDeadlineExceeded)
+}
+
+func TestScheduler_QueueDeadlineNotReached(t *testing.T) {
+	f := newSchedulerFixture(t, []runtime.Object{}, []runtime.Object{})
+
+	if err := f.scheduler.SetQueueDeadline("test", "test-algorithm", time.Now().Add(time.Hour)); err != nil {
+		t.Errorf("failed to set a queue deadline: %s", err)
+		t.FailNow()
+	}
+
+	submitTestRun(t, f)
+
+	checkpoint, _ := f.buffer.Get("test", "test-algorithm")
+	if checkpoint.LifecycleStage != coremodels.LifecycleStageRunning {
+		t.Errorf("The checkpoint lifecycle stage must be running, but %s", checkpoint.LifecycleStage)
+	}
+}
+
+func TestMaxQueueTime(t *testing.T) {
+	template := &v1.NexusAlgorithmTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-algorithm",
+			Annotations: map[string]string{MaxQueueTimeAnnotation: "10m"},
+		},
+	}
+
+	templateLimit, err := TemplateMaxQueueTime(template)
+	if err != nil || templateLimit != 10*time.Minute {
+		t.Errorf("expected template limit of 10m, but got %s (%v)", templateLimit, err)
+	}
+
+	cases := []struct {
+		template time.Duration
+		request  time.Duration
+		expected time.Duration
+	}{
+		{0, 0, 0},
+		{0, time.Minute, time.Minute},
+		{templateLimit, 0, templateLimit},
+		{templateLimit, time.Minute, time.Minute},
+		{templateLimit, time.Hour, templateLimit},
+	}
+
+	for _, c := range cases {
+		if result := EffectiveMaxQueueTime(c.template, c.request); result != c.expected {
+			t.Errorf("expected effective limit of %s for template %s and request %s, but got %s", c.expected, c.template, c.request, result)
+		}
+	}
+
+	template.Annotations[MaxQueueTimeAnnotation] = "soon"
+	if _, err := TemplateMaxQueueTime(template); err == nil {
+		t.Errorf("expected an invalid annotation to be rejected")
+	}
+}
+
+func submitReconciledTestRun(t *testing.T, condition batchv1.JobCondition, pods []corev1.Pod) *coremodels.CheckpointedRequest {
+	f := newSchedulerFixture(t, []runtime.Object{}, []runtime.Object{})
+	f.scheduler.
+		WithShardKubeClients(map[string]kubernetes.Interface{"test-shard": f.shardClient}).
+		WithJobReconciliation(&models.JobReconciliationConfig{Enabled: true, ResyncPeriod: time.Second})
+
+	submitTestRun(t, f)
+
+	for _, pod := range pods {
+		if _, err := f.shardClient.CoreV1().Pods("nexus").Create(f.ctx, &pod, metav1.CreateOptions{}); err != nil {
+			t.Errorf("failed to create a job pod: %s", err)
+			t.FailNow()
+		}
+	}
+
+	job, err := f.shardClient.BatchV1().Jobs("nexus").Get(f.ctx, "test", metav1.GetOptions{})
+	if err != nil {
+		t.Errorf("expected a job to be submitted, but got: %s", err)
+		t.FailNow()
+	}
+
+	job.Status.Conditions = append(job.Status.Conditions, condition)
+	if _, err := f.shardClient.BatchV1().Jobs("nexus").UpdateStatus(f.ctx, job, metav1.UpdateOptions{}); err != nil {
+		t.Errorf("failed to update job status: %s", err)
+		t.FailNow()
+	}
+
+	time.Sleep(2 * time.Second)
+
+	checkpoint, _ := f.buffer.Get("test", "test-algorithm")
+	return checkpoint
+}
+
+func TestScheduler_ReconcileCompletedJob(t *testing.T) {
+	checkpoint := submitReconciledTestRun(t, batchv1.JobCondition{
+		Type:   batchv1.JobComplete,
+		Status: corev1.ConditionTrue,
+	}, []corev1.Pod{})
+
+	if checkpoint.LifecycleStage != coremodels.LifecycleStageCompleted {
+		t.Errorf("The checkpoint lifecycle stage must be completed, but %s", checkpoint.LifecycleStage)
+	}
+}
+
+func TestScheduler_ReconcileFailedJob(t *testing.T) {
+	checkpoint := submitReconciledTestRun(t, batchv1.JobCondition{
+		Type:    batchv1.JobFailed,
+		Status:  corev1.ConditionTrue,
+		Reason:  batchv1.JobReasonBackoffLimitExceeded,
+		Message: "Job has reached the specified backoff limit",
+	}, []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-abcde",
+				Namespace: "nexus",
+				Labels:    map[string]string{JobNameLabel: "test"},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodFailed,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name: "test",
+						State: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{
+								ExitCode: 137,
+								Reason:   "OOMKilled",
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	if checkpoint.LifecycleStage != coremodels.LifecycleStageFailed {
+		t.Errorf("The checkpoint lifecycle stage must be failed, but %s", checkpoint.LifecycleStage)
+	}
+
+	if checkpoint.AlgorithmFailureCause != "Job failed: BackoffLimitExceeded" || !strings.Contains(checkpoint.AlgorithmFailureDetails, "test-abcde/test: OOMKilled (exit code 137)") {
+		t.Errorf("expected pod failure reasons to be recorded, but found: %s, %s", checkpoint.AlgorithmFailureCause, checkpoint.AlgorithmFailureDetails)
+	}
+}
+
+func TestScheduler_ReconcileDeadlineExceededJob(t *testing.T) {
+	checkpoint := submitReconciledTestRun(t, batchv1.JobCondition{
+		Type:    batchv1.JobFailed,
+		Status:  corev1.ConditionTrue,
+		Reason:  batchv1.JobReasonDeadlineExceeded,
+		Message: "Job was active longer than specified deadline",
+	}, []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-fghij",
+				Namespace: "nexus",
+				Labels:    map[string]string{JobNameLabel: "test"},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name: "test",
+						State: corev1.ContainerState{
+							Waiting: &corev1.ContainerStateWaiting{
+								Reason: "ImagePullBackOff",
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	if checkpoint.LifecycleStage != coremodels.LifecycleStageDeadlineExceeded {
+		t.Errorf("The checkpoint lifecycle stage must be deadline exceeded, but %s", checkpoint.LifecycleStage)
+	}
+
+	if !strings.Contains(checkpoint.AlgorithmFailureDetails, "ImagePullBackOff") {
+		t.Errorf("expected pod failure reasons to be recorded, but found: %s", checkpoint.AlgorithmFailureDetails)
+	}
+}
+
+func TestScheduler_StreamRunLogs(t *testing.T) {
+	f := newSchedulerFixture(t, []runtime.Object{}, []runtime.Object{})
+	f.scheduler.WithShardKubeClients(map[string]kubernetes.Interface{"test-shard": f.shardClient})
+
+	submitTestRun(t, f)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-abcde",
+			Namespace: "nexus",
+			Labels:    map[string]string{JobNameLabel: "test"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "test"}},
+		},
+	}
+
+	if _, err := f.shardClient.CoreV1().Pods("nexus").Create(f.ctx, pod, metav1.CreateOptions{}); err != nil {
+		t.Errorf("failed to create a job pod: %s", err)
+		t.FailNow()
+	}
+
+	output := &bytes.Buffer{}
+	if err := f.scheduler.StreamRunLogs(f.ctx, "test", "test-algorithm", &corev1.PodLogOptions{TailLines: ptr.Int64(10)}, output); err != nil {
+		t.Errorf("failed to read run logs: %s", err)
+		t.FailNow()
+	}
+
+	if !strings.HasPrefix(output.String(), "==> test-abcde/test <==\nfake logs") {
+		t.Errorf("unexpected log output: %s", output.String())
+	}
+}
+
+func TestScheduler_StreamRunLogsNotFound(t *testing.T) {
+	f := newSchedulerFixture(t, []runtime.Object{}, []runtime.Object{})
+	f.scheduler.WithShardKubeClients(map[string]kubernetes.Interface{"test-shard": f.shardClient})
+
+	if err := f.scheduler.StreamRunLogs(f.ctx, "test", "test-algorithm", &corev1.PodLogOptions{}, &bytes.Buffer{}); !goerrors.Is(err, ErrRunPodsNotFound) {
+		t.Errorf("expected no pods to be found for a missing run, but got: %v", err)
+	}
+}
+
+func TestScheduler_GetRunTimeline(t *testing.T) {
+	f := newSchedulerFixture(t, []runtime.Object{}, []runtime.Object{})
+	f.scheduler.WithShardKubeClients(map[string]kubernetes.Interface{"test-shard": f.shardClient})
+
+	submitTestRun(t, f)
+
+	objects := []runtime.Object{
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-abcde", Namespace: "nexus", Labels: map[string]string{JobNameLabel: "test"}},
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "test.1", Namespace: "nexus"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "test-abcde"},
+			Reason:         "Failed",
+			Type:           corev1.EventTypeWarning,
+			Message:        "Back-off pulling image",
+			LastTimestamp:  metav1.NewTime(time.Now().Add(time.Minute * 2)),
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "test.2", Namespace: "nexus"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Job", Name: "test"},
+			Reason:         "SuccessfulCreate",
+			Type:           corev1.EventTypeNormal,
+			LastTimestamp:  metav1.NewTime(time.Now().Add(time.Minute)),
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "other.1", Namespace: "nexus"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Job", Name: "other"},
+			Reason:         "SuccessfulCreate",
+			LastTimestamp:  metav1.NewTime(time.Now()),
+		},
+	}
+
+	for _, object := range objects {
+		if err := f.shardClient.(*k8sfake.Clientset).Tracker().Add(object); err != nil {
+			t.Errorf("failed to add a shard object: %s", err)
+			t.FailNow()
+		}
+	}
+
+	timeline, err := f.scheduler.GetRunTimeline(f.ctx, "test", "test-algorithm")
+	if err != nil {
+		t.Errorf("failed to read a run timeline: %s", err)
+		t.FailNow()
+	}
+
+	expected := []string{
+		coremodels.LifecycleStageNew,
+		coremodels.LifecycleStageBuffered,
+		coremodels.LifecycleStageRunning,
+		"SuccessfulCreate",
+		"Failed",
+	}
+
+	if len(timeline) != len(expected) {
+		t.Errorf("expected %d timeline events, but got %d", len(expected), len(timeline))
+		t.FailNow()
+	}
+
+	for index, event := range timeline {
+		if event.Reason != expected[index] {
+			t.Errorf("expected timeline event %d to be %s, but got %s", index, expected[index], event.Reason)
+		}
+	}
+
+	if timeline[4].Object != "Pod/test-abcde" || timeline[4].Source != models.TimelineSourceKubernetes {
+		t.Errorf("expected the last event to come from the run pod, but got %s from %s", timeline[4].Object, timeline[4].Source)
+	}
+
+	if timeline[1].Time != nil {
+		t.Errorf("expected the buffered transition to have no time, but got %s", timeline[1].Time)
+	}
+}
+
+func TestScheduler_GetRunTimelineMissingRun(t *testing.T) {
+	f := newSchedulerFixture(t, []runtime.Object{}, []runtime.Object{})
+
+	if timeline, err := f.scheduler.GetRunTimeline(f.ctx, "test", "test-algorithm"); timeline != nil || err != nil {
+		t.Errorf("expected no timeline for a missing run, but got %v (%v)", timeline, err)
+	}
+}
+
+func TestScheduler_SetPayloadReference(t *testing.T) {
+	f := newSchedulerFixture(t, []runtime.Object{}, []runtime.Object{})
+
+	if err := f.scheduler.SetQueueDeadline("test", "test-algorithm", time.Now().Add(time.Hour)); err != nil {
+		t.Errorf("failed to set a queue deadline: %s", err)
+		t.FailNow()
+	}
+
+	if err := f.scheduler.SetPayloadReference("test", "test-algorithm", &models.PayloadObject{Path: "s3a://bucket/payload", ContentHash: "abc", Size: 10}); err != nil {
+		t.Errorf("failed to set a payload reference: %s", err)
+		t.FailNow()
+	}
+
+	attributes, _ := f.store.ReadAttributes("test-algorithm", "test")
+	if attributes.PayloadReference != "s3a://bucket/payload" || attributes.PayloadContentHash != "abc" || attributes.PayloadSize != 10 || attributes.QueueDeadline.IsZero() {
+		t.Errorf("expected the payload reference to be recorded next to other attributes, but got %v", attributes)
+	}
+}
+
+func TestContentHash(t *testing.T) {
+	spec := newFakeSpec()
+	reference := func(uri string) map[string]interface{} {
+		return map[string]interface{}{
+			PayloadReferenceParameter: map[string]interface{}{"uri": uri, "contentHash": "abc", "size": 10},
+		}
+	}
+
+	first, err := ContentHash(reference("https://bucket/payload?signature=1"), spec)
+	if err != nil {
+		t.Errorf("failed to compute a content hash: %s", err)
+		t.FailNow()
+	}
+
+	if second, _ := ContentHash(reference("https://bucket/payload?signature=2"), spec); first != second {
+		t.Errorf("expected pre-signed URLs to be excluded from the content hash")
+	}
+
+	changedSpec := spec.DeepCopy()
+	changedSpec.Container.VersionTag = "other"
+	if changed, _ := ContentHash(reference("https://bucket/payload?signature=1"), changedSpec); changed == first {
+		t.Errorf("expected configuration to be included in the content hash")
+	}
+
+	if changed, _ := ContentHash(map[string]interface{}{"a": 1}, spec); changed == first {
+		t.Errorf("expected parameters to be included in the content hash")
+	}
+}
+
+func TestResultCacheTtl(t *testing.T) {
+	template := &v1.NexusAlgorithmTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-algorithm",
+			Annotations: map[string]string{},
+		},
+	}
+
+	if ttl, err := TemplateResultCacheTtl(template); ttl != 0 || err != nil {
+		t.Errorf("expected the result cache to be disabled by default, but got %s (%v)", ttl, err)
+	}
+
+	template.Annotations[ResultCacheTtlAnnotation] = "24h"
+	if ttl, err := TemplateResultCacheTtl(template); ttl != 24*time.Hour || err != nil {
+		t.Errorf("expected a result cache ttl of 24h, but got %s (%v)", ttl, err)
+	}
+
+	template.Annotations[ResultCacheTtlAnnotation] = "forever"
+	if _, err := TemplateResultCacheTtl(template); err == nil {
+		t.Errorf("expected an invalid annotation to be rejected")
+	}
+}
+
+func TestScheduler_ResultCache(t *testing.T) {
+	f := newSchedulerFixture(t, []runtime.Object{}, []runtime.Object{})
+	memoryBuffer := f.buffer.(*request.MemoryPassthroughBuffer)
+
+	if cached, err := f.scheduler.LookupCachedResult("test-algorithm", "hash", time.Hour); cached != nil || err != nil {
+		t.Errorf("expected a miss for an unknown content hash, but got %v (%v)", cached, err)
+	}
+
+	if err := f.scheduler.RecordCacheMiss("source", "test-algorithm", "hash"); err != nil {
+		t.Errorf("failed to record a cache miss: %s", err)
+		t.FailNow()
+	}
+
+	source, _, _ := coremodels.FromAlgorithmRequest("source", "test-algorithm", newFakeRequest(), newFakeSpec())
+	source.LifecycleStage = coremodels.LifecycleStageRunning
+	memoryBuffer.Checkpoints = append(memoryBuffer.Checkpoints, source)
+
+	if cached, _ := f.scheduler.LookupCachedResult("test-algorithm", "hash", time.Hour); cached != nil {
+		t.Errorf("expected a miss while the source run is not completed, but got %v", cached)
+	}
+
+	source.LifecycleStage = coremodels.LifecycleStageCompleted
+	source.ResultUri = "https://bucket/result"
+
+	cached, err := f.scheduler.LookupCachedResult("test-algorithm", "hash", time.Hour)
+	if cached == nil || err != nil {
+		t.Errorf("expected a hit for a completed run, but got %v", err)
+		t.FailNow()
+	}
+
+	// the memory buffer only updates existing checkpoints
+	memoryBuffer.Checkpoints = append(memoryBuffer.Checkpoints, &coremodels.CheckpointedRequest{Id: "cached", Algorithm: "test-algorithm"})
+	if err := f.scheduler.CompleteFromCache("cached", "test-algorithm", newFakeRequest(), newFakeSpec(), cached, "hash"); err != nil {
+		t.Errorf("failed to complete a run from the cache: %s", err)
+		t.FailNow()
+	}
+
+	checkpoint, _ := f.buffer.Get("cached", "test-algorithm")
+	if checkpoint.LifecycleStage != coremodels.LifecycleStageCompleted || checkpoint.ResultUri != "https://bucket/result" || checkpoint.ContentHash != "hash" {
+		t.Errorf("expected a cached run to resolve to the source result, but got %v", checkpoint)
+	}
+
+	attributes, _ := f.store.ReadAttributes("test-algorithm", "cached")
+	if attributes.ResultCache != models.ResultCacheHit || attributes.ResultCacheSource != "source" {
+		t.Errorf("expected a cache hit to be recorded, but got %v", attributes)
+	}
+
+	attributes, _ = f.store.ReadAttributes("test-algorithm", "source")
+	if attributes.ResultCache != models.ResultCacheMiss || attributes.ContentHash != "hash" {
+		t.Errorf("expected a cache miss to be recorded, but got %v", attributes)
+	}
+
+	// a later run with the same content must not hide the result of the source run while it is in flight, or once it fails
+	if err := f.scheduler.RecordCacheMiss("later", "test-algorithm", "hash"); err != nil {
+		t.Errorf("failed to record a cache miss: %s", err)
+		t.FailNow()
+	}
+
+	later, _, _ := coremodels.FromAlgorithmRequest("later", "test-algorithm", newFakeRequest(), newFakeSpec())
+	later.LifecycleStage = coremodels.LifecycleStageRunning
+	memoryBuffer.Checkpoints = append(memoryBuffer.Checkpoints, later)
+
+	for _, stage := range []string{coremodels.LifecycleStageRunning, coremodels.LifecycleStageFailed} {
+		later.LifecycleStage = stage
+		if cached, _ := f.scheduler.LookupCachedResult("test-algorithm", "hash", time.Hour); cached == nil || cached.Id != "source" {
+			t.Errorf("expected the source run to remain cached while a later run is %s, but got %v", stage, cached)
+		}
+	}
+
+	source.LastModified = time.Now().Add(-2 * time.Hour)
+	if cached, _ := f.scheduler.LookupCachedResult("test-algorithm", "hash", time.Hour); cached != nil {
+		t.Errorf("expected a miss for a result older than the ttl, but got %v", cached)
+	}
+}
+
+func TestScheduler_WatchRun(t *testing.T) {
+	f := newSchedulerFixture(t, []runtime.Object{}, []runtime.Object{})
+	memoryBuffer := f.buffer.(*request.MemoryPassthroughBuffer)
+
+	checkpoint, _, _ := coremodels.FromAlgorithmRequest("watched", "test-algorithm", newFakeRequest(), newFakeSpec())
+	checkpoint.LifecycleStage = coremodels.LifecycleStageRunning
+	memoryBuffer.Checkpoints = append(memoryBuffer.Checkpoints, checkpoint)
+
+	stages := make(chan string, 10)
+	done := make(chan error)
+	go func() {
+		done <- f.scheduler.WatchRun(f.ctx, "watched", "test-algorithm", time.Millisecond*10, func(update *coremodels.CheckpointedRequest) error {
+			stages <- update.LifecycleStage
+			return nil
+		})
+	}()
+
+	if stage := <-stages; stage != coremodels.LifecycleStageRunning {
+		t.Errorf("expected the current stage to be sent first, but got %s", stage)
+	}
+
+	finished := checkpoint.DeepCopy()
+	finished.LifecycleStage = coremodels.LifecycleStageCompleted
+	finished.LastModified = time.Now()
+	_ = f.buffer.Update(finished)
+
+	if err := <-done; err != nil {
+		t.Errorf("expected the watch to complete once the run has finished, but got %s", err)
+	}
+
+	close(stages)
+	received := []string{}
+	for stage := range stages {
+		received = append(received, stage)
+	}
+
+	if len(received) != 1 || received[0] != coremodels.LifecycleStageCompleted {
+		t.Errorf("expected a single update for the finished run, but got %v", received)
+	}
+
+	if err := f.scheduler.WatchRun(f.ctx, "missing", "test-algorithm", time.Millisecond*10, func(*coremodels.CheckpointedRequest) error { return nil }); !goerrors.Is(err, ErrRunNotFound) {
+		t.Errorf("expected watching a missing run to fail, but got %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"fmt"
+	coremodels "github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
+	"github.com/SneaksAndData/nexus/services/models"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"time"
+)
+
+// isRetryable returns false for submission errors that will not resolve on their own
+func isRetryable(err error) bool {
+	return !errors.IsInvalid(err) && !errors.IsBadRequest(err) && !errors.IsForbidden(err)
+}
+
+// retriesEnabled returns true if failed submissions can be retried. Retries are resubmitted by the supervisor loop, so they are not lost if this scheduler instance is terminated
+func (scheduler *RequestScheduler) retriesEnabled() bool {
+	return scheduler.supervisorConfig != nil && scheduler.supervisorConfig.Enabled
+}
+
+// retryOrFail records a failed submission attempt and either leaves the run BUFFERED for a retry or marks it SCHEDULING_FAILED once the retry budget is exhausted
+func (scheduler *RequestScheduler) retryOrFail(checkpoint *coremodels.CheckpointedRequest, entry *coremodels.SubmissionBufferEntry, submitErr error) error {
+	attributes, err := scheduler.store.ReadAttributes(checkpoint.Algorithm, checkpoint.Id)
+	if err != nil { // coverage-ignore
+		return err
+	}
+
+	if attributes == nil {
+		attributes = models.NewCheckpointAttributes(checkpoint.Algorithm, checkpoint.Id)
+	}
+
+	previousAttempts := attributes.SchedulingAttempts
+	attributes.SchedulingAttempts++
+	attributes.LastSchedulingError = submitErr.Error()
+	attributes.LastSchedulingAttempt = time.Now()
+
+	recorded, err := scheduler.store.RecordSchedulingAttempt(attributes, previousAttempts)
+	if err != nil { // coverage-ignore
+		return err
+	}
+
+	if !recorded { // coverage-ignore
+		scheduler.logger.V(0).Info("submission failed, but another attempt has been recorded concurrently - leaving the retry to its owner", "request", checkpoint.Id, "template", checkpoint.Algorithm, "error", submitErr.Error())
+		scheduler.completeRecovery(checkpoint)
+		return submitErr
+	}
+
+	policy := scheduler.retryConfig.PolicyFor(checkpoint.Algorithm)
+	if isRetryable(submitErr) && entry != nil && scheduler.retriesEnabled() && attributes.SchedulingAttempts < policy.MaxAttempts {
+		delay := policy.Delay(attributes.SchedulingAttempts)
+		scheduler.logger.V(0).Info("submission failed - retrying", "request", checkpoint.Id, "template", checkpoint.Algorithm, "attempt", attributes.SchedulingAttempts, "retryIn", delay, "error", submitErr.Error())
+		scheduler.recordTemplateEvent(checkpoint.Algorithm, corev1.EventTypeWarning, EventReasonSubmissionFailed, "Submission of run %s failed (attempt %d of %d), retrying in %s: %s", checkpoint.Id, attributes.SchedulingAttempts, policy.MaxAttempts, delay, submitErr.Error())
+
+		// run stays BUFFERED until the supervisor picks it up once the backoff has elapsed
+		scheduler.completeRecovery(checkpoint)
+
+		return submitErr
+	}
+
+	scheduler.logger.V(0).Info("submission failed - retry budget exhausted, marking as SCHEDULING_FAILED", "request", checkpoint.Id, "template", checkpoint.Algorithm, "attempts", attributes.SchedulingAttempts)
+
+	scheduler.completeRecovery(checkpoint)
+
+	failed := checkpoint.DeepCopy()
+	failed.LifecycleStage = coremodels.LifecycleStageSchedulingFailed
+	failed.AlgorithmFailureCause = fmt.Sprintf("Scheduling failed after %d attempt(s)", attributes.SchedulingAttempts)
+	failed.AlgorithmFailureDetails = submitErr.Error()
+
+	if err := scheduler.buffer.Update(failed); err != nil { // coverage-ignore
+		return err
+	}
+
+	scheduler.recordTemplateEvent(checkpoint.Algorithm, corev1.EventTypeWarning, EventReasonSchedulingFailed, "Run %s could not be scheduled after %d attempt(s): %s", checkpoint.Id, attributes.SchedulingAttempts, submitErr.Error())
+
+	return submitErr
+}
+
+// retryFailedSubmissions resubmits BUFFERED runs with failed submission attempts once their retry backoff has elapsed. Backoff is derived from the persisted attempt count and time of the last attempt
+func (scheduler *RequestScheduler) retryFailedSubmissions() error {
+	checkpoints, err := scheduler.store.ReadCheckpointsInStage(coremodels.LifecycleStageBuffered)
+	if err != nil { // coverage-ignore
+		return err
+	}
+
+	for checkpoint, err := range checkpoints {
+		if err != nil { // coverage-ignore
+			return err
+		}
+
+		attributes, err := scheduler.store.ReadAttributes(checkpoint.Algorithm, checkpoint.Id)
+		if err != nil { // coverage-ignore
+			return err
+		}
+
+		if attributes == nil || attributes.SchedulingAttempts == 0 {
+			continue
+		}
+
+		policy := scheduler.retryConfig.PolicyFor(checkpoint.Algorithm)
+		if time.Since(attributes.LastSchedulingAttempt) < policy.Delay(attributes.SchedulingAttempts) {
+			continue
+		}
+
+		entry, err := scheduler.buffer.GetBufferedEntry(checkpoint)
+		if err != nil { // coverage-ignore
+			utilruntime.HandleError(err)
+			continue
+		}
+
+		if _, loaded := scheduler.recovering.LoadOrStore(recoveryKey(checkpoint), true); loaded {
+			continue
+		}
+
+		scheduler.logger.V(0).Info("retry backoff elapsed - resubmitting", "request", checkpoint.Id, "template", checkpoint.Algorithm, "attempt", attributes.SchedulingAttempts+1)
+		scheduler.LateSubmissionActor.Receive(&LateSubmission{
+			Checkpoint:    checkpoint,
+			BufferedEntry: entry,
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	coremodels "github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
+	"github.com/SneaksAndData/nexus/services/models"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"os"
+	"time"
+)
+
+// WithSupervisor enables a leader-elected loop that recovers BUFFERED and NEW requests received by scheduler instances that are no longer running, and resumes their bulk cancellations
+func (scheduler *RequestScheduler) WithSupervisor(config *models.SupervisorConfig) *RequestScheduler {
+	scheduler.supervisorConfig = config
+	return scheduler
+}
+
+// liveHosts returns names of scheduler instances that are running or about to start
+func (scheduler *RequestScheduler) liveHosts() map[string]bool {
+	hosts := map[string]bool{}
+	for _, obj := range scheduler.podInformer.GetStore().List() {
+		pod := obj.(*corev1.Pod)
+		if pod.Labels[ComponentKey] == ComponentName && (pod.Status.Phase == corev1.PodRunning || pod.Status.Phase == corev1.PodPending) {
+			hosts[pod.Name] = true
+		}
+	}
+
+	// this instance is always alive, even if it is not deployed as a pod
+	if podName, err := os.Hostname(); err == nil {
+		hosts[podName] = true
+	}
+
+	return hosts
+}
+
+// findOrphanedHosts returns hosts that hold BUFFERED or NEW requests older than the grace period, but are not running anymore
+func (scheduler *RequestScheduler) findOrphanedHosts() (map[string]bool, error) {
+	live := scheduler.liveHosts()
+	orphaned := map[string]bool{}
+
+	for _, stage := range []string{coremodels.LifecycleStageBuffered, coremodels.LifecycleStageNew} {
+		checkpoints, err := scheduler.store.ReadCheckpointsInStage(stage)
+		if err != nil { // coverage-ignore
+			return nil, err
+		}
+
+		for checkpoint, err := range checkpoints {
+			if err != nil { // coverage-ignore
+				return nil, err
+			}
+
+			if checkpoint.ReceivedByHost == "" || live[checkpoint.ReceivedByHost] {
+				continue
+			}
+
+			if time.Since(checkpoint.ReceivedAt) > scheduler.supervisorConfig.GracePeriod {
+				orphaned[checkpoint.ReceivedByHost] = true
+			}
+		}
+	}
+
+	return orphaned, nil
+}
+
+// superviseOrphans recovers requests and bulk cancellations left over by terminated scheduler instances, in case the termination event has been missed, and retries failed submissions
+func (scheduler *RequestScheduler) superviseOrphans(_ context.Context) {
+	if !scheduler.podInformer.HasSynced() { // coverage-ignore
+		scheduler.logger.V(1).Info("pod informer has not synced yet - skipping orphaned request check")
+		return
+	}
+
+	if err := scheduler.cleanupRecoveryClaims(); err != nil { // coverage-ignore
+		utilruntime.HandleError(err)
+	}
+
+	if err := scheduler.resumeCancellations(scheduler.liveHosts()); err != nil { // coverage-ignore
+		utilruntime.HandleError(err)
+	}
+
+	if err := scheduler.retryFailedSubmissions(); err != nil { // coverage-ignore
+		utilruntime.HandleError(err)
+	}
+
+	hosts, err := scheduler.findOrphanedHosts()
+	if err != nil { // coverage-ignore
+		utilruntime.HandleError(err)
+		return
+	}
+
+	for host := range hosts {
+		scheduler.logger.V(0).Info("discovered requests left over by a scheduler that is not running", "instance", host)
+		scheduler.recoverHost(host)
+	}
+}
+
+// runSupervisor participates in the supervisor leader election until the context is cancelled. Only the leader executes the supervisor loop.
+func (scheduler *RequestScheduler) runSupervisor(ctx context.Context) {
+	identity, err := os.Hostname()
+	if err != nil { // coverage-ignore
+		utilruntime.HandleError(err)
+		return
+	}
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      scheduler.supervisorConfig.LeaseName,
+			Namespace: scheduler.deployNamespace,
+		},
+		Client: scheduler.kubeClient.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: identity,
+		},
+	}
+
+	for ctx.Err() == nil {
+		leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
+			Lock:            lock,
+			LeaseDuration:   scheduler.supervisorConfig.LeaseDuration,
+			RenewDeadline:   scheduler.supervisorConfig.RenewDeadline,
+			RetryPeriod:     scheduler.supervisorConfig.RetryPeriod,
+			ReleaseOnCancel: true,
+			Name:            scheduler.supervisorConfig.LeaseName,
+			Callbacks: leaderelection.LeaderCallbacks{
+				OnStartedLeading: func(leaderCtx context.Context) {
+					scheduler.logger.V(0).Info("acquired supervisor lease - starting orphaned request checks", "lease", scheduler.supervisorConfig.LeaseName)
+					wait.UntilWithContext(leaderCtx, scheduler.superviseOrphans, scheduler.supervisorConfig.Interval)
+				},
+				OnStoppedLeading: func() {
+					scheduler.logger.V(0).Info("released supervisor lease", "lease", scheduler.supervisorConfig.LeaseName)
+				},
+			},
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	EventReasonSubmissionFailed      = "SubmissionFailed"
+	EventReasonSchedulingFailed      = "SchedulingFailed"
+	EventReasonRunRecovered          = "RunRecovered"
+	EventReasonRunCancelled          = "RunCancelled"
+	EventReasonQueueDeadlineExceeded = "QueueDeadlineExceeded"
+	EventReasonMissingWorkgroup      = "MissingWorkgroup"
+	EventReasonInvalidTemplate       = "InvalidTemplate"
+	EventReasonTemplateValid         = "TemplateValid"
+	EventReasonMissingVariant        = "MissingVariant"
+)
+
+// WithEventRecorder enables Kubernetes events on NexusAlgorithmTemplate resources for run lifecycle issues, so algorithm owners can see them with kubectl describe
+func (scheduler *RequestScheduler) WithEventRecorder(recorder record.EventRecorder, configCache *NexusResourceCache) *RequestScheduler {
+	scheduler.recorder = recorder
+	scheduler.configCache = configCache
+	return scheduler
+}
+
+// recordTemplateEvent emits an event on the template of a run. Events are best-effort and are skipped if the template is not in the cache
+func (scheduler *RequestScheduler) recordTemplateEvent(algorithmName string, eventType string, reason string, messageFmt string, args ...interface{}) {
+	if scheduler.recorder == nil || scheduler.configCache == nil {
+		return
+	}
+
+	template, err := scheduler.configCache.GetAlgorithmConfiguration(algorithmName)
+	if err != nil || template == nil {
+		scheduler.logger.V(1).Info("template not found - skipping event", "template", algorithmName, "reason", reason)
+		return
+	}
+
+	scheduler.recorder.Eventf(template, eventType, reason, messageFmt, args...)
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	v1 "github.com/SneaksAndData/nexus-core/pkg/apis/science/v1"
+	"github.com/SneaksAndData/nexus/services/models"
+	"github.com/SneaksAndData/nexus/storage"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// TemplateRevisionAnnotation is added to runtime environment annotations of the applied configuration of a run, with the template revision the run was created from
+const TemplateRevisionAnnotation = "science.sneaksanddata.com/template-revision"
+
+// ErrRevisionNotFound is returned for template revisions that have not been recorded
+var ErrRevisionNotFound = errors.New("template revision not found")
+
+// recordedTemplate is the latest template instance and generation recorded for an algorithm
+type recordedTemplate struct {
+	uid        types.UID
+	generation int64
+}
+
+// TemplateHistory records spec revisions of algorithm templates, so runs can be created from a revision other than the current one
+type TemplateHistory struct {
+	store    storage.TemplateRevisionStore
+	logger   klog.Logger
+	recorded map[string]recordedTemplate
+	lock     sync.Mutex
+}
+
+// NewTemplateHistory creates a TemplateHistory backed by the provided store
+func NewTemplateHistory(store storage.TemplateRevisionStore, logger klog.Logger) *TemplateHistory {
+	return &TemplateHistory{
+		store:    store,
+		logger:   logger,
+		recorded: map[string]recordedTemplate{},
+	}
+}
+
+// Record stores the spec of a template under the provided algorithm name, unless its generation has already been recorded. Generation only changes with the spec, so informer resyncs and status updates do not produce new revisions.
+// A revision recorded for a template that was since deleted and created again under the same name is overwritten
+func (history *TemplateHistory) Record(algorithmName string, template *v1.NexusAlgorithmTemplate) error {
+	history.lock.Lock()
+	defer history.lock.Unlock()
+
+	current := recordedTemplate{uid: template.UID, generation: template.Generation}
+	if recorded, ok := history.recorded[algorithmName]; ok && recorded == current {
+		return nil
+	}
+
+	spec, err := json.Marshal(template.Spec)
+	if err != nil { // coverage-ignore
+		return err
+	}
+
+	// revision may have been recorded before a restart, keep its original creation time
+	existing, err := history.store.ReadTemplateRevision(algorithmName, template.Generation)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil || existing.TemplateUid != string(template.UID) || existing.Spec != string(spec) {
+		if err := history.store.UpsertTemplateRevision(&models.TemplateRevision{
+			Algorithm:       algorithmName,
+			Revision:        template.Generation,
+			ResourceVersion: template.ResourceVersion,
+			TemplateUid:     string(template.UID),
+			Spec:            string(spec),
+			CreatedAt:       time.Now(),
+		}); err != nil {
+			return err
+		}
+
+		history.logger.V(2).Info("recorded template revision", "template", algorithmName, "revision", template.Generation)
+	}
+
+	history.recorded[algorithmName] = current
+	return nil
+}
+
+// currentRevision returns false for revisions recorded for a previous template with the same name
+func (history *TemplateHistory) currentRevision(algorithmName string, revision *models.TemplateRevision) bool {
+	history.lock.Lock()
+	defer history.lock.Unlock()
+
+	recorded, ok := history.recorded[algorithmName]
+	return !ok || revision.TemplateUid == string(recorded.uid)
+}
+
+// Resolve returns a copy of a template with the spec of the provided revision, or of the current revision if revision is 0.
+// The revision is added to runtime environment annotations of the returned spec, so it is visible in the applied configuration of a run
+func (history *TemplateHistory) Resolve(algorithmName string, template *v1.NexusAlgorithmTemplate, revision int64) (*v1.NexusAlgorithmTemplate, error) {
+	resolved := template.DeepCopy()
+	if revision != 0 && revision != template.Generation {
+		recorded, err := history.store.ReadTemplateRevision(algorithmName, revision)
+		if err != nil {
+			return nil, err
+		}
+
+		if recorded == nil || recorded.TemplateUid != string(template.UID) {
+			return nil, ErrRevisionNotFound
+		}
+
+		spec, err := recorded.TemplateSpec()
+		if err != nil {
+			return nil, err
+		}
+
+		resolved.Spec = *spec
+		resolved.Generation = recorded.Revision
+		resolved.ResourceVersion = recorded.ResourceVersion
+	}
+
+	return annotateAppliedConfiguration(resolved, TemplateRevisionAnnotation, strconv.FormatInt(resolved.Generation, 10)), nil
+}
+
+// List returns recorded revisions of a template, latest first
+func (history *TemplateHistory) List(algorithmName string) ([]*models.TemplateRevision, error) {
+	revisions, err := history.store.ReadTemplateRevisions(algorithmName)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(revisions, func(revision *models.TemplateRevision) bool {
+		return !history.currentRevision(algorithmName, revision)
+	}), nil
+}
+
+// Diff returns values that differ between two recorded revisions of a template, sorted by path
+func (history *TemplateHistory) Diff(algorithmName string, from int64, to int64) ([]*models.TemplateSpecChange, error) {
+	specs := []interface{}{nil, nil}
+	for index, revision := range []int64{from, to} {
+		recorded, err := history.store.ReadTemplateRevision(algorithmName, revision)
+		if err != nil {
+			return nil, err
+		}
+
+		if recorded == nil || !history.currentRevision(algorithmName, recorded) {
+			return nil, ErrRevisionNotFound
+		}
+
+		if err := json.Unmarshal([]byte(recorded.Spec), &specs[index]); err != nil {
+			return nil, err
+		}
+	}
+
+	changes := []*models.TemplateSpecChange{}
+	diffValues("", specs[0], specs[1], &changes)
+	return changes, nil
+}
+
+// diffValues compares JSON values, descending into objects. Arrays and scalars are reported as a whole
+func diffValues(path string, from interface{}, to interface{}, changes *[]*models.TemplateSpecChange) {
+	fromObject, fromIsObject := from.(map[string]interface{})
+	toObject, toIsObject := to.(map[string]interface{})
+	if !fromIsObject || !toIsObject {
+		if !reflect.DeepEqual(from, to) {
+			*changes = append(*changes, &models.TemplateSpecChange{Path: path, From: from, To: to})
+		}
+		return
+	}
+
+	keys := slices.Collect(maps.Keys(fromObject))
+	for key := range toObject {
+		if _, ok := fromObject[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		childPath := key
+		if path != "" {
+			childPath = path + "." + key
+		}
+		diffValues(childPath, fromObject[key], toObject[key], changes)
+	}
+}
+
+// WithTemplateHistory records a template revision each time the cache sees a template spec change
+func (c *NexusResourceCache) WithTemplateHistory(history *TemplateHistory) *NexusResourceCache {
+	c.history = history
+	return c
+}
+
+// recordRevision is best-effort: a failed attempt is repeated on the next informer resync
+func (c *NexusResourceCache) recordRevision(obj interface{}) {
+	template, ok := obj.(*v1.NexusAlgorithmTemplate)
+	if !ok || c.history == nil || !c.served(template) {
+		return
+	}
+
+	if err := c.history.Record(c.RunName(template), template); err != nil {
+		c.logger.V(0).Error(err, "failed to record template revision", "template", template.Name, "revision", template.Generation)
+	}
+}
+
+// WithTemplateHistory allows runs to be pinned to a recorded template revision
+func (submitter *RunSubmitter) WithTemplateHistory(history *TemplateHistory) *RunSubmitter {
+	submitter.history = history
+	return submitter
+}
+
+// resolveRevision returns the template a run is created from. Without template history, only the current revision can be used
+func (submitter *RunSubmitter) resolveRevision(config *v1.NexusAlgorithmTemplate, revision int64, requestId string) (*v1.NexusAlgorithmTemplate, error) {
+	if submitter.history == nil {
+		if revision != 0 {
+			return nil, invalidSubmission(`Template revisions are not enabled`)
+		}
+
+		return config, nil
+	}
+
+	resolved, err := submitter.history.Resolve(submitter.configCache.RunName(config), config, revision)
+	switch {
+	case errors.Is(err, ErrRevisionNotFound):
+		return nil, invalidSubmission(`Revision %d of algorithm %s not found`, revision, submitter.configCache.AlgorithmName(config))
+	case err != nil:
+		return nil, submitter.failedSubmission(err, "error when resolving template revision", submitter.configCache.RunName(config), requestId)
+	}
+
+	return resolved, nil
+}
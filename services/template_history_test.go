@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"errors"
+	v1 "github.com/SneaksAndData/nexus-core/pkg/apis/science/v1"
+	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/request"
+	"github.com/SneaksAndData/nexus/storage"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"testing"
+	"time"
+)
+
+func newHistoryTemplate(generation int64, versionTag string) *v1.NexusAlgorithmTemplate {
+	spec := newFakeSpec()
+	spec.Container.VersionTag = versionTag
+	return &v1.NexusAlgorithmTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "test-algorithm",
+			Namespace:       "nexus",
+			UID:             "test-uid",
+			Generation:      generation,
+			ResourceVersion: "rv" + versionTag,
+		},
+		Spec: *spec,
+	}
+}
+
+func newTemplateHistory(t *testing.T) (*TemplateHistory, *storage.MemoryStore) {
+	store := storage.NewMemoryStore(request.NewMemoryPassthroughBuffer(context.TODO(), map[string]string{}))
+	history := NewTemplateHistory(store, klog.NewKlogr())
+	for _, template := range []*v1.NexusAlgorithmTemplate{newHistoryTemplate(1, "v1.0.0"), newHistoryTemplate(2, "v2.0.0")} {
+		if err := history.Record(template.Name, template); err != nil {
+			t.Errorf("failed to record a template revision: %v", err)
+			t.FailNow()
+		}
+	}
+
+	return history, store
+}
+
+func TestTemplateHistory_Record(t *testing.T) {
+	history, store := newTemplateHistory(t)
+	first, _ := store.ReadTemplateRevision("test-algorithm", 1)
+
+	// a restarted scheduler sees the same revision again
+	if err := NewTemplateHistory(store, klog.NewKlogr()).Record("test-algorithm", newHistoryTemplate(1, "v1.0.0")); err != nil {
+		t.Errorf("failed to record a template revision: %v", err)
+	}
+
+	revisions, err := history.List("test-algorithm")
+	if err != nil || len(revisions) != 2 || revisions[0].Revision != 2 || revisions[1].Revision != 1 {
+		t.Errorf("expected revisions latest first, but got %v (%v)", revisions, err)
+		t.FailNow()
+	}
+
+	if !revisions[1].CreatedAt.Equal(first.CreatedAt) || revisions[1].ResourceVersion != "rvv1.0.0" {
+		t.Errorf("expected a recorded revision to be kept unchanged, but got %v", revisions[1])
+	}
+}
+
+func TestTemplateHistory_RecreatedTemplate(t *testing.T) {
+	history, store := newTemplateHistory(t)
+	recreated := newHistoryTemplate(1, "v3.0.0")
+	recreated.UID = "recreated-uid"
+
+	if err := history.Record(recreated.Name, recreated); err != nil {
+		t.Errorf("failed to record a template revision: %v", err)
+		t.FailNow()
+	}
+
+	if revision, _ := store.ReadTemplateRevision("test-algorithm", 1); revision == nil || revision.TemplateUid != "recreated-uid" || revision.ResourceVersion != "rvv3.0.0" {
+		t.Errorf("expected a revision of a deleted template to be overwritten, but got %v", revision)
+	}
+
+	if revisions, err := history.List("test-algorithm"); err != nil || len(revisions) != 1 || revisions[0].Revision != 1 {
+		t.Errorf("expected only revisions of the recreated template to be listed, but got %v (%v)", revisions, err)
+	}
+
+	if _, err := history.Resolve(recreated.Name, recreated, 2); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("expected a revision of a deleted template to be rejected, but got %v", err)
+	}
+
+	if _, err := history.Diff("test-algorithm", 1, 2); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("expected a diff with a revision of a deleted template to be rejected, but got %v", err)
+	}
+}
+
+func TestTemplateHistory_Resolve(t *testing.T) {
+	history, _ := newTemplateHistory(t)
+	current := newHistoryTemplate(2, "v2.0.0")
+
+	resolved, err := history.Resolve(current.Name, current, 0)
+	if err != nil || resolved.Spec.Container.VersionTag != "v2.0.0" || resolved.Spec.RuntimeEnvironment.Annotations[TemplateRevisionAnnotation] != "2" {
+		t.Errorf("expected the current revision to be used, but got %v (%v)", resolved, err)
+	}
+
+	pinned, err := history.Resolve(current.Name, current, 1)
+	if err != nil || pinned.Spec.Container.VersionTag != "v1.0.0" || pinned.Spec.RuntimeEnvironment.Annotations[TemplateRevisionAnnotation] != "1" {
+		t.Errorf("expected the pinned revision to be used, but got %v (%v)", pinned, err)
+	}
+
+	if current.Spec.RuntimeEnvironment.Annotations != nil {
+		t.Errorf("expected the cached template to be left unchanged, but got %v", current.Spec.RuntimeEnvironment.Annotations)
+	}
+
+	if _, err := history.Resolve(current.Name, current, 3); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("expected an unknown revision to be rejected, but got %v", err)
+	}
+}
+
+func TestTemplateHistory_Diff(t *testing.T) {
+	history, _ := newTemplateHistory(t)
+
+	changes, err := history.Diff("test-algorithm", 1, 2)
+	if err != nil || len(changes) != 1 || changes[0].Path != "container.versionTag" || changes[0].From != "v1.0.0" || changes[0].To != "v2.0.0" {
+		t.Errorf("expected a single changed value, but got %v (%v)", changes, err)
+	}
+
+	if _, err := history.Diff("test-algorithm", 1, 3); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("expected a diff with an unknown revision to be rejected, but got %v", err)
+	}
+}
+
+func TestRunSubmitter_PinnedRevision(t *testing.T) {
+	f, submitter := newSubmitterFixture(t, true)
+	scheduler, err := f.scheduler.Init(f.ctx)
+	if err != nil {
+		t.Errorf("scheduler init failed: %s", err)
+		t.FailNow()
+	}
+
+	go f.buffer.Start(scheduler.SchedulerActor)
+	time.Sleep(1 * time.Second)
+
+	// the pinned revision is recorded for the same template instance the run is created from
+	current, _ := f.scheduler.configCache.GetAlgorithmConfiguration("test-algorithm")
+	pinned := newHistoryTemplate(1, "v1.0.0")
+	pinned.UID = current.UID
+
+	history := NewTemplateHistory(f.store, klog.FromContext(f.ctx))
+	_ = history.Record("test-algorithm", pinned)
+
+	if _, err := submitter.Submit(context.TODO(), &RunSubmission{AlgorithmName: "test-algorithm", Request: newFakeRequest(), TemplateRevision: 1}); !errors.Is(err, ErrInvalidSubmission) {
+		t.Errorf("expected a pinned run to be rejected without template history, but got %v", err)
+	}
+
+	submitter.WithTemplateHistory(history)
+	requestId, err := submitter.Submit(context.TODO(), &RunSubmission{AlgorithmName: "test-algorithm", Request: newFakeRequest(), TemplateRevision: 1})
+	if err != nil {
+		t.Errorf("failed to submit a pinned run: %v", err)
+		t.FailNow()
+	}
+
+	// allow buffering to happen
+	time.Sleep(5 * time.Second)
+
+	checkpoint, _ := f.buffer.Get(requestId, "test-algorithm")
+	if checkpoint == nil || checkpoint.AppliedConfiguration.Container.VersionTag != "v1.0.0" || checkpoint.AppliedConfiguration.RuntimeEnvironment.Annotations[TemplateRevisionAnnotation] != "1" {
+		t.Errorf("expected a run to be created from the pinned revision, but got %v", checkpoint)
+	}
+
+	if _, err := submitter.Submit(context.TODO(), &RunSubmission{AlgorithmName: "test-algorithm", Request: newFakeRequest(), TemplateRevision: 5}); !errors.Is(err, ErrInvalidSubmission) {
+		t.Errorf("expected a run pinned to an unknown revision to be rejected, but got %v", err)
+	}
+}
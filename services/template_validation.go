@@ -0,0 +1,272 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	v1 "github.com/SneaksAndData/nexus-core/pkg/apis/science/v1"
+	"github.com/SneaksAndData/nexus-core/pkg/resolvers"
+	"github.com/SneaksAndData/nexus-core/pkg/shards"
+	"github.com/SneaksAndData/nexus-core/pkg/telemetry"
+	"github.com/SneaksAndData/nexus/services/models"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// shardLookupTimeout limits the time spent looking up resources referenced by a template on a shard cluster
+const shardLookupTimeout = time.Second * 10
+
+// WithValidation enables consistency checks of templates whenever a template or workgroup is added, modified or deleted, with results available from GetTemplateValidationStatus
+func (c *NexusResourceCache) WithValidation(config *models.TemplateValidationConfig, shardClients []*shards.ShardClient, shardKubeClients map[string]kubernetes.Interface, recorder record.EventRecorder) *NexusResourceCache {
+	c.validationConfig = config
+	c.shardClients = shardClients
+	c.shardKubeClients = shardKubeClients
+	c.recorder = recorder
+	c.validationStatus = map[string]*models.TemplateValidationStatus{}
+	return c
+}
+
+func (c *NexusResourceCache) validationEnabled() bool {
+	return c.validationConfig != nil && c.validationConfig.Enabled
+}
+
+// onResourceChanged validates a changed template, or all templates referencing a changed workgroup
+func (c *NexusResourceCache) onResourceChanged(obj interface{}) {
+	if !c.validationEnabled() {
+		return
+	}
+
+	object, ok := obj.(metav1.Object)
+	if !ok || !c.served(object) {
+		return
+	}
+
+	switch resource := obj.(type) {
+	case *v1.NexusAlgorithmTemplate:
+		c.validateTemplate(resource)
+	case *v1.NexusAlgorithmWorkgroup:
+		c.validateWorkgroupTemplates(resource)
+	}
+}
+
+// onResourceRemoved drops the status and resets the problem gauge of a deleted template, or validates all templates referencing a deleted workgroup
+func (c *NexusResourceCache) onResourceRemoved(object metav1.Object) {
+	if !c.validationEnabled() || !c.served(object) {
+		return
+	}
+
+	switch resource := object.(type) {
+	case *v1.NexusAlgorithmTemplate:
+		algorithmName := c.RunName(resource)
+		c.validationLock.Lock()
+		delete(c.validationStatus, algorithmName)
+		c.validationLock.Unlock()
+
+		// statsd gauges keep reporting the last value, a deleted template must not be reported as invalid
+		telemetry.Gauge(c.metrics, "template_problems", 0, map[string]string{"algorithm": algorithmName}, 1)
+	case *v1.NexusAlgorithmWorkgroup:
+		c.validateWorkgroupTemplates(resource)
+	}
+}
+
+// validateWorkgroupTemplates validates templates referencing a workgroup. Templates can only reference workgroups in their own namespace
+func (c *NexusResourceCache) validateWorkgroupTemplates(workgroup *v1.NexusAlgorithmWorkgroup) {
+	for _, template := range c.ListAlgorithmConfigurations() {
+		if template.Namespace == workgroup.Namespace && template.Spec.WorkgroupRef != nil && template.Spec.WorkgroupRef.Name == workgroup.Name {
+			c.validateTemplate(template)
+		}
+	}
+}
+
+// validateTemplate checks a template and records the result. Logs and events are only produced when the result changes, so informer resyncs do not repeat them
+func (c *NexusResourceCache) validateTemplate(template *v1.NexusAlgorithmTemplate) {
+	problems := c.templateProblems(template)
+	algorithmName := c.RunName(template)
+	status := &models.TemplateValidationStatus{
+		Template:        c.AlgorithmName(template),
+		ResourceVersion: template.ResourceVersion,
+		Valid:           len(problems) == 0,
+		Problems:        problems,
+		ValidatedAt:     time.Now(),
+	}
+
+	c.validationLock.Lock()
+	previous := c.validationStatus[algorithmName]
+	c.validationStatus[algorithmName] = status
+	c.validationLock.Unlock()
+
+	telemetry.Gauge(c.metrics, "template_problems", float64(len(problems)), map[string]string{"algorithm": algorithmName}, 1)
+
+	if previous != nil && slices.Equal(previous.Problems, problems) {
+		return
+	}
+
+	if len(problems) > 0 {
+		c.logger.V(0).Info("template validation failed", "template", algorithmName, "problems", problems)
+		c.recordValidationEvent(template, corev1.EventTypeWarning, EventReasonInvalidTemplate, "Template validation failed: %s", strings.Join(problems, "; "))
+		return
+	}
+
+	if previous != nil {
+		c.logger.V(0).Info("template validation passed", "template", algorithmName)
+		c.recordValidationEvent(template, corev1.EventTypeNormal, EventReasonTemplateValid, "Template validation passed")
+	}
+}
+
+func (c *NexusResourceCache) recordValidationEvent(template *v1.NexusAlgorithmTemplate, eventType string, reason string, messageFmt string, args ...interface{}) {
+	if c.recorder == nil || !c.validationConfig.RecordEvents {
+		return
+	}
+
+	c.recorder.Eventf(template, eventType, reason, messageFmt, args...)
+}
+
+// templateProblems follows references of a template to traffic split variants, aliases, its workgroup and the shard of the workgroup, the same way runs are resolved
+func (c *NexusResourceCache) templateProblems(template *v1.NexusAlgorithmTemplate) []string {
+	problems := []string{}
+	if template.Spec.Container == nil {
+		problems = append(problems, "container is not set")
+	}
+
+	variants, err := TemplateTrafficSplit(template)
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	for _, variant := range variants {
+		if existing, err := c.GetAlgorithmConfiguration(c.qualifiedName(template.Namespace, variant.Template)); err == nil && existing == nil {
+			problems = append(problems, fmt.Sprintf("variant template %s not found", variant.Template))
+		}
+	}
+
+	problems = append(problems, c.aliasProblems(template)...)
+
+	if _, err := TemplatePayloadEncoding(template); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if template.Spec.WorkgroupRef == nil || template.Spec.WorkgroupRef.Name == "" {
+		return append(problems, "workgroup is not set")
+	}
+
+	workgroup, err := c.GetTemplateWorkgroup(template)
+	if err != nil || workgroup == nil {
+		return append(problems, fmt.Sprintf("workgroup %s not found", template.Spec.WorkgroupRef.Name))
+	}
+
+	shard := c.getShardByName(workgroup.Spec.Cluster)
+	if shard == nil {
+		return append(problems, fmt.Sprintf("cluster %s of workgroup %s is not configured as a shard", workgroup.Spec.Cluster, workgroup.Name))
+	}
+
+	client, ok := c.shardKubeClients[shard.Name]
+	if !c.validationConfig.CheckShardResources || !ok {
+		return problems
+	}
+
+	return append(problems, c.shardResourceProblems(template, client, shard)...)
+}
+
+// aliasProblems reports aliases that cannot be resolved to the template, because they are template names or are defined by other templates
+func (c *NexusResourceCache) aliasProblems(template *v1.NexusAlgorithmTemplate) []string {
+	aliases, err := TemplateAliases(template)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	problems := []string{}
+	for _, alias := range aliases {
+		if existing, err := resolvers.GetCachedObject[v1.NexusAlgorithmTemplate](alias.Name, template.Namespace, c.templateInformer); err == nil && existing != nil {
+			problems = append(problems, fmt.Sprintf("alias %s is the name of an existing template", alias.Name))
+			continue
+		}
+
+		targets, err := c.aliasTargets(template.Namespace, alias.Name)
+		if err == nil && len(targets) > 0 && targets[0].Name != template.Name {
+			problems = append(problems, fmt.Sprintf("alias %s is already defined by template %s", alias.Name, targets[0].Name))
+		}
+	}
+
+	return problems
+}
+
+// shardResourceProblems looks up resources a template references on a shard. Lookup errors other than a missing resource are logged and do not fail the validation
+func (c *NexusResourceCache) shardResourceProblems(template *v1.NexusAlgorithmTemplate, client kubernetes.Interface, shard *shards.ShardClient) []string {
+	ctx, cancel := context.WithTimeout(context.Background(), shardLookupTimeout)
+	defer cancel()
+
+	problems := []string{}
+	check := func(kind string, name string, err error) {
+		if apierrors.IsNotFound(err) {
+			problems = append(problems, fmt.Sprintf("%s %s not found on cluster %s", kind, name, shard.Name))
+		} else if err != nil {
+			c.logger.V(1).Error(err, "unable to look up a resource referenced by a template", "template", template.Name, "kind", kind, "name", name, "shard", shard.Name)
+		}
+	}
+
+	if template.Spec.Container != nil && template.Spec.Container.ServiceAccountName != "" {
+		_, err := client.CoreV1().ServiceAccounts(shard.Namespace).Get(ctx, template.Spec.Container.ServiceAccountName, metav1.GetOptions{})
+		check("service account", template.Spec.Container.ServiceAccountName, err)
+	}
+
+	if template.Spec.RuntimeEnvironment == nil {
+		return problems
+	}
+
+	secrets := template.GetSecretNames()
+	sort.Strings(secrets)
+	for _, secret := range secrets {
+		_, err := client.CoreV1().Secrets(shard.Namespace).Get(ctx, secret, metav1.GetOptions{})
+		check("secret", secret, err)
+	}
+
+	configMaps := template.GetConfigMapNames()
+	sort.Strings(configMaps)
+	for _, configMap := range configMaps {
+		_, err := client.CoreV1().ConfigMaps(shard.Namespace).Get(ctx, configMap, metav1.GetOptions{})
+		check("config map", configMap, err)
+	}
+
+	return problems
+}
+
+func (c *NexusResourceCache) getShardByName(shardName string) *shards.ShardClient {
+	for _, shard := range c.shardClients {
+		if shard.Name == shardName {
+			return shard
+		}
+	}
+
+	return nil
+}
+
+// GetTemplateValidationStatus returns the latest validation result of a template, or nil if the template has not been validated
+func (c *NexusResourceCache) GetTemplateValidationStatus(algorithmName string) *models.TemplateValidationStatus {
+	c.validationLock.RLock()
+	defer c.validationLock.RUnlock()
+
+	return c.validationStatus[RunAlgorithmName(algorithmName)]
+}
+
+// ListTemplateValidationStatus returns the latest validation results of all templates, sorted by template name
+func (c *NexusResourceCache) ListTemplateValidationStatus() []*models.TemplateValidationStatus {
+	c.validationLock.RLock()
+	defer c.validationLock.RUnlock()
+
+	statuses := []*models.TemplateValidationStatus{}
+	for _, status := range c.validationStatus {
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Template < statuses[j].Template
+	})
+
+	return statuses
+}
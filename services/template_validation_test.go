@@ -0,0 +1,131 @@
+package services
+
+import (
+	v1 "github.com/SneaksAndData/nexus-core/pkg/apis/science/v1"
+	"github.com/SneaksAndData/nexus-core/pkg/generated/clientset/versioned/fake"
+	"github.com/SneaksAndData/nexus-core/pkg/shards"
+	"github.com/SneaksAndData/nexus/services/models"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func newValidationFixture(t *testing.T, existingShardObjects []runtime.Object) (*fixture, *record.FakeRecorder, *k8sfake.Clientset) {
+	f := newFixture(t, []runtime.Object{})
+	shardClient := k8sfake.NewClientset(existingShardObjects...)
+	recorder := record.NewFakeRecorder(100)
+
+	f.configCache.WithValidation(
+		&models.TemplateValidationConfig{Enabled: true, CheckShardResources: true, RecordEvents: true},
+		[]*shards.ShardClient{shards.NewShardClient(shardClient, fake.NewClientset(), "test-shard", "test", klog.FromContext(f.ctx))},
+		map[string]kubernetes.Interface{"test-shard": shardClient},
+		recorder,
+	)
+
+	f.populateWorkgroups([]*v1.NexusAlgorithmWorkgroup{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "test"},
+			Spec:       *newFakeWorkgroupSpec(),
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "unassigned", Namespace: "test"},
+			Spec:       v1.NexusAlgorithmWorkgroupSpec{Cluster: "missing-shard"},
+		},
+	})
+
+	return f, recorder, shardClient
+}
+
+func newValidatedTemplate(name string, workgroup string) *v1.NexusAlgorithmTemplate {
+	spec := newFakeSpec()
+	spec.WorkgroupRef.Name = workgroup
+	return &v1.NexusAlgorithmTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+		Spec:       *spec,
+	}
+}
+
+func TestNexusResourceCache_ValidateTemplate(t *testing.T) {
+	f, _, _ := newValidationFixture(t, []runtime.Object{
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "test-sa", Namespace: "test"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "test-secret", Namespace: "test"}},
+	})
+
+	withMissingSecret := newValidatedTemplate("missing-secret", "default")
+	withMissingSecret.Spec.RuntimeEnvironment.MappedEnvironmentVariables = append(withMissingSecret.Spec.RuntimeEnvironment.MappedEnvironmentVariables, corev1.EnvFromSource{
+		ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "test-config"}},
+	})
+
+	templates := []*v1.NexusAlgorithmTemplate{
+		newValidatedTemplate("valid", "default"),
+		newValidatedTemplate("missing-workgroup", "missing"),
+		newValidatedTemplate("missing-shard", "unassigned"),
+		withMissingSecret,
+	}
+	f.populateTemplates(templates)
+	for _, template := range templates {
+		f.configCache.onResourceChanged(template)
+	}
+
+	expected := map[string][]string{
+		"valid":             {},
+		"missing-workgroup": {"workgroup missing not found"},
+		"missing-shard":     {"cluster missing-shard of workgroup unassigned is not configured as a shard"},
+		"missing-secret":    {"config map test-config not found on cluster test-shard"},
+	}
+	for name, problems := range expected {
+		status := f.configCache.GetTemplateValidationStatus(name)
+		if status == nil || status.Valid != (len(problems) == 0) || !slices.Equal(status.Problems, problems) {
+			t.Errorf("expected template %s to have problems %v, but got %v", name, problems, status)
+		}
+	}
+
+	if statuses := f.configCache.ListTemplateValidationStatus(); len(statuses) != 4 || statuses[0].Template != "missing-secret" {
+		t.Errorf("expected validation results sorted by template name, but got %v", statuses)
+	}
+}
+
+func TestNexusResourceCache_ValidationEvents(t *testing.T) {
+	f, recorder, shardClient := newValidationFixture(t, []runtime.Object{})
+	template := newValidatedTemplate("test-algorithm", "default")
+	f.populateTemplates([]*v1.NexusAlgorithmTemplate{template})
+
+	f.configCache.onResourceChanged(template)
+	// a resync with the same result must not repeat the event
+	f.configCache.onConfigurationUpdated(template, template)
+
+	_, _ = shardClient.CoreV1().ServiceAccounts("test").Create(f.ctx, &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "test-sa", Namespace: "test"}}, metav1.CreateOptions{})
+	_, _ = shardClient.CoreV1().Secrets("test").Create(f.ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "test-secret", Namespace: "test"}}, metav1.CreateOptions{})
+	// a workgroup change validates all templates that reference it
+	workgroup, _ := f.configCache.GetWorkgroupConfiguration("default")
+	f.configCache.onResourceChanged(workgroup)
+
+	recorded := []string{}
+	for len(recorder.Events) > 0 {
+		recorded = append(recorded, <-recorder.Events)
+	}
+
+	if len(recorded) != 2 || !strings.Contains(recorded[0], EventReasonInvalidTemplate) || !strings.Contains(recorded[0], "secret test-secret not found") || !strings.Contains(recorded[1], EventReasonTemplateValid) {
+		t.Errorf("expected an event when a template becomes invalid and when it becomes valid again, but got %v", recorded)
+	}
+
+	// a resync of an unmodified template does not look up shard resources again
+	template.ResourceVersion = "1"
+	lookups := len(shardClient.Actions())
+	f.configCache.onConfigurationUpdated(template, template)
+	if len(shardClient.Actions()) != lookups {
+		t.Errorf("expected an unmodified template not to be revalidated, but got %d shard lookups", len(shardClient.Actions())-lookups)
+	}
+
+	f.configCache.onConfigurationDeleted(template)
+	if status := f.configCache.GetTemplateValidationStatus("test-algorithm"); status != nil {
+		t.Errorf("expected validation result of a deleted template to be removed, but got %v", status)
+	}
+}
@@ -0,0 +1,305 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	v1 "github.com/SneaksAndData/nexus-core/pkg/apis/science/v1"
+	coremodels "github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
+	nexusinf "github.com/SneaksAndData/nexus-core/pkg/generated/informers/externalversions"
+	"github.com/SneaksAndData/nexus/services/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// TenantSeparator separates a tenant from an algorithm name in the API, for example my-team/my-algorithm
+	TenantSeparator = "/"
+	// runNameSeparator separates a tenant from an algorithm name in names runs are stored under. Run names are used as Job label values, which cannot contain slashes.
+	// Neither namespaces nor template names can contain underscores, so run names remain unambiguous
+	runNameSeparator = "_"
+)
+
+var (
+	// ErrQuotaExceeded is returned for runs of a tenant that has reached its limit of active runs
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	// ErrInvalidTag is returned for client tags containing the tenant separator while tenancy is enabled
+	ErrInvalidTag = fmt.Errorf("tags cannot contain %s when tenancy is enabled", TenantSeparator)
+)
+
+// SplitAlgorithmName returns the tenant and the name of a tenant-qualified algorithm, in either API or run name form. Tenant is empty for algorithms in the runtime namespace
+func SplitAlgorithmName(algorithmName string) (string, string) {
+	if tenant, name, ok := strings.Cut(algorithmName, TenantSeparator); ok {
+		return tenant, name
+	}
+
+	if tenant, name, ok := strings.Cut(algorithmName, runNameSeparator); ok {
+		return tenant, name
+	}
+
+	return "", algorithmName
+}
+
+// RunAlgorithmName converts an algorithm name to the name runs of the algorithm are stored under
+func RunAlgorithmName(algorithmName string) string {
+	return strings.Replace(algorithmName, TenantSeparator, runNameSeparator, 1)
+}
+
+// TenantTag scopes a run tag to a tenant, so tag queries of one tenant do not return runs of another tenant
+func TenantTag(tenant string, tag string) string {
+	if tenant == "" || tag == "" {
+		return tag
+	}
+
+	return tenant + TenantSeparator + tag
+}
+
+// validateTag rejects client tags containing the tenant separator, so a tag of the runtime namespace cannot name a tag of a tenant
+func validateTag(tenancy *models.TenancyConfig, tag string) error {
+	if tenancy != nil && tenancy.Enabled && strings.Contains(tag, TenantSeparator) {
+		return ErrInvalidTag
+	}
+
+	return nil
+}
+
+// ScopedTag returns the tag runs of a tenant with the client tag are stored under, or ErrInvalidTag if the client tag cannot be used.
+// Tenant is not authenticated: tags are namespaced per tenant, not isolated between tenants
+func (scheduler *RequestScheduler) ScopedTag(tenant string, tag string) (string, error) {
+	if err := validateTag(scheduler.tenancy, tag); err != nil {
+		return "", err
+	}
+
+	return TenantTag(tenant, tag), nil
+}
+
+// WithTenancy discovers templates and workgroups in tenant namespaces in addition to the runtime namespace. Must be called before Init
+func (c *NexusResourceCache) WithTenancy(config *models.TenancyConfig) *NexusResourceCache {
+	c.tenancy = config
+	if !c.tenancyEnabled() {
+		return c
+	}
+
+	c.watch(nexusinf.WithNamespace(metav1.NamespaceAll), nexusinf.WithTweakListOptions(func(options *metav1.ListOptions) {
+		options.LabelSelector = config.LabelSelector
+	}))
+
+	return c
+}
+
+func (c *NexusResourceCache) tenancyEnabled() bool {
+	return c.tenancy != nil && c.tenancy.Enabled
+}
+
+// tenantNamespace returns the namespace of a tenant, or false if the tenant is not served by this scheduler. An empty tenant is the runtime namespace
+func (c *NexusResourceCache) tenantNamespace(tenant string) (string, bool) {
+	if tenant == "" || tenant == c.prefix {
+		return c.prefix, true
+	}
+
+	if !c.tenancyEnabled() || (len(c.tenancy.Namespaces) > 0 && !slices.Contains(c.tenancy.Namespaces, tenant)) {
+		return "", false
+	}
+
+	return tenant, true
+}
+
+// served returns true if a resource is located in the runtime namespace or in a tenant namespace
+func (c *NexusResourceCache) served(object metav1.Object) bool {
+	_, ok := c.tenantNamespace(object.GetNamespace())
+	return ok
+}
+
+// qualifiedName returns the name a resource is addressed by in the API: its name in the runtime namespace, or a tenant-qualified name otherwise
+func (c *NexusResourceCache) qualifiedName(namespace string, name string) string {
+	if namespace == c.prefix {
+		return name
+	}
+
+	return namespace + TenantSeparator + name
+}
+
+// TemplateTenant returns the tenant a template belongs to, or an empty string for templates in the runtime namespace
+func (c *NexusResourceCache) TemplateTenant(template *v1.NexusAlgorithmTemplate) string {
+	if template.Namespace == c.prefix {
+		return ""
+	}
+
+	return template.Namespace
+}
+
+// AlgorithmName returns the name a template is addressed by in the API
+func (c *NexusResourceCache) AlgorithmName(template *v1.NexusAlgorithmTemplate) string {
+	return c.qualifiedName(template.Namespace, template.Name)
+}
+
+// RunName returns the name runs, revisions and validation results of a template are stored under
+func (c *NexusResourceCache) RunName(template *v1.NexusAlgorithmTemplate) string {
+	return RunAlgorithmName(c.AlgorithmName(template))
+}
+
+// ListTenantAlgorithmConfigurations returns cached templates of a tenant, sorted by name
+func (c *NexusResourceCache) ListTenantAlgorithmConfigurations(tenant string) []*v1.NexusAlgorithmTemplate {
+	if tenant == c.prefix {
+		tenant = ""
+	}
+
+	templates := []*v1.NexusAlgorithmTemplate{}
+	for _, template := range c.ListAlgorithmConfigurations() {
+		if c.TemplateTenant(template) == tenant {
+			templates = append(templates, template)
+		}
+	}
+
+	return templates
+}
+
+// WithTenancy creates Jobs of tenants in tenant namespaces, if enabled, and limits the number of active runs of each tenant
+func (scheduler *RequestScheduler) WithTenancy(config *models.TenancyConfig) *RequestScheduler {
+	scheduler.tenancy = config
+	scheduler.activeRuns = &activeRunCounter{ttl: config.ActiveRunsCacheTtl, counts: map[string]activeRunCount{}}
+	return scheduler
+}
+
+// tenantJobNamespace returns the namespace Jobs of an algorithm are created in on a shard, or an empty string if the runtime namespace should be used
+func (scheduler *RequestScheduler) tenantJobNamespace(algorithmName string) string {
+	if scheduler.tenancy == nil || !scheduler.tenancy.Enabled || !scheduler.tenancy.TenantJobNamespaces {
+		return ""
+	}
+
+	tenant, _ := SplitAlgorithmName(algorithmName)
+	return tenant
+}
+
+// runNamespace returns the namespace of Jobs of an algorithm on a shard
+func (scheduler *RequestScheduler) runNamespace(algorithmName string) string {
+	if namespace := scheduler.tenantJobNamespace(algorithmName); namespace != "" {
+		return namespace
+	}
+
+	return scheduler.jobNamespace
+}
+
+// jobNamespaces returns namespaces Jobs are created in on shards: the runtime namespace and tenant namespaces, if enabled. All namespaces are returned as NamespaceAll if tenant namespaces are not listed
+func (scheduler *RequestScheduler) jobNamespaces() []string {
+	if scheduler.tenancy == nil || !scheduler.tenancy.Enabled || !scheduler.tenancy.TenantJobNamespaces {
+		return []string{scheduler.jobNamespace}
+	}
+
+	if len(scheduler.tenancy.Namespaces) == 0 {
+		return []string{metav1.NamespaceAll}
+	}
+
+	namespaces := []string{scheduler.jobNamespace}
+	for _, namespace := range scheduler.tenancy.Namespaces {
+		if !slices.Contains(namespaces, namespace) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+
+	return namespaces
+}
+
+// countActiveRuns returns the number of buffered and running runs of all templates of a tenant
+func (scheduler *RequestScheduler) countActiveRuns(tenant string) (int, error) {
+	count := 0
+	for _, template := range scheduler.configCache.ListTenantAlgorithmConfigurations(tenant) {
+		for _, stage := range []string{coremodels.LifecycleStageNew, coremodels.LifecycleStageBuffered, coremodels.LifecycleStageRunning} {
+			checkpoints, err := scheduler.store.ReadCheckpointsByStage(scheduler.configCache.RunName(template), stage)
+			if err != nil {
+				return 0, err
+			}
+
+			for _, err := range checkpoints {
+				if err != nil { // coverage-ignore
+					return 0, err
+				}
+				count++
+			}
+		}
+	}
+
+	return count, nil
+}
+
+// activeRunCount is a count of active runs of a tenant, including runs accepted since it was read from the checkpoint store
+type activeRunCount struct {
+	count     int
+	expiresAt time.Time
+}
+
+// activeRunCounter caches counts of active runs of tenants, so the checkpoint store is not queried on every submission
+type activeRunCounter struct {
+	lock   sync.Mutex
+	ttl    time.Duration
+	counts map[string]activeRunCount
+}
+
+// reserve counts a run of a tenant if the tenant has less than the limit of active runs, and returns the number of active runs before it with a function releasing the run.
+// Counts are refreshed with count once they expire, so runs completed in the meantime are only released then
+func (counter *activeRunCounter) reserve(tenant string, limit int, count func(string) (int, error)) (int, func(), error) {
+	counter.lock.Lock()
+	defer counter.lock.Unlock()
+
+	active, ok := counter.counts[tenant]
+	if !ok || !time.Now().Before(active.expiresAt) {
+		current, err := count(tenant)
+		if err != nil {
+			return 0, nil, err
+		}
+		active = activeRunCount{count: current, expiresAt: time.Now().Add(counter.ttl)}
+	}
+
+	if active.count >= limit {
+		counter.counts[tenant] = active
+		return active.count, nil, nil
+	}
+
+	counter.counts[tenant] = activeRunCount{count: active.count + 1, expiresAt: active.expiresAt}
+	return active.count, func() { counter.release(tenant, active.expiresAt) }, nil
+}
+
+// release removes a reserved run from the count of a tenant, unless the count was refreshed since the run was reserved
+func (counter *activeRunCounter) release(tenant string, expiresAt time.Time) {
+	counter.lock.Lock()
+	defer counter.lock.Unlock()
+
+	if active, ok := counter.counts[tenant]; ok && active.expiresAt.Equal(expiresAt) && active.count > 0 {
+		counter.counts[tenant] = activeRunCount{count: active.count - 1, expiresAt: active.expiresAt}
+	}
+}
+
+// checkTenantQuota rejects a run if the tenant of the template already has the maximum number of active runs.
+// The quota is approximate: counts are cached by each scheduler replica, so replicas may together accept more runs than the limit until their counts expire
+// A run that is accepted is reserved in the count of the tenant, and must be released with the returned function if it is not buffered
+func (submitter *RunSubmitter) checkTenantQuota(config *v1.NexusAlgorithmTemplate, requestId string) (func(), error) {
+	noop := func() {}
+	tenancy := submitter.scheduler.tenancy
+	if tenancy == nil || !tenancy.Enabled || tenancy.MaxActiveRuns <= 0 {
+		return noop, nil
+	}
+
+	tenant := submitter.configCache.TemplateTenant(config)
+	active, release, err := submitter.scheduler.activeRuns.reserve(tenant, tenancy.MaxActiveRuns, submitter.scheduler.countActiveRuns)
+	if err != nil {
+		return noop, submitter.failedSubmission(err, "error when counting active runs of a tenant", submitter.configCache.RunName(config), requestId)
+	}
+
+	if release == nil {
+		submitter.logger.V(0).Info("tenant quota exceeded - rejecting run", "tenant", tenant, "algorithm", submitter.configCache.RunName(config), "active", active)
+		return noop, &SubmissionError{Reason: ErrQuotaExceeded, Message: fmt.Sprintf(`Tenant %s has reached the limit of %d active runs. Please try again later.`, tenantDisplayName(tenant), tenancy.MaxActiveRuns)}
+	}
+
+	return release, nil
+}
+
+// tenantDisplayName returns a tenant name for messages, where the runtime namespace is an empty tenant
+func tenantDisplayName(tenant string) string {
+	if tenant == "" {
+		return "default"
+	}
+
+	return tenant
+}
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"errors"
+	v1 "github.com/SneaksAndData/nexus-core/pkg/apis/science/v1"
+	"github.com/SneaksAndData/nexus/services/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSplitAlgorithmName(t *testing.T) {
+	tests := []struct {
+		name           string
+		algorithmName  string
+		expectedTenant string
+		expectedName   string
+		expectedRun    string
+	}{
+		{name: "runtime namespace", algorithmName: "test-algorithm", expectedTenant: "", expectedName: "test-algorithm", expectedRun: "test-algorithm"},
+		{name: "api form", algorithmName: "team-a/test-algorithm", expectedTenant: "team-a", expectedName: "test-algorithm", expectedRun: "team-a_test-algorithm"},
+		{name: "run name form", algorithmName: "team-a_test-algorithm", expectedTenant: "team-a", expectedName: "test-algorithm", expectedRun: "team-a_test-algorithm"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tenant, name := SplitAlgorithmName(test.algorithmName)
+			if tenant != test.expectedTenant || name != test.expectedName {
+				t.Errorf("expected %s and %s, but got %s and %s", test.expectedTenant, test.expectedName, tenant, name)
+			}
+
+			if runName := RunAlgorithmName(test.algorithmName); runName != test.expectedRun {
+				t.Errorf("expected run name %s, but got %s", test.expectedRun, runName)
+			}
+		})
+	}
+
+	if tag := TenantTag("team-a", "daily"); tag != "team-a/daily" {
+		t.Errorf("expected a tag to be scoped to a tenant, but got %s", tag)
+	}
+
+	if tag := TenantTag("", "daily"); tag != "daily" {
+		t.Errorf("expected a tag of the runtime namespace to remain unchanged, but got %s", tag)
+	}
+}
+
+func TestNexusResourceCache_WithTenancy(t *testing.T) {
+	f := newFixture(t, []runtime.Object{})
+	f.configCache.WithTenancy(&models.TenancyConfig{Enabled: true, Namespaces: []string{"team-a"}})
+	f.populateTemplates([]*v1.NexusAlgorithmTemplate{
+		{ObjectMeta: metav1.ObjectMeta{Name: "test-algorithm", Namespace: "test"}, Spec: *newFakeSpec()},
+		{ObjectMeta: metav1.ObjectMeta{Name: "test-algorithm", Namespace: "team-a"}, Spec: *newFakeSpec()},
+		{ObjectMeta: metav1.ObjectMeta{Name: "test-algorithm", Namespace: "team-b"}, Spec: *newFakeSpec()},
+	})
+	f.populateWorkgroups([]*v1.NexusAlgorithmWorkgroup{
+		{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "team-a"}, Spec: *newFakeWorkgroupSpec()},
+	})
+
+	template, err := f.configCache.GetAlgorithmConfiguration("team-a/test-algorithm")
+	if err != nil || template == nil || template.Namespace != "team-a" {
+		t.Errorf("expected a template of a tenant to be resolved, but got %v (%v)", template, err)
+		t.FailNow()
+	}
+
+	if name, runName := f.configCache.AlgorithmName(template), f.configCache.RunName(template); name != "team-a/test-algorithm" || runName != "team-a_test-algorithm" {
+		t.Errorf("expected tenant-qualified names, but got %s and %s", name, runName)
+	}
+
+	if byRunName, _ := f.configCache.GetAlgorithmConfiguration("team-a_test-algorithm"); byRunName == nil || byRunName.Namespace != "team-a" {
+		t.Errorf("expected a template of a tenant to be resolved by its run name, but got %v", byRunName)
+	}
+
+	if workgroup, _ := f.configCache.GetTemplateWorkgroup(template); workgroup == nil || workgroup.Namespace != "team-a" {
+		t.Errorf("expected a workgroup to be resolved in the namespace of the template, but got %v", workgroup)
+	}
+
+	if runtimeTemplate, _ := f.configCache.GetAlgorithmConfiguration("test-algorithm"); runtimeTemplate == nil || runtimeTemplate.Namespace != "test" {
+		t.Errorf("expected a template of the runtime namespace to be resolved by name, but got %v", runtimeTemplate)
+	}
+
+	if other, _ := f.configCache.GetAlgorithmConfiguration("team-b/test-algorithm"); other != nil {
+		t.Errorf("expected a template of an unlisted tenant to be hidden, but got %v", other)
+	}
+
+	names := []string{}
+	for _, listed := range f.configCache.ListAlgorithmConfigurations() {
+		names = append(names, f.configCache.AlgorithmName(listed))
+	}
+
+	if !slices.Equal(names, []string{"team-a/test-algorithm", "test-algorithm"}) {
+		t.Errorf("expected templates of served tenants to be listed, but got %v", names)
+	}
+
+	if tenantTemplates := f.configCache.ListTenantAlgorithmConfigurations("team-a"); len(tenantTemplates) != 1 || tenantTemplates[0].Namespace != "team-a" {
+		t.Errorf("expected only templates of a tenant to be listed, but got %v", tenantTemplates)
+	}
+}
+
+func TestScheduler_TenantJobNamespaces(t *testing.T) {
+	f := newSchedulerFixture(t, []runtime.Object{}, []runtime.Object{})
+	f.scheduler.WithTenancy(&models.TenancyConfig{Enabled: true, Namespaces: []string{"team-a"}, TenantJobNamespaces: true})
+
+	if namespace := f.scheduler.runNamespace("team-a_test-algorithm"); namespace != "team-a" {
+		t.Errorf("expected Jobs of a tenant to be created in the tenant namespace, but got %s", namespace)
+	}
+
+	if namespace := f.scheduler.runNamespace("test-algorithm"); namespace != "nexus" {
+		t.Errorf("expected Jobs of the runtime namespace to be created in the runtime namespace, but got %s", namespace)
+	}
+
+	if namespaces := f.scheduler.jobNamespaces(); !slices.Equal(namespaces, []string{"nexus", "team-a"}) {
+		t.Errorf("expected Jobs to be watched in the runtime namespace and tenant namespaces, but got %v", namespaces)
+	}
+
+	f.scheduler.WithTenancy(&models.TenancyConfig{Enabled: true})
+	if namespace := f.scheduler.runNamespace("team-a_test-algorithm"); namespace != "nexus" {
+		t.Errorf("expected Jobs of a tenant to be created in the runtime namespace if tenant Job namespaces are disabled, but got %s", namespace)
+	}
+}
+
+func TestScheduler_ScopedTag(t *testing.T) {
+	f := newSchedulerFixture(t, []runtime.Object{}, []runtime.Object{})
+
+	if tag, err := f.scheduler.ScopedTag("", "team-a/daily"); err != nil || tag != "team-a/daily" {
+		t.Errorf("expected tags to be used as is without tenancy, but got %s (%v)", tag, err)
+	}
+
+	f.scheduler.WithTenancy(&models.TenancyConfig{Enabled: true})
+	if tag, err := f.scheduler.ScopedTag("team-a", "daily"); err != nil || tag != "team-a/daily" {
+		t.Errorf("expected a tag to be scoped to a tenant, but got %s (%v)", tag, err)
+	}
+
+	// a tag of the runtime namespace must not name a tag of a tenant
+	if _, err := f.scheduler.ScopedTag("", "team-a/daily"); !errors.Is(err, ErrInvalidTag) {
+		t.Errorf("expected a tag with a tenant separator to be rejected, but got %v", err)
+	}
+}
+
+func TestRunSubmitter_TenantQuota(t *testing.T) {
+	f, submitter := newSubmitterFixture(t, false)
+	tenancy := &models.TenancyConfig{Enabled: true, MaxActiveRuns: 1}
+	f.scheduler.configCache.WithTenancy(tenancy)
+	f.scheduler.WithTenancy(tenancy)
+	_ = f.scheduler.configCache.templateInformer.GetIndexer().Add(&v1.NexusAlgorithmTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-algorithm", Namespace: "team-a"},
+		Spec:       *newFakeSpec(),
+	})
+	_ = f.scheduler.configCache.templateInformer.GetIndexer().Add(&v1.NexusAlgorithmTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-algorithm", Namespace: "nexus"},
+		Spec:       *newFakeSpec(),
+	})
+	_ = f.scheduler.configCache.workgroupInformer.GetIndexer().Add(&v1.NexusAlgorithmWorkgroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "team-a"},
+		Spec:       *newFakeWorkgroupSpec(),
+	})
+
+	scheduler, err := f.scheduler.Init(f.ctx)
+	if err != nil {
+		t.Errorf("scheduler init failed: %s", err)
+		t.FailNow()
+	}
+
+	go f.buffer.Start(scheduler.SchedulerActor)
+	time.Sleep(1 * time.Second)
+
+	payload := newFakeRequest()
+	payload.Tag = "daily"
+	requestId, err := submitter.Submit(context.TODO(), &RunSubmission{AlgorithmName: "team-a/test-algorithm", Request: payload})
+	if err != nil {
+		t.Errorf("failed to submit a run of a tenant: %v", err)
+		t.FailNow()
+	}
+
+	// allow buffering to happen
+	time.Sleep(5 * time.Second)
+
+	checkpoint, _ := f.buffer.Get(requestId, "team-a_test-algorithm")
+	if checkpoint == nil || checkpoint.Tag != "team-a/daily" {
+		t.Errorf("expected a run of a tenant to be stored under its run name with a tenant tag, but got %v", checkpoint)
+	}
+
+	spoofed := newFakeRequest()
+	spoofed.Tag = "team-a/daily"
+	if _, err := submitter.Submit(context.TODO(), &RunSubmission{AlgorithmName: "test-algorithm", Request: spoofed}); !errors.Is(err, ErrInvalidSubmission) || !strings.Contains(err.Error(), "Tag team-a/daily is invalid") {
+		t.Errorf("expected a tag naming a tag of another tenant to be rejected, but got %v", err)
+	}
+
+	_, err = submitter.Submit(context.TODO(), &RunSubmission{AlgorithmName: "team-a/test-algorithm", Request: newFakeRequest()})
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("expected a run exceeding the tenant quota to be rejected, but got %v", err)
+	}
+}
+
+func TestActiveRunCounter(t *testing.T) {
+	counter := &activeRunCounter{ttl: time.Hour, counts: map[string]activeRunCount{}}
+	counted := 0
+	count := func(tenant string) (int, error) {
+		counted++
+		return 1, nil
+	}
+
+	if active, release, err := counter.reserve("team-a", 3, count); err != nil || release == nil || active != 1 {
+		t.Errorf("expected a run within the limit to be reserved, but got %d active runs (%v)", active, err)
+		t.FailNow()
+	}
+
+	_, release, _ := counter.reserve("team-a", 3, count)
+	if release == nil || counted != 1 {
+		t.Errorf("expected a cached count to be reused, but active runs were counted %d times", counted)
+		t.FailNow()
+	}
+
+	if active, rejected, _ := counter.reserve("team-a", 3, count); rejected != nil || active != 3 {
+		t.Errorf("expected a run exceeding the limit to be rejected, but got %d active runs", active)
+	}
+
+	// a released run frees a slot until the count is refreshed
+	release()
+	if _, reserved, _ := counter.reserve("team-a", 3, count); reserved == nil {
+		t.Errorf("expected a released run to free a slot of the tenant")
+	}
+
+	counter.counts["team-a"] = activeRunCount{count: 3, expiresAt: time.Now()}
+	if _, reserved, _ := counter.reserve("team-a", 3, count); reserved == nil || counted != 2 {
+		t.Errorf("expected an expired count to be refreshed, but active runs were counted %d times", counted)
+		t.FailNow()
+	}
+
+	// a run reserved before the refresh must not be released from the refreshed count
+	release()
+	if active := counter.counts["team-a"].count; active != 2 {
+		t.Errorf("expected a refreshed count to remain unchanged, but got %d", active)
+	}
+}
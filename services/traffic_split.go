@@ -0,0 +1,135 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	v1 "github.com/SneaksAndData/nexus-core/pkg/apis/science/v1"
+	"github.com/SneaksAndData/nexus-core/pkg/telemetry"
+	"github.com/SneaksAndData/nexus/services/models"
+	"hash/fnv"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// TrafficSplitAnnotation routes a percentage of runs of the annotated template to variant templates, for example [{"template": "my-algorithm-v2", "weight": 10}]. Remaining runs use the annotated template
+	TrafficSplitAnnotation = "science.sneaksanddata.com/traffic-split"
+	// TemplateVariantAnnotation is added to runtime environment annotations of the applied configuration of a run, with the template selected by a traffic split
+	TemplateVariantAnnotation = "science.sneaksanddata.com/template-variant"
+)
+
+// TemplateTrafficSplit returns variants defined by a template, or nil if it has none
+func TemplateTrafficSplit(template *v1.NexusAlgorithmTemplate) ([]*models.TrafficSplitVariant, error) {
+	value, ok := template.Annotations[TrafficSplitAnnotation]
+	if !ok || value == "" {
+		return nil, nil
+	}
+
+	variants := []*models.TrafficSplitVariant{}
+	if err := json.Unmarshal([]byte(value), &variants); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation on template %s, a JSON array is expected: %w", TrafficSplitAnnotation, template.Name, err)
+	}
+
+	total := 0
+	for _, variant := range variants {
+		if variant.Template == "" || variant.Weight <= 0 {
+			return nil, fmt.Errorf("invalid %s annotation on template %s, each variant requires a template and a positive weight", TrafficSplitAnnotation, template.Name)
+		}
+		total += variant.Weight
+	}
+
+	if total > 100 {
+		return nil, fmt.Errorf("invalid %s annotation on template %s, weights add up to %d%%", TrafficSplitAnnotation, template.Name, total)
+	}
+
+	return variants, nil
+}
+
+// SelectVariant picks a variant template for a routing key, or returns an empty string if the run should use the algorithm template.
+// Runs with the same routing key are always routed to the same variant, as long as the split is unchanged
+func SelectVariant(algorithmName string, variants []*models.TrafficSplitVariant, routingKey string) string {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(algorithmName + "/" + routingKey))
+	bucket := int(hash.Sum32() % 100)
+
+	for _, variant := range variants {
+		if bucket < variant.Weight {
+			return variant.Template
+		}
+		bucket -= variant.Weight
+	}
+
+	return ""
+}
+
+// SetTemplateVariant records the template selected for a run by a traffic split
+func (scheduler *RequestScheduler) SetTemplateVariant(requestId string, algorithmName string, variant string) error {
+	attributes := models.NewCheckpointAttributes(algorithmName, requestId)
+	attributes.TemplateVariant = variant
+
+	return scheduler.store.UpsertAttributes(attributes, models.AttributeTemplateVariant)
+}
+
+// selectVariant returns the template a run is created from according to the traffic split of the algorithm template, and the name of the selected template if the algorithm has a traffic split.
+// Runs without a routing key are routed randomly. If a variant template is missing, the run falls back to the algorithm template, so a broken rollout does not reject runs
+func (submitter *RunSubmitter) selectVariant(config *v1.NexusAlgorithmTemplate, routingKey string, requestId string) (*v1.NexusAlgorithmTemplate, string, error) {
+	variants, err := TemplateTrafficSplit(config)
+	if err != nil {
+		return nil, "", submitter.failedSubmission(err, "error when reading traffic split", submitter.configCache.RunName(config), requestId)
+	}
+
+	if len(variants) == 0 {
+		return config, "", nil
+	}
+
+	if routingKey == "" {
+		routingKey = requestId
+	}
+
+	algorithmName := submitter.configCache.RunName(config)
+	selected := config
+	if variantName := SelectVariant(algorithmName, variants, routingKey); variantName != "" {
+		// variants are located in the namespace of the algorithm template
+		variant, err := submitter.configCache.GetAlgorithmConfiguration(submitter.configCache.qualifiedName(config.Namespace, variantName))
+		if err != nil {
+			return nil, "", submitter.failedSubmission(err, "error when retrieving variant template", algorithmName, requestId)
+		}
+
+		if variant != nil {
+			selected = variant
+		} else {
+			submitter.logger.V(0).Info("variant template not found - using algorithm template", "algorithm", algorithmName, "request", requestId, "variant", variantName)
+			submitter.recorder.Eventf(config, corev1.EventTypeWarning, EventReasonMissingVariant, "Request %s used this template: variant template %s not found", requestId, variantName)
+		}
+	}
+
+	variant := submitter.configCache.RunName(selected)
+	telemetry.Increment(submitter.scheduler.metrics, "traffic_split", map[string]string{"algorithm": algorithmName, "variant": variant})
+
+	return annotateAppliedConfiguration(selected, TemplateVariantAnnotation, variant), variant, nil
+}
+
+// annotateAppliedConfiguration returns a copy of a template with an annotation added to runtime environment annotations, which are recorded with the applied configuration of a run and set on its pods
+func annotateAppliedConfiguration(template *v1.NexusAlgorithmTemplate, key string, value string) *v1.NexusAlgorithmTemplate {
+	annotated := template.DeepCopy()
+	if annotated.Spec.RuntimeEnvironment == nil {
+		return annotated
+	}
+
+	if annotated.Spec.RuntimeEnvironment.Annotations == nil {
+		annotated.Spec.RuntimeEnvironment.Annotations = map[string]string{}
+	}
+	annotated.Spec.RuntimeEnvironment.Annotations[key] = value
+
+	return annotated
+}
+
+// appliedTemplateVariant returns the template a run was routed to by a traffic split, or the algorithm template
+func appliedTemplateVariant(algorithmName string, appliedConfiguration *v1.NexusAlgorithmSpec) string {
+	if appliedConfiguration != nil && appliedConfiguration.RuntimeEnvironment != nil {
+		if variant, ok := appliedConfiguration.RuntimeEnvironment.Annotations[TemplateVariantAnnotation]; ok {
+			return variant
+		}
+	}
+
+	return algorithmName
+}
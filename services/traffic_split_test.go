@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	v1 "github.com/SneaksAndData/nexus-core/pkg/apis/science/v1"
+	"github.com/SneaksAndData/nexus/services/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestTemplateTrafficSplit(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+		variants   int
+		valid      bool
+	}{
+		{name: "no split", annotation: "", variants: 0, valid: true},
+		{name: "single variant", annotation: `[{"template": "test-algorithm-v2", "weight": 10}]`, variants: 1, valid: true},
+		{name: "multiple variants", annotation: `[{"template": "a", "weight": 50}, {"template": "b", "weight": 50}]`, variants: 2, valid: true},
+		{name: "not an array", annotation: `{"template": "a"}`, valid: false},
+		{name: "missing template", annotation: `[{"weight": 10}]`, valid: false},
+		{name: "weights above 100", annotation: `[{"template": "a", "weight": 60}, {"template": "b", "weight": 60}]`, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variants, err := TemplateTrafficSplit(&v1.NexusAlgorithmTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-algorithm", Annotations: map[string]string{TrafficSplitAnnotation: tt.annotation}},
+			})
+			if (err == nil) != tt.valid || len(variants) != tt.variants {
+				t.Errorf("expected %d variants and valid=%v, but got %v (%v)", tt.variants, tt.valid, variants, err)
+			}
+		})
+	}
+}
+
+func TestSelectVariant(t *testing.T) {
+	variants := []*models.TrafficSplitVariant{{Template: "test-algorithm-v2", Weight: 20}}
+
+	routed := 0
+	for i := 0; i < 1000; i++ {
+		key := strconv.Itoa(i)
+		selected := SelectVariant("test-algorithm", variants, key)
+		if selected != SelectVariant("test-algorithm", variants, key) {
+			t.Errorf("expected runs with the same routing key to be routed to the same variant")
+		}
+		if selected == "test-algorithm-v2" {
+			routed++
+		}
+	}
+
+	if routed < 150 || routed > 250 {
+		t.Errorf("expected about 20%% of runs to be routed to the variant, but got %d of 1000", routed)
+	}
+
+	if selected := SelectVariant("test-algorithm", []*models.TrafficSplitVariant{{Template: "test-algorithm-v2", Weight: 100}}, "key"); selected != "test-algorithm-v2" {
+		t.Errorf("expected all runs to be routed to a variant with weight 100, but got %s", selected)
+	}
+}
+
+func TestRunSubmitter_TrafficSplit(t *testing.T) {
+	f, submitter := newSubmitterFixture(t, true)
+	if _, err := f.scheduler.Init(f.ctx); err != nil {
+		t.Errorf("scheduler init failed: %s", err)
+		t.FailNow()
+	}
+
+	buffered := make(bufferedRuns, 1)
+	go f.buffer.Start(buffered)
+	time.Sleep(1 * time.Second)
+
+	// cached templates are shared with the submitter, so the split is applied by updating the cache instead of the cached object
+	template, _ := f.scheduler.configCache.GetAlgorithmConfiguration("test-algorithm")
+	primary := template.DeepCopy()
+	primary.Annotations = map[string]string{TrafficSplitAnnotation: `[{"template": "test-algorithm-v2", "weight": 100}]`}
+	_ = f.scheduler.configCache.templateInformer.GetIndexer().Update(primary)
+	variantSpec := newFakeSpec()
+	variantSpec.Container.VersionTag = "v2.0.0"
+	_ = f.scheduler.configCache.templateInformer.GetIndexer().Add(&v1.NexusAlgorithmTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-algorithm-v2", Namespace: "nexus"},
+		Spec:       *variantSpec,
+	})
+
+	requestId, err := submitter.Submit(context.TODO(), &RunSubmission{AlgorithmName: "test-algorithm", Request: newFakeRequest(), RoutingKey: "customer"})
+	if err != nil {
+		t.Errorf("failed to submit a run: %v", err)
+		t.FailNow()
+	}
+
+	// the buffered run is read from the buffer output, so the test does not share the buffer with its actor
+	checkpoint := buffered.wait(t).Checkpoint
+	if checkpoint.Id != requestId || checkpoint.AppliedConfiguration.Container.VersionTag != "v2.0.0" || checkpoint.AppliedConfiguration.RuntimeEnvironment.Annotations[TemplateVariantAnnotation] != "test-algorithm-v2" {
+		t.Errorf("expected a run to be created from the variant template, but got %v", checkpoint)
+	}
+
+	attributes, _ := f.store.ReadAttributes("test-algorithm", requestId)
+	if attributes == nil || attributes.TemplateVariant != "test-algorithm-v2" {
+		t.Errorf("expected the selected variant to be recorded, but got %v", attributes)
+	}
+}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"errors"
+	"github.com/SneaksAndData/nexus/services/models"
+	"github.com/gocql/gocql"
+	"github.com/scylladb/gocqlx/v3/qb"
+)
+
+// AttributeStore persists CheckpointAttributes alongside the checkpoints
+type AttributeStore interface {
+	// UpsertAttributes writes provided attribute columns, or all attribute columns if none are provided. Columns not provided keep their stored values.
+	UpsertAttributes(attributes *models.CheckpointAttributes, columns ...string) error
+	// ReadAttributes returns nil if no attributes have been recorded for the run, for example for runs submitted by older scheduler versions
+	ReadAttributes(algorithm string, id string) (*models.CheckpointAttributes, error)
+	// RecordSchedulingAttempt writes scheduling attempt columns only if the stored attempt count still equals previousAttempts, and returns false otherwise.
+	// Attempt count of a run is not stored until its first attempt is recorded
+	RecordSchedulingAttempt(attributes *models.CheckpointAttributes, previousAttempts int) (bool, error)
+}
+
+func (cqls *CqlStore) UpsertAttributes(attributes *models.CheckpointAttributes, columns ...string) error { // coverage-ignore
+	if len(columns) == 0 {
+		columns = models.CheckpointAttributeColumns
+	}
+
+	// CQL UPDATE creates the row if it does not exist, and only modifies the provided columns
+	var query = cqls.cqlSession.Query(models.CheckpointAttributesTable.Update(columns...)).BindStruct(*attributes)
+	if err := query.ExecRelease(); err != nil {
+		cqls.logger.V(1).Error(err, "error when inserting checkpoint attributes", "algorithm", attributes.Algorithm, "id", attributes.Id)
+		return err
+	}
+
+	return nil
+}
+
+func (cqls *CqlStore) ReadAttributes(algorithm string, id string) (*models.CheckpointAttributes, error) { // coverage-ignore
+	result := models.NewCheckpointAttributes(algorithm, id)
+
+	var query = cqls.cqlSession.Query(models.CheckpointAttributesTable.Get()).BindStruct(*result)
+	if err := query.GetRelease(result); err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, nil
+		}
+
+		cqls.logger.V(1).Error(err, "error when reading checkpoint attributes", "algorithm", algorithm, "id", id)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (cqls *CqlStore) RecordSchedulingAttempt(attributes *models.CheckpointAttributes, previousAttempts int) (bool, error) { // coverage-ignore
+	condition := qb.EqNamed(models.AttributeSchedulingAttempts, "previous_attempts")
+	if previousAttempts == 0 {
+		condition = qb.EqLit(models.AttributeSchedulingAttempts, "null")
+	}
+
+	statement, names := qb.Update(models.CheckpointAttributesTable.Name()).
+		Set(models.AttributeSchedulingAttempts, models.AttributeLastSchedulingError, models.AttributeLastSchedulingAttempt).
+		Where(qb.Eq("algorithm"), qb.Eq("id")).
+		If(condition).
+		ToCql()
+
+	// lightweight transaction prevents concurrent attempts from overwriting each other's count
+	var query = cqls.cqlSession.Query(statement, names).BindStructMap(*attributes, qb.M{"previous_attempts": previousAttempts})
+	applied, err := query.ExecCASRelease()
+	if err != nil {
+		cqls.logger.V(1).Error(err, "error when recording a scheduling attempt", "algorithm", attributes.Algorithm, "id", attributes.Id)
+		return false, err
+	}
+
+	return applied, nil
+}
@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"encoding/json"
+	coremodels "github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
+	"github.com/scylladb/gocqlx/v3"
+	"github.com/scylladb/gocqlx/v3/table"
+	"iter"
+)
+
+// checkpointQueryPageSize limits the number of checkpoints held in memory while a query is iterated
+const checkpointQueryPageSize = 500
+
+// CheckpointQueryStore provides checkpoint queries not supported by the checkpoint buffer
+type CheckpointQueryStore interface {
+	ReadCheckpointsByStage(algorithm string, lifecycleStage string) (iter.Seq2[*coremodels.CheckpointedRequest, error], error)
+	// ReadCheckpointsInStage returns checkpoints of all algorithms in the provided lifecycle stage
+	ReadCheckpointsInStage(lifecycleStage string) (iter.Seq2[*coremodels.CheckpointedRequest, error], error)
+	// ReadChildCheckpoints returns checkpoints of runs submitted with the provided parent
+	ReadChildCheckpoints(parent *coremodels.AlgorithmRequestRef) (iter.Seq2[*coremodels.CheckpointedRequest, error], error)
+}
+
+var checkpointsByAlgorithmAndStage = table.New(table.Metadata{
+	Name:    coremodels.CheckpointedRequestTable.Name(),
+	Columns: coremodels.CheckpointedRequestTable.Metadata().Columns,
+	PartKey: []string{
+		"algorithm",
+		"lifecycle_stage",
+	},
+	SortKey: []string{},
+})
+
+var checkpointsByStage = table.New(table.Metadata{
+	Name:    coremodels.CheckpointedRequestTable.Name(),
+	Columns: coremodels.CheckpointedRequestTable.Metadata().Columns,
+	PartKey: []string{
+		"lifecycle_stage",
+	},
+	SortKey: []string{},
+})
+
+var checkpointsByParent = table.New(table.Metadata{
+	Name:    coremodels.CheckpointedRequestTable.Name(),
+	Columns: coremodels.CheckpointedRequestTable.Metadata().Columns,
+	PartKey: []string{
+		"parent",
+	},
+	SortKey: []string{},
+})
+
+func (cqls *CqlStore) ReadCheckpointsByStage(algorithm string, lifecycleStage string) (iter.Seq2[*coremodels.CheckpointedRequest, error], error) { // coverage-ignore
+	predicate := &coremodels.CheckpointedRequestCqlModel{
+		Algorithm:      algorithm,
+		LifecycleStage: lifecycleStage,
+	}
+	query := cqls.cqlSession.Query(checkpointsByAlgorithmAndStage.GetBuilder().AllowFiltering().ToCql()).BindStruct(*predicate).PageSize(checkpointQueryPageSize)
+
+	return pagedCheckpoints(query, func(err error) {
+		cqls.logger.V(1).Error(err, "error when reading checkpoints by lifecycle stage", "algorithm", algorithm, "lifecycleStage", lifecycleStage)
+	}), nil
+}
+
+func (cqls *CqlStore) ReadCheckpointsInStage(lifecycleStage string) (iter.Seq2[*coremodels.CheckpointedRequest, error], error) { // coverage-ignore
+	predicate := &coremodels.CheckpointedRequestCqlModel{
+		LifecycleStage: lifecycleStage,
+	}
+	query := cqls.cqlSession.Query(checkpointsByStage.Get()).BindStruct(*predicate).PageSize(checkpointQueryPageSize)
+
+	return pagedCheckpoints(query, func(err error) {
+		cqls.logger.V(1).Error(err, "error when reading checkpoints by lifecycle stage", "lifecycleStage", lifecycleStage)
+	}), nil
+}
+
+func (cqls *CqlStore) ReadChildCheckpoints(parent *coremodels.AlgorithmRequestRef) (iter.Seq2[*coremodels.CheckpointedRequest, error], error) { // coverage-ignore
+	// parent reference is stored in the same serialized form as produced by the checkpoint buffer
+	serializedParent, err := json.Marshal(parent)
+	if err != nil {
+		return nil, err
+	}
+
+	predicate := &coremodels.CheckpointedRequestCqlModel{
+		Parent: string(serializedParent),
+	}
+	query := cqls.cqlSession.Query(checkpointsByParent.Get()).BindStruct(*predicate).PageSize(checkpointQueryPageSize)
+
+	return pagedCheckpoints(query, func(err error) {
+		cqls.logger.V(1).Error(err, "error when reading child checkpoints", "parentAlgorithm", parent.AlgorithmName, "parentId", parent.RequestId)
+	}), nil
+}
+
+// pagedCheckpoints iterates query results page by page, so checkpoints are not loaded into memory all at once. Query errors are yielded with a nil checkpoint
+func pagedCheckpoints(query *gocqlx.Queryx, onError func(error)) iter.Seq2[*coremodels.CheckpointedRequest, error] { // coverage-ignore
+	return func(yield func(*coremodels.CheckpointedRequest, error) bool) {
+		defer query.Release()
+
+		results := query.Iter()
+		for {
+			model := &coremodels.CheckpointedRequestCqlModel{}
+			if !results.StructScan(model) {
+				break
+			}
+
+			if !yield(model.FromCqlModel()) {
+				_ = results.Close()
+				return
+			}
+		}
+
+		if err := results.Close(); err != nil {
+			onError(err)
+			yield(nil, err)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"errors"
+	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/request"
+	"github.com/gocql/gocql"
+	"github.com/scylladb/gocqlx/v3"
+	"k8s.io/klog/v2"
+)
+
+// CqlStore provides access to Nexus tables owned by the scheduler. It uses the same connection settings as the checkpoint buffer.
+type CqlStore struct {
+	cqlSession gocqlx.Session
+	logger     klog.Logger
+}
+
+// NewCqlStore creates a generic connected CqlStore (Apache Cassandra/Scylla)
+func NewCqlStore(cluster *gocql.ClusterConfig, logger klog.Logger) *CqlStore { // coverage-ignore
+	session, err := gocqlx.WrapSession(cluster.CreateSession())
+	if err != nil {
+		logger.V(0).Error(err, "failed to create CQL session")
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	}
+
+	return &CqlStore{
+		cqlSession: session,
+		logger:     logger,
+	}
+}
+
+// NewAstraCqlStore creates a CqlStore connected to DataStax AstraDB serverless instance
+func NewAstraCqlStore(logger klog.Logger, bundle *request.AstraBundleConfig) *CqlStore { // coverage-ignore
+	config := request.NewAstraCqlStoreConfig(logger, bundle)
+	cluster := gocql.NewCluster(config.GatewayHost)
+	cluster.Authenticator = gocql.PasswordAuthenticator{
+		Username: config.GatewayUser,
+		Password: config.GatewayPass,
+	}
+	cluster.Hosts = []string{config.GatewayHost + ":" + config.GatewayPort}
+	cluster.SslOpts = &gocql.SslOptions{
+		Config:                 config.TlsConfig,
+		EnableHostVerification: false,
+	}
+	cluster.Consistency = gocql.LocalQuorum
+
+	return NewCqlStore(cluster, logger)
+}
+
+// NewScyllaCqlStore creates a CqlStore connected to a ScyllaDB or Apache Cassandra cluster
+func NewScyllaCqlStore(logger klog.Logger, config *request.ScyllaCqlStoreConfig) *CqlStore { // coverage-ignore
+	cluster := gocql.NewCluster(config.Hosts...)
+	fallback := gocql.RoundRobinHostPolicy()
+	if config.LocalDC != "" {
+		fallback = gocql.DCAwareRoundRobinPolicy(config.LocalDC)
+	}
+
+	cluster.PoolConfig.HostSelectionPolicy = gocql.TokenAwareHostPolicy(fallback)
+	if config.LocalDC != "" {
+		cluster.Consistency = gocql.LocalQuorum
+	}
+
+	if config.Password != "" {
+		cluster.Authenticator = gocql.PasswordAuthenticator{
+			Username: config.User,
+			Password: config.Password,
+		}
+	}
+
+	return NewCqlStore(cluster, logger)
+}
+
+// IsNotFound returns true if the error indicates that a requested row does not exist
+func IsNotFound(err error) bool {
+	return errors.Is(err, gocql.ErrNotFound)
+}
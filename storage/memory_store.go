@@ -0,0 +1,258 @@
+package storage
+
+import (
+	"cmp"
+	coremodels "github.com/SneaksAndData/nexus-core/pkg/checkpoint/models"
+	"github.com/SneaksAndData/nexus-core/pkg/checkpoint/request"
+	"github.com/SneaksAndData/nexus/services/models"
+	"github.com/scylladb/gocqlx/v3"
+	"iter"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps all data in app memory and is ONLY intended to use in tests. DO NOT USE THIS IN PRODUCTION.
+// Checkpoint queries are served from the provided MemoryPassthroughBuffer.
+type MemoryStore struct {
+	attributes map[string]*models.CheckpointAttributes
+	operations map[string]*models.CancellationOperation
+	cache      map[string]*models.ResultCacheEntry
+	revisions  map[string][]*models.TemplateRevision
+	buffer     *request.MemoryPassthroughBuffer
+	lock       sync.RWMutex
+}
+
+// NewMemoryStore creates an empty MemoryStore
+func NewMemoryStore(buffer *request.MemoryPassthroughBuffer) *MemoryStore {
+	return &MemoryStore{
+		attributes: map[string]*models.CheckpointAttributes{},
+		operations: map[string]*models.CancellationOperation{},
+		cache:      map[string]*models.ResultCacheEntry{},
+		revisions:  map[string][]*models.TemplateRevision{},
+		buffer:     buffer,
+	}
+}
+
+func memoryKey(algorithm string, id string) string {
+	return algorithm + "/" + id
+}
+
+func (store *MemoryStore) UpsertAttributes(attributes *models.CheckpointAttributes, columns ...string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	key := memoryKey(attributes.Algorithm, attributes.Id)
+	existing, ok := store.attributes[key]
+	if !ok || len(columns) == 0 {
+		cloned := *attributes
+		store.attributes[key] = &cloned
+		return nil
+	}
+
+	source := reflect.ValueOf(attributes).Elem()
+	target := reflect.ValueOf(existing).Elem()
+	for _, column := range columns {
+		gocqlx.DefaultMapper.FieldByName(target, column).Set(gocqlx.DefaultMapper.FieldByName(source, column))
+	}
+
+	return nil
+}
+
+func (store *MemoryStore) ReadAttributes(algorithm string, id string) (*models.CheckpointAttributes, error) {
+	store.lock.RLock()
+	defer store.lock.RUnlock()
+
+	if attributes, ok := store.attributes[memoryKey(algorithm, id)]; ok {
+		cloned := *attributes
+		return &cloned, nil
+	}
+
+	return nil, nil
+}
+
+func (store *MemoryStore) RecordSchedulingAttempt(attributes *models.CheckpointAttributes, previousAttempts int) (bool, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	key := memoryKey(attributes.Algorithm, attributes.Id)
+	existing, ok := store.attributes[key]
+	if !ok {
+		existing = models.NewCheckpointAttributes(attributes.Algorithm, attributes.Id)
+	}
+
+	if existing.SchedulingAttempts != previousAttempts {
+		return false, nil
+	}
+
+	store.attributes[key] = existing
+	existing.SchedulingAttempts = attributes.SchedulingAttempts
+	existing.LastSchedulingError = attributes.LastSchedulingError
+	existing.LastSchedulingAttempt = attributes.LastSchedulingAttempt
+
+	return true, nil
+}
+
+func (store *MemoryStore) UpsertCancellationOperation(operation *models.CancellationOperation) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	cloned := operation.DeepCopy()
+	cloned.LastModified = time.Now()
+	store.operations[operation.Id] = cloned
+
+	return nil
+}
+
+func (store *MemoryStore) ReadCancellationOperation(id string) (*models.CancellationOperation, error) {
+	store.lock.RLock()
+	defer store.lock.RUnlock()
+
+	if operation, ok := store.operations[id]; ok {
+		return operation.DeepCopy(), nil
+	}
+
+	return nil, nil
+}
+
+func (store *MemoryStore) ReadCancellationOperationsInStatus(status string) ([]*models.CancellationOperation, error) {
+	store.lock.RLock()
+	defer store.lock.RUnlock()
+
+	result := []*models.CancellationOperation{}
+	for _, operation := range store.operations {
+		if operation.Status == status {
+			result = append(result, operation.DeepCopy())
+		}
+	}
+
+	return result, nil
+}
+
+func (store *MemoryStore) UpsertResultCacheEntry(entry *models.ResultCacheEntry, columns ...string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	key := memoryKey(entry.Algorithm, entry.ContentHash)
+	existing, ok := store.cache[key]
+	if !ok {
+		existing = &models.ResultCacheEntry{Algorithm: entry.Algorithm, ContentHash: entry.ContentHash}
+		store.cache[key] = existing
+	}
+
+	source := reflect.ValueOf(entry).Elem()
+	target := reflect.ValueOf(existing).Elem()
+	for _, column := range columns {
+		gocqlx.DefaultMapper.FieldByName(target, column).Set(gocqlx.DefaultMapper.FieldByName(source, column))
+	}
+
+	return nil
+}
+
+func (store *MemoryStore) ReadResultCacheEntry(algorithm string, contentHash string) (*models.ResultCacheEntry, error) {
+	store.lock.RLock()
+	defer store.lock.RUnlock()
+
+	if entry, ok := store.cache[memoryKey(algorithm, contentHash)]; ok {
+		cloned := *entry
+		return &cloned, nil
+	}
+
+	return nil, nil
+}
+
+func (store *MemoryStore) UpsertTemplateRevision(revision *models.TemplateRevision) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	cloned := *revision
+	revisions := slices.DeleteFunc(store.revisions[revision.Algorithm], func(existing *models.TemplateRevision) bool {
+		return existing.Revision == revision.Revision
+	})
+	revisions = append(revisions, &cloned)
+	slices.SortFunc(revisions, func(a, b *models.TemplateRevision) int {
+		return cmp.Compare(b.Revision, a.Revision)
+	})
+	store.revisions[revision.Algorithm] = revisions
+
+	return nil
+}
+
+func (store *MemoryStore) ReadTemplateRevision(algorithm string, revision int64) (*models.TemplateRevision, error) {
+	store.lock.RLock()
+	defer store.lock.RUnlock()
+
+	for _, existing := range store.revisions[algorithm] {
+		if existing.Revision == revision {
+			cloned := *existing
+			return &cloned, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (store *MemoryStore) ReadTemplateRevisions(algorithm string) ([]*models.TemplateRevision, error) {
+	store.lock.RLock()
+	defer store.lock.RUnlock()
+
+	revisions := []*models.TemplateRevision{}
+	for _, existing := range store.revisions[algorithm] {
+		cloned := *existing
+		revisions = append(revisions, &cloned)
+	}
+
+	return revisions, nil
+}
+
+func (store *MemoryStore) ReadCheckpointsByStage(algorithm string, lifecycleStage string) (iter.Seq2[*coremodels.CheckpointedRequest, error], error) {
+	matches := []*coremodels.CheckpointedRequest{}
+	for _, checkpoint := range store.buffer.Checkpoints {
+		if checkpoint.Algorithm == algorithm && checkpoint.LifecycleStage == lifecycleStage {
+			matches = append(matches, checkpoint)
+		}
+	}
+
+	return func(yield func(*coremodels.CheckpointedRequest, error) bool) {
+		for _, checkpoint := range matches {
+			if !yield(checkpoint, nil) {
+				return
+			}
+		}
+	}, nil
+}
+
+func (store *MemoryStore) ReadCheckpointsInStage(lifecycleStage string) (iter.Seq2[*coremodels.CheckpointedRequest, error], error) {
+	matches := []*coremodels.CheckpointedRequest{}
+	for _, checkpoint := range store.buffer.Checkpoints {
+		if checkpoint.LifecycleStage == lifecycleStage {
+			matches = append(matches, checkpoint)
+		}
+	}
+
+	return func(yield func(*coremodels.CheckpointedRequest, error) bool) {
+		for _, checkpoint := range matches {
+			if !yield(checkpoint, nil) {
+				return
+			}
+		}
+	}, nil
+}
+
+func (store *MemoryStore) ReadChildCheckpoints(parent *coremodels.AlgorithmRequestRef) (iter.Seq2[*coremodels.CheckpointedRequest, error], error) {
+	matches := []*coremodels.CheckpointedRequest{}
+	for _, checkpoint := range store.buffer.Checkpoints {
+		if checkpoint.Parent != nil && checkpoint.Parent.RequestId == parent.RequestId && checkpoint.Parent.AlgorithmName == parent.AlgorithmName {
+			matches = append(matches, checkpoint)
+		}
+	}
+
+	return func(yield func(*coremodels.CheckpointedRequest, error) bool) {
+		for _, checkpoint := range matches {
+			if !yield(checkpoint, nil) {
+				return
+			}
+		}
+	}, nil
+}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"errors"
+	"github.com/SneaksAndData/nexus/services/models"
+	"github.com/gocql/gocql"
+	"github.com/scylladb/gocqlx/v3/table"
+	"time"
+)
+
+// OperationStore persists state of long-running operations, such as bulk cancellations, so it can be queried from any scheduler replica
+type OperationStore interface {
+	UpsertCancellationOperation(operation *models.CancellationOperation) error
+	// ReadCancellationOperation returns nil if the operation does not exist
+	ReadCancellationOperation(id string) (*models.CancellationOperation, error)
+	// ReadCancellationOperationsInStatus returns operations in the provided status
+	ReadCancellationOperationsInStatus(status string) ([]*models.CancellationOperation, error)
+}
+
+var cancellationOperationsByStatus = table.New(table.Metadata{
+	Name:    models.CancellationOperationTable.Name(),
+	Columns: models.CancellationOperationTable.Metadata().Columns,
+	PartKey: []string{
+		"status",
+	},
+	SortKey: []string{},
+})
+
+func (cqls *CqlStore) UpsertCancellationOperation(operation *models.CancellationOperation) error { // coverage-ignore
+	cloned := operation.DeepCopy()
+	cloned.LastModified = time.Now()
+
+	var query = cqls.cqlSession.Query(models.CancellationOperationTable.Insert()).BindStruct(*cloned)
+	if err := query.ExecRelease(); err != nil {
+		cqls.logger.V(1).Error(err, "error when inserting a cancellation operation", "id", operation.Id)
+		return err
+	}
+
+	return nil
+}
+
+func (cqls *CqlStore) ReadCancellationOperation(id string) (*models.CancellationOperation, error) { // coverage-ignore
+	result := &models.CancellationOperation{
+		Id: id,
+	}
+
+	var query = cqls.cqlSession.Query(models.CancellationOperationTable.Get()).BindStruct(*result)
+	if err := query.GetRelease(result); err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, nil
+		}
+
+		cqls.logger.V(1).Error(err, "error when reading a cancellation operation", "id", id)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (cqls *CqlStore) ReadCancellationOperationsInStatus(status string) ([]*models.CancellationOperation, error) { // coverage-ignore
+	predicate := &models.CancellationOperation{
+		Status: status,
+	}
+	result := []*models.CancellationOperation{}
+
+	var query = cqls.cqlSession.Query(cancellationOperationsByStatus.Get()).BindStruct(*predicate)
+	if err := query.SelectRelease(&result); err != nil {
+		cqls.logger.V(1).Error(err, "error when reading cancellation operations by status", "status", status)
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"errors"
+	"github.com/SneaksAndData/nexus/services/models"
+	"github.com/gocql/gocql"
+)
+
+// ResultCacheStore persists content hashes of submitted runs, so identical submissions can reuse results of completed runs
+type ResultCacheStore interface {
+	// UpsertResultCacheEntry only modifies the provided columns of an entry
+	UpsertResultCacheEntry(entry *models.ResultCacheEntry, columns ...string) error
+	// ReadResultCacheEntry returns nil if no run has been submitted with the provided content hash
+	ReadResultCacheEntry(algorithm string, contentHash string) (*models.ResultCacheEntry, error)
+}
+
+func (cqls *CqlStore) UpsertResultCacheEntry(entry *models.ResultCacheEntry, columns ...string) error { // coverage-ignore
+	// CQL UPDATE creates the row if it does not exist, and only modifies the provided columns
+	var query = cqls.cqlSession.Query(models.ResultCacheTable.Update(columns...)).BindStruct(*entry)
+	if err := query.ExecRelease(); err != nil {
+		cqls.logger.V(1).Error(err, "error when inserting a result cache entry", "algorithm", entry.Algorithm, "contentHash", entry.ContentHash)
+		return err
+	}
+
+	return nil
+}
+
+func (cqls *CqlStore) ReadResultCacheEntry(algorithm string, contentHash string) (*models.ResultCacheEntry, error) { // coverage-ignore
+	result := &models.ResultCacheEntry{
+		Algorithm:   algorithm,
+		ContentHash: contentHash,
+	}
+
+	var query = cqls.cqlSession.Query(models.ResultCacheTable.Get()).BindStruct(*result)
+	if err := query.GetRelease(result); err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, nil
+		}
+
+		cqls.logger.V(1).Error(err, "error when reading a result cache entry", "algorithm", algorithm, "contentHash", contentHash)
+		return nil, err
+	}
+
+	return result, nil
+}
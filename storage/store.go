@@ -0,0 +1,10 @@
+package storage
+
+// Store combines all storage capabilities required by the scheduler
+type Store interface {
+	AttributeStore
+	OperationStore
+	CheckpointQueryStore
+	ResultCacheStore
+	TemplateRevisionStore
+}
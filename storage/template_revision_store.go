@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"errors"
+	"github.com/SneaksAndData/nexus/services/models"
+	"github.com/gocql/gocql"
+	"github.com/scylladb/gocqlx/v3/qb"
+)
+
+// TemplateRevisionStore persists spec revisions of algorithm templates
+type TemplateRevisionStore interface {
+	UpsertTemplateRevision(revision *models.TemplateRevision) error
+	// ReadTemplateRevision returns nil if the revision has not been recorded
+	ReadTemplateRevision(algorithm string, revision int64) (*models.TemplateRevision, error)
+	// ReadTemplateRevisions returns recorded revisions of a template, latest first
+	ReadTemplateRevisions(algorithm string) ([]*models.TemplateRevision, error)
+}
+
+func (cqls *CqlStore) UpsertTemplateRevision(revision *models.TemplateRevision) error { // coverage-ignore
+	var query = cqls.cqlSession.Query(models.TemplateRevisionsTable.Insert()).BindStruct(*revision)
+	if err := query.ExecRelease(); err != nil {
+		cqls.logger.V(1).Error(err, "error when inserting a template revision", "algorithm", revision.Algorithm, "revision", revision.Revision)
+		return err
+	}
+
+	return nil
+}
+
+func (cqls *CqlStore) ReadTemplateRevision(algorithm string, revision int64) (*models.TemplateRevision, error) { // coverage-ignore
+	result := &models.TemplateRevision{
+		Algorithm: algorithm,
+		Revision:  revision,
+	}
+
+	var query = cqls.cqlSession.Query(models.TemplateRevisionsTable.Get()).BindStruct(*result)
+	if err := query.GetRelease(result); err != nil {
+		if errors.Is(err, gocql.ErrNotFound) {
+			return nil, nil
+		}
+
+		cqls.logger.V(1).Error(err, "error when reading a template revision", "algorithm", algorithm, "revision", revision)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (cqls *CqlStore) ReadTemplateRevisions(algorithm string) ([]*models.TemplateRevision, error) { // coverage-ignore
+	predicate := &models.TemplateRevision{
+		Algorithm: algorithm,
+	}
+	result := []*models.TemplateRevision{}
+
+	// rows are clustered by revision in descending order
+	stmt, names := qb.Select(models.TemplateRevisionsTable.Name()).
+		Columns(models.TemplateRevisionsTable.Metadata().Columns...).
+		Where(qb.Eq("algorithm")).
+		ToCql()
+	var query = cqls.cqlSession.Query(stmt, names).BindStruct(*predicate)
+	if err := query.SelectRelease(&result); err != nil {
+		cqls.logger.V(1).Error(err, "error when reading template revisions", "algorithm", algorithm)
+		return nil, err
+	}
+
+	return result, nil
+}